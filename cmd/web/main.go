@@ -2,9 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -13,8 +20,13 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -26,12 +38,23 @@ import (
 	dbGenerated "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
 	customMiddleware "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/delivery/http/middlewares"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/delivery/http/routes"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/gateways/messaging"
 	grpcServerImpl "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/grpc"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/grpc/interceptors"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/handlers"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/background"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/cache"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/grpc/account"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/health"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/logger"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/resilience"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/tracing"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/services"
 
@@ -48,6 +71,12 @@ func main() {
 		log.Fatalf("FATAL: Gagal memuat konfigurasi: %v", err)
 	}
 
+	// Tracer provider dipasang sebelum koneksi lain dibuat supaya semua client ikut ter-instrumentasi
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	dbCredential := models.Credential{
 		Host:         cfg.Database.Host,
 		Username:     cfg.Database.User,
@@ -86,6 +115,8 @@ func main() {
 	}
 	defer conn.Close()
 
+	metrics.RegisterDBStats(conn, cfg.Database.Name)
+
 	// Init SQLC query
 	sqlcQueries := dbGenerated.New(conn)
 
@@ -96,12 +127,43 @@ func main() {
 	}
 	defer redisClient.Close()
 
-	accountConn := createGrpcConnection(cfg.GRPC.AccountServiceAddress, log)
+	// Semua call ke account service (GetUsers, ValidateToken) adalah lookup idempoten sehingga aman di-retry
+	accountBreaker := resilience.NewCircuitBreaker("account_service", cfg.GRPC.AccountBreakerThreshold, cfg.GRPC.AccountBreakerTimeout)
+	accountConn := createGrpcConnection(cfg.GRPC.AccountServiceAddress, log,
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(resilience.ClientPolicy{
+			Timeout: cfg.GRPC.AccountTimeout,
+			Retry: resilience.RetryPolicy{
+				MaxRetries:     cfg.GRPC.AccountMaxRetries,
+				InitialBackoff: cfg.GRPC.AccountRetryBackoff,
+				MaxBackoff:     cfg.GRPC.AccountRetryMaxBackoff,
+			},
+		}, accountBreaker)),
+	)
 	defer accountConn.Close()
 
 	accountClient := accountpb.NewAccountServiceClient(accountConn)
-	authClient := authpb.NewAuthServiceClient(accountConn)
-	authClientWrapper := account.NewAuthClientFromService(authClient, accountConn)
+
+	// JWT diverifikasi lokal dengan JWT_SECRET dan/atau JWKS; gRPC ValidateToken hanya dipakai jika fallback diaktifkan
+	var keySet *pkg.KeySet
+	if cfg.Auth.JWKSFile != "" || cfg.Auth.JWKSURL != "" {
+		keySet, err = pkg.NewKeySet(ctx, cfg.Auth.JWKSFile, cfg.Auth.JWKSURL, cfg.Auth.JWKSRefreshInterval, log)
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+	}
+
+	jwtVerifier := pkg.NewJWTVerifier(pkg.JWTVerifierConfig{
+		Secret:   cfg.Server.JWTSecret,
+		KeySet:   keySet,
+		Issuer:   cfg.Auth.Issuer,
+		Audience: cfg.Auth.Audience,
+	})
+
+	var authClientWrapper *account.AuthClient
+	if cfg.Auth.GRPCFallback {
+		authClient := authpb.NewAuthServiceClient(accountConn)
+		authClientWrapper = account.NewAuthClientFromService(authClient, accountConn, cfg.Auth.GRPCFallbackTimeout)
+	}
 
 	// Publisher Rabbitmq
 	rabbitMQURL := cfg.RabbitMQ.URL
@@ -118,32 +180,150 @@ func main() {
 	defer rabbitChannel.Close()
 
 	productsRepo := repositories.NewProductRepository(conn, sqlcQueries, log)
-	cartsRepo := repositories.NewCartRepository(redisClient, log)
+	productImportRepo := repositories.NewProductImportRepository(redisClient, log)
+	productAttributeRepo := repositories.NewProductAttributeRepository(sqlcQueries, log)
+	sellerStaffRepo := repositories.NewSellerStaffRepository(sqlcQueries, log)
+	productChangeRepo := repositories.NewProductChangeRepository(sqlcQueries, log)
+	cartsRepo := createCartRepository(&cfg.Cart, conn, sqlcQueries, redisClient, log)
+	sellerNameRepo := repositories.NewSellerNameRepository(redisClient, log)
 	validate := validator.New()
-	productService := services.NewProductService(productsRepo, redisClient, validate, log)
-	cartService := services.NewCartService(cartsRepo, productService, redisClient, accountClient, log)
-	handler := handlers.NewHandler(productService, cartService, log)
-	authMiddleware := customMiddleware.AuthMiddleware(authClientWrapper, log)
+	backgroundTasks := background.NewTracker()
+	productPolicy := policies.NewProductPolicy(sellerStaffRepo, log)
+	productCache := cache.New(redisClient, &cfg.Cache, log)
+	backgroundTasks.GoWorker(productCache.Subscribe)
+	trendingRepo := repositories.NewTrendingRepository(redisClient, services.TrendingRetention, 3*cfg.Trending.CompactInterval, cfg.Trending.MaxItems, log)
+	productService := services.NewProductService(productsRepo, productImportRepo, productAttributeRepo, trendingRepo, productPolicy, productCache, validate, backgroundTasks, log)
+	if cfg.Cache.WarmupOnStart {
+		backgroundTasks.Go(func(ctx context.Context) {
+			if _, err := productService.WarmProductCaches(ctx, entities.CacheWarmTarget{TopN: cfg.Cache.WarmupTopN}); err != nil {
+				log.WithError(err).Warn("Startup cache warm-up failed")
+			}
+		})
+	}
+	trendingService := services.NewTrendingService(trendingRepo, productService, log)
+	backgroundTasks.GoWorker(func(ctx context.Context) {
+		trendingService.RunCompaction(ctx, cfg.Trending.CompactInterval)
+	})
+	coPurchaseRepo := repositories.NewCoPurchaseRepository(redisClient, cfg.Related.MaxCoPurchases, log)
+	relatedService := services.NewRelatedService(coPurchaseRepo, productService, productCache, cfg.Related.CacheTTL, log)
+
+	reviewRepo := repositories.NewReviewRepository(conn, sqlcQueries, log)
+	reviewService := services.NewReviewService(reviewRepo, productService, productPolicy, log)
+	questionRepo := repositories.NewQuestionRepository(sqlcQueries, log)
+	questionService := services.NewQuestionService(questionRepo, productService, productPolicy, log)
+
+	// Pembelian dicatat dulu untuk verified purchase; keduanya aman diulang saat event dikirim ulang
+	handleOrderCreated := func(ctx context.Context, event models.OrderCreatedEvent) error {
+		if err := reviewService.HandleOrderCreated(ctx, event); err != nil {
+			return err
+		}
+		return relatedService.HandleOrderCreated(ctx, event)
+	}
+
+	// Consumer memakai channel sendiri supaya tidak berbagi channel dengan publisher
+	consumerChannel, err := rabbitConn.Channel()
+	if err != nil {
+		log.Fatalf("Failed to open a consumer channel: %v", err)
+	}
+	defer consumerChannel.Close()
+	orderConsumer, err := messaging.NewOrderEventConsumer(consumerChannel, cfg.RabbitMQ.OrderQueue, log)
+	if err != nil {
+		log.Fatalf("Failed to set up order event consumer: %v", err)
+	}
+	backgroundTasks.GoWorker(func(ctx context.Context) {
+		if err := orderConsumer.Consume(ctx, handleOrderCreated); err != nil && ctx.Err() == nil {
+			log.WithError(err).Error("Order event consumer stopped")
+		}
+	})
+
+	cartService := services.NewCartService(cartsRepo, sellerNameRepo, productService, trendingService, redisClient, accountClient, log)
+	sellerStaffService := services.NewSellerStaffService(sellerStaffRepo, log)
+	recentlyViewedRepo := repositories.NewRecentlyViewedRepository(redisClient, cfg.RecentlyViewed.Limit, cfg.RecentlyViewed.TTL, cfg.RecentlyViewed.GuestTTL, log)
+	recentlyViewedService := services.NewRecentlyViewedService(recentlyViewedRepo, productService, cfg.RecentlyViewed.Limit, log)
+	handler := handlers.NewHandler(productService, cartService, sellerStaffService, recentlyViewedService, trendingService, relatedService, reviewService, questionService, log)
+	authTokenRepo := repositories.NewAuthTokenRepository(redisClient, cfg.Auth.BlacklistPrefix, log)
+	authService := services.NewAuthService(jwtVerifier, authTokenRepo, authClientWrapper, log)
+	authMiddleware := customMiddleware.AuthMiddleware(authService, log)
+	optionalAuthMiddleware := customMiddleware.OptionalAuthMiddleware(authService, log)
 
 	lis, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
 	if err != nil {
 		log.Fatalf("Failed to listen for gRPC server: %v", err)
 	}
-	s := grpc.NewServer()
+	serviceAuthCfg := interceptors.ServiceAuthConfig{
+		Mode:            cfg.GRPCServer.AuthMode,
+		Secrets:         cfg.GRPCServer.ServiceSecrets,
+		AllowedServices: cfg.GRPCServer.AllowedServices,
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			interceptors.RequestID(),
+			interceptors.Metrics(),
+			interceptors.Logging(log),
+			interceptors.Recovery(log),
+			interceptors.ServiceAuth(serviceAuthCfg, log),
+			interceptors.MethodAuthorization(map[string][]string{
+				"DecreaseStock": cfg.GRPCServer.StockServices,
+				"IncreaseStock": cfg.GRPCServer.StockServices,
+			}),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptors.StreamRequestID(),
+			interceptors.StreamMetrics(),
+			interceptors.StreamLogging(log),
+			interceptors.StreamRecovery(log),
+			interceptors.StreamServiceAuth(serviceAuthCfg, log),
+		),
+	}
+	if cfg.GRPCServer.AuthMode == interceptors.AuthModeMTLS {
+		serverOpts = append(serverOpts, grpc.Creds(createGrpcServerCredentials(&cfg.GRPCServer, log)))
+	} else if len(cfg.GRPCServer.ServiceSecrets) == 0 {
+		log.Warn("GRPC_SERVICE_SECRETS is empty, every gRPC call will be rejected")
+	}
+	s := grpc.NewServer(serverOpts...)
 
-	productServer := grpcServerImpl.NewProductServer(productService)
+	productChangeService := services.NewProductChangeService(productChangeRepo, cfg.GRPCServer.WatchPollInterval, cfg.GRPCServer.WatchRetention, log)
+	backgroundTasks.GoWorker(func(ctx context.Context) {
+		productChangeService.RunRetention(ctx, cfg.GRPCServer.WatchPruneInterval)
+	})
+	productServer := grpcServerImpl.NewProductServer(productService, trendingService, log)
 	productpb.RegisterProductServiceServer(s, productServer)
 	reflection.Register(s)
 
+	// Readiness dipakai bersama oleh /readyz dan gRPC health service
+	healthChecker := health.NewChecker(cfg.Server.ReadinessCheckTimeout, log)
+	healthChecker.Register("postgres", conn.PingContext)
+	healthChecker.Register("redis", func(ctx context.Context) error {
+		return redisClient.Client.Ping(ctx).Err()
+	})
+	healthChecker.Register("rabbitmq", func(ctx context.Context) error {
+		if rabbitConn.IsClosed() {
+			return amqp.ErrClosed
+		}
+		return nil
+	})
+	healthChecker.Register("account_service", health.GRPCConnCheck(accountConn))
+
+	grpcHealthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(s, grpcHealthServer)
+
+	backgroundTasks.GoWorker(func(ctx context.Context) {
+		healthChecker.SyncGRPC(ctx, grpcHealthServer, 10*time.Second)
+	})
+
+	serverErrors := make(chan error, 2)
 	go func() {
 		if err := s.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve gRPC server: %v", err)
+			serverErrors <- fmt.Errorf("gRPC server: %w", err)
 		}
 	}()
 
 	// Setup Echo (REST API)
 	e := echo.New()
 	e.Use(middleware.RequestID())
+	e.Use(otelecho.Middleware(cfg.Tracing.ServiceName))
+	e.Use(customMiddleware.MetricsMiddleware())
 	e.Use(customMiddleware.LoggingMiddleware(log))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{
@@ -151,17 +331,111 @@ func main() {
 			"http://localhost:5173",
 			"http://72.61.142.248",
 		},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "If-Match", customMiddleware.GuestTokenHeader},
+		ExposeHeaders: []string{"ETag", customMiddleware.GuestTokenHeader},
 	}))
 
-	routes.InitRoutes(e, handler, authMiddleware)
+	routes.InitRoutes(e, handler, authMiddleware, optionalAuthMiddleware)
+	routes.InitHealthRoutes(e, handlers.NewHealthHandler(healthChecker))
+	routes.InitMetricsRoutes(e, metrics.Handler())
+
+	go func() {
+		if err := e.Start(":" + cfg.Server.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	select {
+	case <-signalCtx.Done():
+		log.Info("Shutdown signal received, draining servers...")
+	case err := <-serverErrors:
+		log.WithError(err).Error("Server stopped unexpectedly, shutting down...")
+	}
+
+	// Readiness gagal lebih dulu supaya trafik baru berhenti masuk selama drain
+	healthChecker.SetDraining()
+	grpcHealthServer.Shutdown()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancelShutdown()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Warn("HTTP server did not shut down cleanly")
+	}
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		log.Warn("gRPC graceful stop timed out, forcing stop")
+		s.Stop()
+	}
+
+	// Worker dan task background dihentikan sebelum koneksi DB, Redis dan RabbitMQ ditutup oleh defer di atas
+	if err := backgroundTasks.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Background tasks did not finish before shutdown deadline")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Failed to flush pending spans")
+	}
+
+	log.Info("Shutdown complete")
+}
+
+func createCartRepository(cfg *configs.CartConfig, conn *sql.DB, q *dbGenerated.Queries, redisClient *redis.RedisClient, log *logrus.Logger) repositories.CartRepository {
+	switch cfg.Store {
+	case configs.CartStoreRedis:
+		return repositories.NewCartRepository(redisClient, log)
+	case configs.CartStorePostgres:
+		return repositories.NewPostgresCartRepository(conn, q, log)
+	case configs.CartStoreLayered:
+		return repositories.NewLayeredCartRepository(conn, q, redisClient, cfg.CacheTTL, log)
+	}
+
+	log.Fatalf("Unknown CART_STORE '%s', expected redis, postgres or layered", cfg.Store)
+	return nil
+}
+
+func createGrpcServerCredentials(cfg *configs.GrpcServerConfig, log *logrus.Logger) credentials.TransportCredentials {
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		log.Fatalf("Failed to load gRPC server certificate: %v", err)
+	}
+
+	caPEM, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		log.Fatalf("Failed to read gRPC client CA: %v", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		log.Fatalf("No valid certificates found in %s", cfg.TLSClientCAFile)
+	}
 
-	e.Logger.Fatal(e.Start(":" + cfg.Server.Port))
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})
 }
 
-func createGrpcConnection(url string, log *logrus.Logger) *grpc.ClientConn {
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func createGrpcConnection(url string, log *logrus.Logger, opts ...grpc.DialOption) *grpc.ClientConn {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}, opts...)
+
+	conn, err := grpc.NewClient(url, opts...)
 	if err != nil {
 		log.Fatalf("Failed to create gRPC client connection to %s: %v", url, err)
 	}
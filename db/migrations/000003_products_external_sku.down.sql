@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_seller_external_sku;
+
+ALTER TABLE products DROP COLUMN IF EXISTS external_sku;
@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN external_sku TEXT;
+
+CREATE UNIQUE INDEX idx_products_seller_external_sku ON products (seller_id, external_sku);
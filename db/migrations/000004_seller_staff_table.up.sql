@@ -0,0 +1,8 @@
+CREATE TABLE seller_staff (
+    seller_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (seller_id, user_id)
+);
+
+CREATE INDEX idx_seller_staff_user_id ON seller_staff (user_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_products_record_change ON products;
+DROP FUNCTION IF EXISTS record_product_change();
+DROP TABLE IF EXISTS product_changes;
@@ -0,0 +1,3 @@
+ALTER TABLE carts ADD COLUMN checked BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX idx_carts_user_product ON carts (user_id, product_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS cart_seller_notes;
+
+ALTER TABLE carts
+    DROP COLUMN IF EXISTS gift_wrap,
+    DROP COLUMN IF EXISTS gift_message,
+    DROP COLUMN IF EXISTS variant_note;
@@ -0,0 +1,12 @@
+ALTER TABLE carts
+    ADD COLUMN gift_wrap BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN gift_message TEXT,
+    ADD COLUMN variant_note TEXT;
+
+CREATE TABLE cart_seller_notes (
+    user_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    note TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, seller_id)
+);
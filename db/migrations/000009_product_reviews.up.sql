@@ -0,0 +1,83 @@
+ALTER TABLE products
+    ADD COLUMN rating_avg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+
+-- Listing bisa diurutkan dan difilter berdasarkan rating tanpa menghitung ulang dari tabel review
+CREATE INDEX idx_products_rating ON products (rating_avg DESC, rating_count DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE product_reviews (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    verified_purchase BOOLEAN NOT NULL DEFAULT FALSE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    seller_reply TEXT,
+    seller_replied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_product_reviews_product_user ON product_reviews (product_id, user_id);
+CREATE INDEX idx_product_reviews_status ON product_reviews (status, created_at);
+
+-- Diisi dari event OrderCreated; dipakai untuk menandai review sebagai verified purchase
+CREATE TABLE user_purchases (
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    order_id TEXT NOT NULL,
+    purchased_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, product_id)
+);
+
+-- Perubahan yang hanya menyentuh agregat rating tidak dicatat di feed perubahan produk
+CREATE OR REPLACE FUNCTION record_product_change() RETURNS TRIGGER AS $$
+DECLARE
+    row_data products%ROWTYPE;
+    kind TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        row_data := NEW;
+        kind := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        row_data := OLD;
+        kind := 'deleted';
+    ELSE
+        IF (to_jsonb(NEW) - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'rating_avg' - 'rating_count') THEN
+            RETURN NULL;
+        END IF;
+
+        row_data := NEW;
+        IF NEW.stock IS DISTINCT FROM OLD.stock
+            AND (to_jsonb(NEW) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') THEN
+            kind := 'stock_changed';
+        ELSE
+            kind := 'updated';
+        END IF;
+    END IF;
+
+    INSERT INTO product_changes (product_id, seller_id, change_type, product)
+    VALUES (
+        row_data.id,
+        row_data.seller_id,
+        kind,
+        jsonb_build_object(
+            'id', row_data.id,
+            'seller_id', row_data.seller_id,
+            'name', row_data.name,
+            'price', row_data.price,
+            'stock', row_data.stock,
+            'discount', COALESCE(row_data.discount, 0),
+            'type', COALESCE(row_data."type", ''),
+            'description', COALESCE(row_data."description", ''),
+            'external_sku', COALESCE(row_data.external_sku, ''),
+            'created_at', row_data.created_at AT TIME ZONE 'UTC',
+            'updated_at', row_data.updated_at AT TIME ZONE 'UTC'
+        )
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_question_flags;
+DROP TABLE IF EXISTS product_question_votes;
+DROP TABLE IF EXISTS product_questions;
@@ -0,0 +1,35 @@
+CREATE TABLE product_questions (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    answer TEXT,
+    answered_by UUID,
+    answered_at TIMESTAMPTZ,
+    upvotes INTEGER NOT NULL DEFAULT 0,
+    flag_count INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_questions_product ON product_questions (product_id, created_at DESC);
+-- Inbox seller hanya membaca pertanyaan yang belum dijawab
+CREATE INDEX idx_product_questions_unanswered ON product_questions (product_id, created_at) WHERE answer IS NULL;
+CREATE INDEX idx_product_questions_flagged ON product_questions (flag_count DESC) WHERE flag_count > 0;
+
+-- Satu user hanya bisa upvote dan flag satu pertanyaan satu kali
+CREATE TABLE product_question_votes (
+    question_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, user_id)
+);
+
+CREATE TABLE product_question_flags (
+    question_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, user_id)
+);
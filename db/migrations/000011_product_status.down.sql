@@ -0,0 +1,55 @@
+CREATE OR REPLACE FUNCTION record_product_change() RETURNS TRIGGER AS $$
+DECLARE
+    row_data products%ROWTYPE;
+    kind TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        row_data := NEW;
+        kind := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        row_data := OLD;
+        kind := 'deleted';
+    ELSE
+        IF (to_jsonb(NEW) - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'rating_avg' - 'rating_count') THEN
+            RETURN NULL;
+        END IF;
+
+        row_data := NEW;
+        IF NEW.stock IS DISTINCT FROM OLD.stock
+            AND (to_jsonb(NEW) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') THEN
+            kind := 'stock_changed';
+        ELSE
+            kind := 'updated';
+        END IF;
+    END IF;
+
+    INSERT INTO product_changes (product_id, seller_id, change_type, product)
+    VALUES (
+        row_data.id,
+        row_data.seller_id,
+        kind,
+        jsonb_build_object(
+            'id', row_data.id,
+            'seller_id', row_data.seller_id,
+            'name', row_data.name,
+            'price', row_data.price,
+            'stock', row_data.stock,
+            'discount', COALESCE(row_data.discount, 0),
+            'type', COALESCE(row_data."type", ''),
+            'description', COALESCE(row_data."description", ''),
+            'external_sku', COALESCE(row_data.external_sku, ''),
+            'created_at', row_data.created_at AT TIME ZONE 'UTC',
+            'updated_at', row_data.updated_at AT TIME ZONE 'UTC'
+        )
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_products_status;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status_changed_at;
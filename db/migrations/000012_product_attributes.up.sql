@@ -0,0 +1,69 @@
+-- Spesifikasi terstruktur produk (brand, berat, dimensi, ...) divalidasi terhadap definisi per type
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- jsonb_path_ops mendukung filter @> dan @@ (jsonpath) yang dipakai query atribut
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes jsonb_path_ops) WHERE deleted_at IS NULL;
+
+CREATE TABLE product_attribute_definitions (
+    product_type TEXT NOT NULL,
+    key TEXT NOT NULL CHECK (key ~ '^[a-z][a-z0-9_]{0,63}$'),
+    data_type TEXT NOT NULL CHECK (data_type IN ('string', 'number', 'boolean')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    unit TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_type, key)
+);
+
+-- Atribut ikut dikirim di feed perubahan
+CREATE OR REPLACE FUNCTION record_product_change() RETURNS TRIGGER AS $$
+DECLARE
+    row_data products%ROWTYPE;
+    kind TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        row_data := NEW;
+        kind := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        row_data := OLD;
+        kind := 'deleted';
+    ELSE
+        IF (to_jsonb(NEW) - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'rating_avg' - 'rating_count') THEN
+            RETURN NULL;
+        END IF;
+
+        row_data := NEW;
+        IF NEW.stock IS DISTINCT FROM OLD.stock
+            AND (to_jsonb(NEW) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') = (to_jsonb(OLD) - 'stock' - 'updated_at' - 'rating_avg' - 'rating_count') THEN
+            kind := 'stock_changed';
+        ELSE
+            kind := 'updated';
+        END IF;
+    END IF;
+
+    INSERT INTO product_changes (product_id, seller_id, change_type, product)
+    VALUES (
+        row_data.id,
+        row_data.seller_id,
+        kind,
+        jsonb_build_object(
+            'id', row_data.id,
+            'seller_id', row_data.seller_id,
+            'name', row_data.name,
+            'price', row_data.price,
+            'stock', row_data.stock,
+            'discount', COALESCE(row_data.discount, 0),
+            'type', COALESCE(row_data."type", ''),
+            'description', COALESCE(row_data."description", ''),
+            'external_sku', COALESCE(row_data.external_sku, ''),
+            'status', row_data.status,
+            'attributes', row_data.attributes,
+            'created_at', row_data.created_at AT TIME ZONE 'UTC',
+            'updated_at', row_data.updated_at AT TIME ZONE 'UTC'
+        )
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
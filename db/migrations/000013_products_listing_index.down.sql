@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_products_active_created_at_id;
@@ -0,0 +1,2 @@
+-- Keyset pagination ListProducts gRPC: WHERE (created_at, id) > cursor ORDER BY created_at, id
+CREATE INDEX idx_products_active_created_at_id ON products (created_at, id) WHERE deleted_at IS NULL AND status = 'active';
@@ -0,0 +1,2 @@
+-- Job retensi menghapus change log berdasarkan umur
+CREATE INDEX idx_product_changes_created_at ON product_changes (created_at);
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
 )
 
 func Connect(ctx context.Context, credential *models.Credential) (*sql.DB, error) {
@@ -20,7 +23,8 @@ func Connect(ctx context.Context, credential *models.Credential) (*sql.DB, error
 		credential.Port,
 	)
 
-	db, err := sql.Open("postgres", dsn)
+	// Setiap query sqlc otomatis mendapat span lewat driver yang dibungkus otelsql
+	db, err := otelsql.Open("postgres", dsn, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open connection: %w", err)
 	}
@@ -0,0 +1,52 @@
+-- name: UpsertCartItem :one
+INSERT INTO carts (id, user_id, product_id, quantity, "description", checked, gift_wrap, gift_message, variant_note, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+ON CONFLICT (user_id, product_id) DO UPDATE SET
+  quantity = EXCLUDED.quantity,
+  "description" = EXCLUDED."description",
+  checked = EXCLUDED.checked,
+  gift_wrap = EXCLUDED.gift_wrap,
+  gift_message = EXCLUDED.gift_message,
+  variant_note = EXCLUDED.variant_note,
+  updated_at = NOW()
+RETURNING *;
+
+-- name: GetCartItemsByUserID :many
+SELECT * FROM carts WHERE user_id = $1 ORDER BY created_at;
+
+-- name: UpdateCartItem :one
+UPDATE carts
+SET quantity = $3,
+  "description" = $4,
+  gift_wrap = $5,
+  gift_message = $6,
+  variant_note = $7,
+  updated_at = NOW()
+WHERE user_id = $1 AND product_id = $2
+RETURNING *;
+
+-- name: DeleteCartItem :exec
+DELETE FROM carts WHERE user_id = $1 AND product_id = $2;
+
+-- name: GetCartSellerNotes :many
+SELECT * FROM cart_seller_notes WHERE user_id = $1;
+
+-- name: UpsertCartSellerNote :exec
+INSERT INTO cart_seller_notes (user_id, seller_id, note)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, seller_id) DO UPDATE SET note = EXCLUDED.note, updated_at = NOW();
+
+-- name: DeleteCartSellerNote :exec
+DELETE FROM cart_seller_notes WHERE user_id = $1 AND seller_id = $2;
+
+-- name: EnsureCartVersion :exec
+INSERT INTO cart_versions (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING;
+
+-- name: LockCartVersion :one
+SELECT version FROM cart_versions WHERE user_id = $1 FOR UPDATE;
+
+-- name: IncrementCartVersion :one
+UPDATE cart_versions SET version = version + 1, updated_at = NOW() WHERE user_id = $1 RETURNING version;
+
+-- name: GetCartVersion :one
+SELECT version FROM cart_versions WHERE user_id = $1;
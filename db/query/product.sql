@@ -8,10 +8,13 @@ INSERT INTO products (
   discount, 
   "type", 
   "description", 
+  external_sku,
+  status,
+  attributes,
   created_at, 
   updated_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW()
 ) RETURNING *;
 
 -- name: GetAllProducts :many
@@ -24,10 +27,47 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE deleted_at IS NULL;
+WHERE deleted_at IS NULL AND status = 'active';
+
+-- name: ListActiveProductsPage :many
+-- Keyset pagination berdasarkan (created_at, id); cursor kosong berarti halaman pertama
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL AND status = 'active'
+  AND (
+    sqlc.narg(after_created_at)::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountActiveProducts :one
+SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND status = 'active';
 
 -- name: GetProductByID :one
 SELECT 
@@ -39,6 +79,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -54,6 +100,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -69,6 +121,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -84,10 +142,16 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE name ILIKE $1 AND deleted_at IS NULL;
+WHERE name ILIKE $1 AND deleted_at IS NULL AND status = 'active';
 
 -- name: GetProductsByType :many
 SELECT 
@@ -99,14 +163,20 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE "type" = $1 AND deleted_at IS NULL;
+WHERE "type" = $1 AND deleted_at IS NULL AND status = 'active';
 
 -- name: UpdateProduct :one
 UPDATE products
-SET name = $2, price = $3, stock = $4, discount = $5, type = $6, description = $7, updated_at = NOW()
+SET name = $2, price = $3, stock = $4, discount = $5, type = $6, description = $7, attributes = $9, updated_at = NOW()
 WHERE id = $1 AND seller_id = $8
 RETURNING *;
 
@@ -137,3 +207,171 @@ SET
 WHERE
     id = sqlc.arg(product_id)
 RETURNING *;
+
+-- name: GetProductAttributesBySKU :one
+SELECT attributes FROM products
+WHERE seller_id = $1 AND external_sku = $2;
+
+-- name: UpsertProductBySKU :one
+-- attributes sudah divalidasi service terhadap type baris import (atribut lama dipakai jika baris tidak membawanya)
+INSERT INTO products (
+  id,
+  seller_id,
+  external_sku,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  attributes,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW()
+)
+ON CONFLICT (seller_id, external_sku) DO UPDATE
+SET
+  "name" = EXCLUDED."name",
+  price = EXCLUDED.price,
+  stock = EXCLUDED.stock,
+  discount = EXCLUDED.discount,
+  "type" = EXCLUDED."type",
+  "description" = EXCLUDED."description",
+  attributes = EXCLUDED.attributes,
+  updated_at = NOW()
+RETURNING *;
+
+-- name: LockProductsByIDs :many
+SELECT * FROM products
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL
+ORDER BY id -- urutan lock konsisten supaya transaksi stok paralel tidak deadlock
+FOR UPDATE;
+
+-- name: LockProductsByFilter :many
+SELECT * FROM products
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(seller_id)::uuid IS NULL OR seller_id = sqlc.narg(seller_id))
+  AND (sqlc.narg(product_type)::text IS NULL OR "type" = sqlc.narg(product_type))
+ORDER BY id -- urutan lock sama dengan LockProductsByIDs
+LIMIT sqlc.arg(row_limit)
+FOR UPDATE;
+
+-- name: BulkAdjustProductPrice :many
+UPDATE products
+SET
+    price = GREATEST(ROUND(price * (1 + sqlc.arg(percent)::float8 / 100)), 1)::int,
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+RETURNING *;
+
+-- name: BulkSetProductDiscount :many
+UPDATE products
+SET discount = sqlc.arg(discount), updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+RETURNING *;
+
+-- name: BulkSetProductType :many
+UPDATE products
+SET "type" = sqlc.arg(product_type), updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+RETURNING *;
+
+-- name: BulkDeleteProducts :many
+DELETE FROM products
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+RETURNING *;
+
+-- name: GetRecentlyUpdatedProducts :many
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+ORDER BY updated_at DESC
+LIMIT $1;
+
+-- name: TransitionProductStatus :one
+-- Hanya berhasil jika status belum diubah oleh request lain sejak dibaca
+UPDATE products
+SET
+  status = sqlc.arg(to_status),
+  status_reason = sqlc.narg(reason),
+  status_changed_at = NOW(),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SearchProducts :many
+-- attribute_filter adalah predikat jsonpath yang dibangun service dari filter attr.*; didukung index GIN jsonb_path_ops
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+  AND (sqlc.arg(include_inactive)::bool OR status = 'active')
+  AND (sqlc.narg(name_pattern)::text IS NULL OR "name" ILIKE sqlc.narg(name_pattern))
+  AND (sqlc.narg(product_type)::text IS NULL OR "type" = sqlc.narg(product_type))
+  AND (sqlc.narg(seller_id)::uuid IS NULL OR seller_id = sqlc.narg(seller_id))
+  AND (sqlc.narg(attribute_filter)::jsonpath IS NULL OR attributes @@ sqlc.narg(attribute_filter)::jsonpath)
+  AND (sqlc.narg(min_rating)::float8 IS NULL OR rating_avg >= sqlc.narg(min_rating))
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: SearchProductsByRating :many
+-- Filter sama dengan SearchProducts, diurutkan mengikuti idx_products_rating; id menjaga urutan halaman tetap stabil
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+  AND (sqlc.arg(include_inactive)::bool OR status = 'active')
+  AND (sqlc.narg(name_pattern)::text IS NULL OR "name" ILIKE sqlc.narg(name_pattern))
+  AND (sqlc.narg(product_type)::text IS NULL OR "type" = sqlc.narg(product_type))
+  AND (sqlc.narg(seller_id)::uuid IS NULL OR seller_id = sqlc.narg(seller_id))
+  AND (sqlc.narg(attribute_filter)::jsonpath IS NULL OR attributes @@ sqlc.narg(attribute_filter)::jsonpath)
+  AND (sqlc.narg(min_rating)::float8 IS NULL OR rating_avg >= sqlc.narg(min_rating))
+ORDER BY rating_avg DESC, rating_count DESC, id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
@@ -0,0 +1,28 @@
+-- name: GetAttributeDefinitionsByType :many
+SELECT * FROM product_attribute_definitions
+WHERE product_type = $1
+ORDER BY key;
+
+-- name: UpsertAttributeDefinition :one
+INSERT INTO product_attribute_definitions (
+  product_type,
+  key,
+  data_type,
+  required,
+  allowed_values,
+  unit
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (product_type, key) DO UPDATE
+SET
+  data_type = EXCLUDED.data_type,
+  required = EXCLUDED.required,
+  allowed_values = EXCLUDED.allowed_values,
+  unit = EXCLUDED.unit,
+  updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteAttributeDefinition :execrows
+DELETE FROM product_attribute_definitions
+WHERE product_type = $1 AND key = $2;
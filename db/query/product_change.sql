@@ -0,0 +1,22 @@
+-- name: ListProductChangesAfter :many
+SELECT * FROM product_changes
+WHERE (tx_id, id) > (sqlc.arg(after_tx_id)::bigint, sqlc.arg(after_id)::bigint)
+    AND tx_id < txid_snapshot_xmin(txid_current_snapshot())
+    AND (sqlc.narg(seller_id)::uuid IS NULL OR seller_id = sqlc.narg(seller_id)::uuid)
+    AND (cardinality(sqlc.arg(product_ids)::uuid[]) = 0 OR product_id = ANY(sqlc.arg(product_ids)::uuid[]))
+ORDER BY tx_id, id
+LIMIT sqlc.arg(max_rows);
+
+-- name: GetProductChangeWatermark :one
+SELECT txid_snapshot_xmin(txid_current_snapshot())::bigint;
+
+-- name: DeleteExpiredProductChanges :execrows
+-- Hanya baris dari transaksi yang sudah final (di bawah watermark) yang dihapus, per batch supaya lock tidak lama
+DELETE FROM product_changes
+WHERE id IN (
+    SELECT pc.id FROM product_changes pc
+    WHERE pc.created_at < sqlc.arg(cutoff)
+        AND pc.tx_id < txid_snapshot_xmin(txid_current_snapshot())
+    ORDER BY pc.created_at
+    LIMIT sqlc.arg(max_rows)
+);
@@ -0,0 +1,76 @@
+-- name: InsertProductQuestion :one
+INSERT INTO product_questions (id, product_id, user_id, body)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetProductQuestionByID :one
+SELECT * FROM product_questions WHERE id = $1;
+
+-- name: GetProductQuestionsByProductID :many
+SELECT * FROM product_questions
+WHERE product_id = sqlc.arg(product_id) AND status = 'visible'
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by_upvotes)::bool THEN upvotes END DESC,
+  created_at DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetUnansweredQuestionsBySellerID :many
+SELECT q.* FROM product_questions q
+JOIN products p ON p.id = q.product_id
+WHERE p.seller_id = sqlc.arg(seller_id)
+  AND p.deleted_at IS NULL
+  AND q.answer IS NULL
+  AND q.status = 'visible'
+ORDER BY q.created_at
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetFlaggedProductQuestions :many
+SELECT * FROM product_questions
+WHERE flag_count > 0 AND status = sqlc.arg(status)
+ORDER BY flag_count DESC, created_at
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: AnswerProductQuestion :one
+UPDATE product_questions
+SET answer = $2, answered_by = $3, answered_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetProductQuestionStatus :one
+UPDATE product_questions
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpvoteProductQuestion :execrows
+-- Counter hanya naik jika vote benar-benar baru
+WITH inserted AS (
+  INSERT INTO product_question_votes (question_id, user_id)
+  VALUES (sqlc.arg(question_id), sqlc.arg(user_id))
+  ON CONFLICT (question_id, user_id) DO NOTHING
+  RETURNING question_id
+)
+UPDATE product_questions
+SET upvotes = upvotes + 1
+WHERE id IN (SELECT question_id FROM inserted);
+
+-- name: RemoveProductQuestionUpvote :execrows
+WITH deleted AS (
+  DELETE FROM product_question_votes
+  WHERE product_question_votes.question_id = sqlc.arg(question_id) AND product_question_votes.user_id = sqlc.arg(user_id)
+  RETURNING question_id
+)
+UPDATE product_questions
+SET upvotes = GREATEST(upvotes - 1, 0)
+WHERE id IN (SELECT question_id FROM deleted);
+
+-- name: FlagProductQuestion :execrows
+WITH inserted AS (
+  INSERT INTO product_question_flags (question_id, user_id, reason)
+  VALUES (sqlc.arg(question_id), sqlc.arg(user_id), sqlc.narg(reason))
+  ON CONFLICT (question_id, user_id) DO NOTHING
+  RETURNING question_id
+)
+UPDATE product_questions
+SET flag_count = flag_count + 1
+WHERE id IN (SELECT question_id FROM inserted);
@@ -0,0 +1,84 @@
+-- name: InsertProductReview :one
+INSERT INTO product_reviews (
+  id,
+  product_id,
+  user_id,
+  rating,
+  title,
+  body,
+  verified_purchase,
+  status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (product_id, user_id) DO NOTHING -- satu review per user per produk
+RETURNING *;
+
+-- name: GetProductReviewByID :one
+SELECT * FROM product_reviews WHERE id = $1;
+
+-- name: LockProductReviewByID :one
+SELECT * FROM product_reviews WHERE id = $1 FOR UPDATE;
+
+-- name: GetProductReviewsByProductID :many
+SELECT * FROM product_reviews
+WHERE product_id = $1 AND status = 'approved'
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetProductReviewsByStatus :many
+SELECT * FROM product_reviews
+WHERE status = $1
+ORDER BY created_at
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateProductReview :one
+UPDATE product_reviews
+SET rating = $2, title = $3, body = $4, status = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetProductReviewStatus :one
+UPDATE product_reviews
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetProductReviewReply :one
+UPDATE product_reviews
+SET seller_reply = $2, seller_replied_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteProductReview :execrows
+DELETE FROM product_reviews WHERE id = $1;
+
+-- name: RefreshProductRating :one
+-- Agregat hanya menghitung review yang sudah disetujui moderator
+UPDATE products
+SET
+  rating_avg = COALESCE((
+    SELECT AVG(r.rating)::float8 FROM product_reviews r
+    WHERE r.product_id = products.id AND r.status = 'approved'
+  ), 0),
+  rating_count = (
+    SELECT COUNT(*)::int FROM product_reviews r
+    WHERE r.product_id = products.id AND r.status = 'approved'
+  )
+WHERE products.id = sqlc.arg(product_id)
+RETURNING *;
+
+-- name: InsertUserPurchase :exec
+INSERT INTO user_purchases (user_id, product_id, order_id, purchased_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, product_id) DO NOTHING;
+
+-- name: HasUserPurchased :one
+SELECT EXISTS (
+  SELECT 1 FROM user_purchases WHERE user_id = $1 AND product_id = $2
+);
+
+-- name: MarkProductReviewVerified :execrows
+UPDATE product_reviews
+SET verified_purchase = TRUE
+WHERE user_id = $1 AND product_id = $2 AND NOT verified_purchase;
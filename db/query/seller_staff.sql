@@ -0,0 +1,16 @@
+-- name: AddSellerStaff :one
+INSERT INTO seller_staff (seller_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (seller_id, user_id) DO UPDATE SET seller_id = EXCLUDED.seller_id
+RETURNING *;
+
+-- name: RemoveSellerStaff :execrows
+DELETE FROM seller_staff WHERE seller_id = $1 AND user_id = $2;
+
+-- name: GetSellerStaff :many
+SELECT * FROM seller_staff WHERE seller_id = $1 ORDER BY created_at;
+
+-- name: IsSellerStaff :one
+SELECT EXISTS (
+  SELECT 1 FROM seller_staff WHERE seller_id = $1 AND user_id = $2
+);
@@ -10,10 +10,142 @@ CREATE TABLE products (
     "description" TEXT,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
-    deleted_at TIMESTAMP
+    deleted_at TIMESTAMP,
+    external_sku TEXT,
+    rating_avg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('draft', 'active', 'archived', 'banned')),
+    status_reason TEXT,
+    status_changed_at TIMESTAMPTZ,
+    attributes JSONB NOT NULL DEFAULT '{}'::jsonb
+);
+
+CREATE UNIQUE INDEX idx_products_seller_external_sku ON products (seller_id, external_sku);
+CREATE INDEX idx_products_rating ON products (rating_avg DESC, rating_count DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_status ON products (status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes jsonb_path_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_active_created_at_id ON products (created_at, id) WHERE deleted_at IS NULL AND status = 'active';
+
+CREATE TABLE product_attribute_definitions (
+    product_type TEXT NOT NULL,
+    key TEXT NOT NULL CHECK (key ~ '^[a-z][a-z0-9_]{0,63}$'),
+    data_type TEXT NOT NULL CHECK (data_type IN ('string', 'number', 'boolean')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    unit TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_type, key)
 );
 
 CREATE TABLE users (
     id UUID PRIMARY KEY,
     name TEXT NOT NULL
-);
\ No newline at end of file
+);
+CREATE TABLE seller_staff (
+    seller_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (seller_id, user_id)
+);
+CREATE TABLE product_changes (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    change_type TEXT NOT NULL,
+    product JSONB NOT NULL,
+    tx_id BIGINT NOT NULL DEFAULT txid_current(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_changes_created_at ON product_changes (created_at);
+
+CREATE TABLE carts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    "description" TEXT,
+    checked BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    gift_wrap BOOLEAN NOT NULL DEFAULT FALSE,
+    gift_message TEXT,
+    variant_note TEXT
+);
+
+CREATE UNIQUE INDEX idx_carts_user_product ON carts (user_id, product_id);
+
+CREATE TABLE cart_seller_notes (
+    user_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    note TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, seller_id)
+);
+
+CREATE TABLE cart_versions (
+    user_id UUID PRIMARY KEY,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE product_reviews (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    verified_purchase BOOLEAN NOT NULL DEFAULT FALSE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    seller_reply TEXT,
+    seller_replied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_product_reviews_product_user ON product_reviews (product_id, user_id);
+CREATE INDEX idx_product_reviews_status ON product_reviews (status, created_at);
+
+CREATE TABLE user_purchases (
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    order_id TEXT NOT NULL,
+    purchased_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, product_id)
+);
+
+CREATE TABLE product_questions (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    answer TEXT,
+    answered_by UUID,
+    answered_at TIMESTAMPTZ,
+    upvotes INTEGER NOT NULL DEFAULT 0,
+    flag_count INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_questions_product ON product_questions (product_id, created_at DESC);
+CREATE INDEX idx_product_questions_unanswered ON product_questions (product_id, created_at) WHERE answer IS NULL;
+CREATE INDEX idx_product_questions_flagged ON product_questions (flag_count DESC) WHERE flag_count > 0;
+
+CREATE TABLE product_question_votes (
+    question_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, user_id)
+);
+
+CREATE TABLE product_question_flags (
+    question_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, user_id)
+);
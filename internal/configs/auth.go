@@ -0,0 +1,16 @@
+package configs
+
+import "time"
+
+type AuthConfig struct {
+	JWKSFile            string        `env:"JWT_JWKS_FILE"`
+	JWKSURL             string        `env:"JWT_JWKS_URL"`
+	JWKSRefreshInterval time.Duration `env:"JWT_JWKS_REFRESH_INTERVAL" envDefault:"10m"`
+	Issuer              string        `env:"JWT_ISSUER"`
+	Audience            string        `env:"JWT_AUDIENCE"`
+	BlacklistPrefix     string        `env:"JWT_BLACKLIST_PREFIX" envDefault:"jwt_blacklist:"`
+
+	// GRPCFallback mengaktifkan validasi lewat account service jika token tidak bisa diverifikasi secara lokal
+	GRPCFallback        bool          `env:"AUTH_GRPC_FALLBACK" envDefault:"false"`
+	GRPCFallbackTimeout time.Duration `env:"AUTH_GRPC_FALLBACK_TIMEOUT" envDefault:"5s"`
+}
@@ -0,0 +1,22 @@
+package configs
+
+import "time"
+
+type CacheConfig struct {
+	TTL         time.Duration `env:"CACHE_TTL" envDefault:"5m"`
+	NegativeTTL time.Duration `env:"CACHE_NEGATIVE_TTL" envDefault:"30s"`
+	// TTL setiap entry ditambah acak hingga TTL*TTLJitter supaya key tidak kedaluwarsa bersamaan
+	TTLJitter float64 `env:"CACHE_TTL_JITTER" envDefault:"0.2"`
+
+	// Cache lokal (L1) di setiap instance untuk lookup product:<id>. TTL sengaja pendek sebagai pengaman
+	// jika pesan invalidasi pub/sub terlewat (mis. saat koneksi Redis terputus).
+	LocalEnabled bool          `env:"CACHE_L1_ENABLED" envDefault:"true"`
+	LocalSize    int           `env:"CACHE_L1_SIZE" envDefault:"10000"`
+	LocalTTL     time.Duration `env:"CACHE_L1_TTL" envDefault:"30s"`
+
+	InvalidationChannel string `env:"CACHE_INVALIDATION_CHANNEL" envDefault:"catalog:cache_invalidation"`
+
+	// Warm-up saat startup supaya Redis yang baru di-flush tidak langsung membanjiri Postgres
+	WarmupOnStart bool `env:"CACHE_WARMUP_ON_START" envDefault:"false"`
+	WarmupTopN    int  `env:"CACHE_WARMUP_TOP_N" envDefault:"200"`
+}
@@ -0,0 +1,14 @@
+package configs
+
+import "time"
+
+const (
+	CartStoreRedis    = "redis"    // cart hanya di Redis (perilaku lama, hilang jika Redis di-flush)
+	CartStorePostgres = "postgres" // cart hanya di Postgres
+	CartStoreLayered  = "layered"  // Postgres sebagai sumber kebenaran, Redis sebagai cache write-through
+)
+
+type CartConfig struct {
+	Store    string        `env:"CART_STORE" envDefault:"layered"`
+	CacheTTL time.Duration `env:"CART_CACHE_TTL" envDefault:"24h"`
+}
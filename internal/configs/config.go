@@ -12,10 +12,21 @@ type AppConfig struct {
 	Database  DatabaseConfig
 	Migration MigrationConfig
 	Redis     RedisConfig
-	GRPC      GrpcConfig
-	Server    ServerConfig
-	RabbitMQ  struct {
+	Cache     CacheConfig
+	Cart      CartConfig
+	// RecentlyViewed mengatur riwayat produk yang dilihat per user/guest
+	RecentlyViewed RecentlyViewedConfig
+	Trending       TrendingConfig
+	Related        RelatedConfig
+	GRPC           GrpcConfig
+	GRPCServer     GrpcServerConfig
+	Server         ServerConfig
+	Auth           AuthConfig
+	Tracing        TracingConfig
+	RabbitMQ       struct {
 		URL string `env:"RABBITMQ_URL,required"`
+		// OrderQueue menerima OrderCreatedEvent untuk menghitung co-purchase
+		OrderQueue string `env:"RABBITMQ_ORDER_QUEUE" envDefault:"catalog.order_created"`
 	}
 }
 
@@ -1,6 +1,36 @@
 package configs
 
+import "time"
+
 type GrpcConfig struct {
 	AccountServiceAddress string `env:"ACCOUNT_GRPC_SERVER_ADDRESS,required"`
 	ProductServiceAddress string `env:"PRODUCT_SERVICE_GRPC_URL,required"`
+
+	// Ketahanan client account service: timeout per percobaan, retry dengan backoff dan circuit breaker
+	AccountTimeout          time.Duration `env:"ACCOUNT_GRPC_TIMEOUT" envDefault:"2s"`
+	AccountMaxRetries       int           `env:"ACCOUNT_GRPC_MAX_RETRIES" envDefault:"2"`
+	AccountRetryBackoff     time.Duration `env:"ACCOUNT_GRPC_RETRY_BACKOFF" envDefault:"100ms"`
+	AccountRetryMaxBackoff  time.Duration `env:"ACCOUNT_GRPC_RETRY_MAX_BACKOFF" envDefault:"1s"`
+	AccountBreakerThreshold int           `env:"ACCOUNT_GRPC_BREAKER_THRESHOLD" envDefault:"5"`
+	AccountBreakerTimeout   time.Duration `env:"ACCOUNT_GRPC_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+}
+
+type GrpcServerConfig struct {
+	// AuthMode: "secret" (header x-service-name + x-service-secret) atau "mtls" (CN sertifikat client)
+	AuthMode        string            `env:"GRPC_AUTH_MODE" envDefault:"secret"`
+	ServiceSecrets  map[string]string `env:"GRPC_SERVICE_SECRETS"` // format: order:secret1,cart:secret2
+	AllowedServices []string          `env:"GRPC_ALLOWED_SERVICES" envSeparator:","`
+	TLSCertFile     string            `env:"GRPC_TLS_CERT_FILE"`
+	TLSKeyFile      string            `env:"GRPC_TLS_KEY_FILE"`
+	TLSClientCAFile string            `env:"GRPC_TLS_CLIENT_CA_FILE"`
+
+	// StockServices adalah service yang boleh memanggil DecreaseStock/IncreaseStock
+	StockServices []string `env:"GRPC_STOCK_SERVICES" envSeparator:"," envDefault:"order"`
+
+	// WatchPollInterval adalah jeda polling change log untuk stream WatchProducts
+	WatchPollInterval time.Duration `env:"GRPC_WATCH_POLL_INTERVAL" envDefault:"1s"`
+	// WatchRetention adalah umur maksimum change log; resume token yang lebih tua ditolak dan client harus resync
+	WatchRetention time.Duration `env:"GRPC_WATCH_RETENTION" envDefault:"168h"`
+	// WatchPruneInterval adalah jeda job yang menghapus change log yang melewati WatchRetention
+	WatchPruneInterval time.Duration `env:"GRPC_WATCH_PRUNE_INTERVAL" envDefault:"1h"`
 }
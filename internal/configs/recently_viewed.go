@@ -0,0 +1,11 @@
+package configs
+
+import "time"
+
+type RecentlyViewedConfig struct {
+	// Limit adalah jumlah maksimum produk yang disimpan per user/guest
+	Limit int           `env:"RECENTLY_VIEWED_LIMIT" envDefault:"50"`
+	TTL   time.Duration `env:"RECENTLY_VIEWED_TTL" envDefault:"720h"`
+	// GuestTTL dibuat jauh lebih pendek karena guest token tidak terikat akun dan mudah dibuat ulang
+	GuestTTL time.Duration `env:"RECENTLY_VIEWED_GUEST_TTL" envDefault:"48h"`
+}
@@ -0,0 +1,9 @@
+package configs
+
+import "time"
+
+type RelatedConfig struct {
+	CacheTTL time.Duration `env:"RELATED_CACHE_TTL" envDefault:"10m"`
+	// MaxCoPurchases adalah jumlah pasangan co-purchase teratas yang disimpan per produk
+	MaxCoPurchases int `env:"RELATED_MAX_COPURCHASES" envDefault:"200"`
+}
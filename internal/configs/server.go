@@ -1,7 +1,13 @@
 package configs
 
+import "time"
+
 type ServerConfig struct {
 	Port      string `env:"SERVER_PORT,required"`
 	GRPCPort  string `env:"GRPC_PORT,required"`
 	JWTSecret string `env:"JWT_SECRET,required"`
+
+	// ShutdownTimeout adalah batas waktu drain HTTP, gRPC dan task background setelah SIGTERM
+	ShutdownTimeout       time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	ReadinessCheckTimeout time.Duration `env:"READINESS_CHECK_TIMEOUT" envDefault:"2s"`
 }
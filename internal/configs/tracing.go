@@ -0,0 +1,8 @@
+package configs
+
+// Endpoint, header dan TLS OTLP dibaca langsung oleh exporter dari env standar OTEL_EXPORTER_OTLP_*
+type TracingConfig struct {
+	Exporter    string  `env:"OTEL_TRACES_EXPORTER" envDefault:"none"` // otlp | stdout | none
+	ServiceName string  `env:"OTEL_SERVICE_NAME" envDefault:"shopeezy-catalog"`
+	SampleRatio float64 `env:"OTEL_TRACES_SAMPLER_RATIO" envDefault:"1"`
+}
@@ -0,0 +1,10 @@
+package configs
+
+import "time"
+
+type TrendingConfig struct {
+	// CompactInterval adalah jeda job yang merangkum bucket sinyal per jam menjadi skor trending
+	CompactInterval time.Duration `env:"TRENDING_COMPACT_INTERVAL" envDefault:"5m"`
+	// MaxItems adalah jumlah produk teratas yang disimpan per window dan per type
+	MaxItems int `env:"TRENDING_MAX_ITEMS" envDefault:"500"`
+}
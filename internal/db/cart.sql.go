@@ -0,0 +1,278 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: cart.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteCartItem = `-- name: DeleteCartItem :exec
+DELETE FROM carts WHERE user_id = $1 AND product_id = $2
+`
+
+type DeleteCartItemParams struct {
+	UserID    uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCartItem, arg.UserID, arg.ProductID)
+	return err
+}
+
+const deleteCartSellerNote = `-- name: DeleteCartSellerNote :exec
+DELETE FROM cart_seller_notes WHERE user_id = $1 AND seller_id = $2
+`
+
+type DeleteCartSellerNoteParams struct {
+	UserID   uuid.UUID
+	SellerID uuid.UUID
+}
+
+func (q *Queries) DeleteCartSellerNote(ctx context.Context, arg DeleteCartSellerNoteParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCartSellerNote, arg.UserID, arg.SellerID)
+	return err
+}
+
+const ensureCartVersion = `-- name: EnsureCartVersion :exec
+INSERT INTO cart_versions (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING
+`
+
+func (q *Queries) EnsureCartVersion(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, ensureCartVersion, userID)
+	return err
+}
+
+const getCartItemsByUserID = `-- name: GetCartItemsByUserID :many
+SELECT id, user_id, product_id, quantity, description, checked, created_at, updated_at, gift_wrap, gift_message, variant_note FROM carts WHERE user_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetCartItemsByUserID(ctx context.Context, userID uuid.UUID) ([]Cart, error) {
+	rows, err := q.db.QueryContext(ctx, getCartItemsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Cart
+	for rows.Next() {
+		var i Cart
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Description,
+			&i.Checked,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GiftWrap,
+			&i.GiftMessage,
+			&i.VariantNote,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCartSellerNotes = `-- name: GetCartSellerNotes :many
+SELECT user_id, seller_id, note, updated_at FROM cart_seller_notes WHERE user_id = $1
+`
+
+func (q *Queries) GetCartSellerNotes(ctx context.Context, userID uuid.UUID) ([]CartSellerNote, error) {
+	rows, err := q.db.QueryContext(ctx, getCartSellerNotes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CartSellerNote
+	for rows.Next() {
+		var i CartSellerNote
+		if err := rows.Scan(
+			&i.UserID,
+			&i.SellerID,
+			&i.Note,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCartVersion = `-- name: GetCartVersion :one
+SELECT version FROM cart_versions WHERE user_id = $1
+`
+
+func (q *Queries) GetCartVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getCartVersion, userID)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const incrementCartVersion = `-- name: IncrementCartVersion :one
+UPDATE cart_versions SET version = version + 1, updated_at = NOW() WHERE user_id = $1 RETURNING version
+`
+
+func (q *Queries) IncrementCartVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, incrementCartVersion, userID)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const lockCartVersion = `-- name: LockCartVersion :one
+SELECT version FROM cart_versions WHERE user_id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockCartVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, lockCartVersion, userID)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const updateCartItem = `-- name: UpdateCartItem :one
+UPDATE carts
+SET quantity = $3,
+  "description" = $4,
+  gift_wrap = $5,
+  gift_message = $6,
+  variant_note = $7,
+  updated_at = NOW()
+WHERE user_id = $1 AND product_id = $2
+RETURNING id, user_id, product_id, quantity, description, checked, created_at, updated_at, gift_wrap, gift_message, variant_note
+`
+
+type UpdateCartItemParams struct {
+	UserID      uuid.UUID
+	ProductID   uuid.UUID
+	Quantity    int32
+	Description sql.NullString
+	GiftWrap    bool
+	GiftMessage sql.NullString
+	VariantNote sql.NullString
+}
+
+func (q *Queries) UpdateCartItem(ctx context.Context, arg UpdateCartItemParams) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, updateCartItem,
+		arg.UserID,
+		arg.ProductID,
+		arg.Quantity,
+		arg.Description,
+		arg.GiftWrap,
+		arg.GiftMessage,
+		arg.VariantNote,
+	)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Description,
+		&i.Checked,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GiftWrap,
+		&i.GiftMessage,
+		&i.VariantNote,
+	)
+	return i, err
+}
+
+const upsertCartItem = `-- name: UpsertCartItem :one
+INSERT INTO carts (id, user_id, product_id, quantity, "description", checked, gift_wrap, gift_message, variant_note, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+ON CONFLICT (user_id, product_id) DO UPDATE SET
+  quantity = EXCLUDED.quantity,
+  "description" = EXCLUDED."description",
+  checked = EXCLUDED.checked,
+  gift_wrap = EXCLUDED.gift_wrap,
+  gift_message = EXCLUDED.gift_message,
+  variant_note = EXCLUDED.variant_note,
+  updated_at = NOW()
+RETURNING id, user_id, product_id, quantity, description, checked, created_at, updated_at, gift_wrap, gift_message, variant_note
+`
+
+type UpsertCartItemParams struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	ProductID   uuid.UUID
+	Quantity    int32
+	Description sql.NullString
+	Checked     bool
+	GiftWrap    bool
+	GiftMessage sql.NullString
+	VariantNote sql.NullString
+	CreatedAt   time.Time
+}
+
+func (q *Queries) UpsertCartItem(ctx context.Context, arg UpsertCartItemParams) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, upsertCartItem,
+		arg.ID,
+		arg.UserID,
+		arg.ProductID,
+		arg.Quantity,
+		arg.Description,
+		arg.Checked,
+		arg.GiftWrap,
+		arg.GiftMessage,
+		arg.VariantNote,
+		arg.CreatedAt,
+	)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Description,
+		&i.Checked,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GiftWrap,
+		&i.GiftMessage,
+		&i.VariantNote,
+	)
+	return i, err
+}
+
+const upsertCartSellerNote = `-- name: UpsertCartSellerNote :exec
+INSERT INTO cart_seller_notes (user_id, seller_id, note)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, seller_id) DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+`
+
+type UpsertCartSellerNoteParams struct {
+	UserID   uuid.UUID
+	SellerID uuid.UUID
+	Note     string
+}
+
+func (q *Queries) UpsertCartSellerNote(ctx context.Context, arg UpsertCartSellerNoteParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCartSellerNote, arg.UserID, arg.SellerID, arg.Note)
+	return err
+}
@@ -6,26 +6,138 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Product struct {
+type Cart struct {
 	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
+	UserID      uuid.UUID
+	ProductID   uuid.UUID
+	Quantity    int32
 	Description sql.NullString
+	Checked     bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-	DeletedAt   sql.NullTime
+	GiftWrap    bool
+	GiftMessage sql.NullString
+	VariantNote sql.NullString
+}
+
+type CartSellerNote struct {
+	UserID    uuid.UUID
+	SellerID  uuid.UUID
+	Note      string
+	UpdatedAt time.Time
+}
+
+type CartVersion struct {
+	UserID    uuid.UUID
+	Version   int64
+	UpdatedAt time.Time
+}
+
+type Product struct {
+	ID              uuid.UUID
+	SellerID        uuid.UUID
+	Name            string
+	Price           int32
+	Stock           int32
+	Discount        sql.NullInt32
+	Type            sql.NullString
+	Description     sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       sql.NullTime
+	ExternalSku     sql.NullString
+	RatingAvg       float64
+	RatingCount     int32
+	Status          string
+	StatusReason    sql.NullString
+	StatusChangedAt sql.NullTime
+	Attributes      json.RawMessage
+}
+
+type ProductAttributeDefinition struct {
+	ProductType   string
+	Key           string
+	DataType      string
+	Required      bool
+	AllowedValues []string
+	Unit          sql.NullString
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type ProductChange struct {
+	ID         int64
+	ProductID  uuid.UUID
+	SellerID   uuid.UUID
+	ChangeType string
+	Product    json.RawMessage
+	TxID       int64
+	CreatedAt  time.Time
+}
+
+type ProductQuestion struct {
+	ID         uuid.UUID
+	ProductID  uuid.UUID
+	UserID     uuid.UUID
+	Body       string
+	Answer     sql.NullString
+	AnsweredBy uuid.NullUUID
+	AnsweredAt sql.NullTime
+	Upvotes    int32
+	FlagCount  int32
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ProductQuestionFlag struct {
+	QuestionID uuid.UUID
+	UserID     uuid.UUID
+	Reason     sql.NullString
+	CreatedAt  time.Time
+}
+
+type ProductQuestionVote struct {
+	QuestionID uuid.UUID
+	UserID     uuid.UUID
+	CreatedAt  time.Time
+}
+
+type ProductReview struct {
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	UserID           uuid.UUID
+	Rating           int16
+	Title            string
+	Body             string
+	VerifiedPurchase bool
+	Status           string
+	SellerReply      sql.NullString
+	SellerRepliedAt  sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type SellerStaff struct {
+	SellerID  uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
 }
 
 type User struct {
 	ID   uuid.UUID
 	Name string
 }
+
+type UserPurchase struct {
+	UserID      uuid.UUID
+	ProductID   uuid.UUID
+	OrderID     string
+	PurchasedAt time.Time
+}
@@ -8,12 +8,236 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+const bulkAdjustProductPrice = `-- name: BulkAdjustProductPrice :many
+UPDATE products
+SET
+    price = GREATEST(ROUND(price * (1 + $1::float8 / 100)), 1)::int,
+    updated_at = NOW()
+WHERE id = ANY($2::uuid[])
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type BulkAdjustProductPriceParams struct {
+	Percent float64
+	Ids     []uuid.UUID
+}
+
+func (q *Queries) BulkAdjustProductPrice(ctx context.Context, arg BulkAdjustProductPriceParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, bulkAdjustProductPrice, arg.Percent, pq.Array(arg.Ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkDeleteProducts = `-- name: BulkDeleteProducts :many
+DELETE FROM products
+WHERE id = ANY($1::uuid[])
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+func (q *Queries) BulkDeleteProducts(ctx context.Context, ids []uuid.UUID) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, bulkDeleteProducts, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkSetProductDiscount = `-- name: BulkSetProductDiscount :many
+UPDATE products
+SET discount = $1, updated_at = NOW()
+WHERE id = ANY($2::uuid[])
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type BulkSetProductDiscountParams struct {
+	Discount sql.NullInt32
+	Ids      []uuid.UUID
+}
+
+func (q *Queries) BulkSetProductDiscount(ctx context.Context, arg BulkSetProductDiscountParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, bulkSetProductDiscount, arg.Discount, pq.Array(arg.Ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkSetProductType = `-- name: BulkSetProductType :many
+UPDATE products
+SET "type" = $1, updated_at = NOW()
+WHERE id = ANY($2::uuid[])
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type BulkSetProductTypeParams struct {
+	ProductType sql.NullString
+	Ids         []uuid.UUID
+}
+
+func (q *Queries) BulkSetProductType(ctx context.Context, arg BulkSetProductTypeParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, bulkSetProductType, arg.ProductType, pq.Array(arg.Ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countActiveProducts = `-- name: CountActiveProducts :one
+SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND status = 'active'
+`
+
+func (q *Queries) CountActiveProducts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveProducts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const decreaseProductStock = `-- name: DecreaseProductStock :one
 UPDATE products
 SET
@@ -21,7 +245,7 @@ SET
 WHERE
     id = $2
     AND stock >= $1 -- Penjaga anti-overselling
-RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
 `
 
 type DecreaseProductStockParams struct {
@@ -44,13 +268,20 @@ func (q *Queries) DecreaseProductStock(ctx context.Context, arg DecreaseProductS
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
 
 const deleteProduct = `-- name: DeleteProduct :one
 DELETE FROM products WHERE id = $1 
-RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
 `
 
 func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) (Product, error) {
@@ -68,6 +299,13 @@ func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) (Product, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
@@ -82,23 +320,35 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE deleted_at IS NULL
+WHERE deleted_at IS NULL AND status = 'active'
 `
 
 type GetAllProductsRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetAllProducts(ctx context.Context) ([]GetAllProductsRow, error) {
@@ -119,6 +369,12 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]GetAllProductsRow, erro
 			&i.Discount,
 			&i.Type,
 			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -135,6 +391,23 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]GetAllProductsRow, erro
 	return items, nil
 }
 
+const getProductAttributesBySKU = `-- name: GetProductAttributesBySKU :one
+SELECT attributes FROM products
+WHERE seller_id = $1 AND external_sku = $2
+`
+
+type GetProductAttributesBySKUParams struct {
+	SellerID    uuid.UUID
+	ExternalSku sql.NullString
+}
+
+func (q *Queries) GetProductAttributesBySKU(ctx context.Context, arg GetProductAttributesBySKUParams) (json.RawMessage, error) {
+	row := q.db.QueryRowContext(ctx, getProductAttributesBySKU, arg.SellerID, arg.ExternalSku)
+	var attributes json.RawMessage
+	err := row.Scan(&attributes)
+	return attributes, err
+}
+
 const getProductByID = `-- name: GetProductByID :one
 SELECT 
   id,
@@ -145,6 +418,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -152,16 +431,22 @@ WHERE id = $1 AND deleted_at IS NULL
 `
 
 type GetProductByIDRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductByIDRow, error) {
@@ -176,6 +461,12 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductB
 		&i.Discount,
 		&i.Type,
 		&i.Description,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.Attributes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -192,6 +483,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -199,16 +496,22 @@ WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
 `
 
 type GetProductByIDsRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetProductByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]GetProductByIDsRow, error) {
@@ -229,6 +532,12 @@ func (q *Queries) GetProductByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]
 			&i.Discount,
 			&i.Type,
 			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -266,23 +575,35 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE name ILIKE $1 AND deleted_at IS NULL
+WHERE name ILIKE $1 AND deleted_at IS NULL AND status = 'active'
 `
 
 type GetProductsByNameRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetProductsByName(ctx context.Context, name string) ([]GetProductsByNameRow, error) {
@@ -303,6 +624,12 @@ func (q *Queries) GetProductsByName(ctx context.Context, name string) ([]GetProd
 			&i.Discount,
 			&i.Type,
 			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -329,6 +656,12 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
@@ -336,16 +669,22 @@ WHERE seller_id = $1 AND deleted_at IS NULL
 `
 
 type GetProductsBySellerIDRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]GetProductsBySellerIDRow, error) {
@@ -366,6 +705,12 @@ func (q *Queries) GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID)
 			&i.Discount,
 			&i.Type,
 			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -392,23 +737,35 @@ SELECT
   discount,
   "type",
   "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
   created_at,
   updated_at
 FROM products
-WHERE "type" = $1 AND deleted_at IS NULL
+WHERE "type" = $1 AND deleted_at IS NULL AND status = 'active'
 `
 
 type GetProductsByTypeRow struct {
-	ID          uuid.UUID
-	SellerID    uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
-	Type        sql.NullString
-	Description sql.NullString
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (q *Queries) GetProductsByType(ctx context.Context, type_ sql.NullString) ([]GetProductsByTypeRow, error) {
@@ -429,6 +786,95 @@ func (q *Queries) GetProductsByType(ctx context.Context, type_ sql.NullString) (
 			&i.Discount,
 			&i.Type,
 			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentlyUpdatedProducts = `-- name: GetRecentlyUpdatedProducts :many
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+ORDER BY updated_at DESC
+LIMIT $1
+`
+
+type GetRecentlyUpdatedProductsRow struct {
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (q *Queries) GetRecentlyUpdatedProducts(ctx context.Context, limit int32) ([]GetRecentlyUpdatedProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentlyUpdatedProducts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentlyUpdatedProductsRow
+	for rows.Next() {
+		var i GetRecentlyUpdatedProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -451,7 +897,7 @@ SET
     stock = stock + $1
 WHERE
     id = $2
-RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
 `
 
 type IncreaseProductStockParams struct {
@@ -474,6 +920,13 @@ func (q *Queries) IncreaseProductStock(ctx context.Context, arg IncreaseProductS
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
@@ -488,11 +941,14 @@ INSERT INTO products (
   discount, 
   "type", 
   "description", 
+  external_sku,
+  status,
+  attributes,
   created_at, 
   updated_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
-) RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW()
+) RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
 `
 
 type InsertProductParams struct {
@@ -504,6 +960,9 @@ type InsertProductParams struct {
 	Discount    sql.NullInt32
 	Type        sql.NullString
 	Description sql.NullString
+	ExternalSku sql.NullString
+	Status      string
+	Attributes  json.RawMessage
 }
 
 func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (Product, error) {
@@ -516,6 +975,9 @@ func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (P
 		arg.Discount,
 		arg.Type,
 		arg.Description,
+		arg.ExternalSku,
+		arg.Status,
+		arg.Attributes,
 	)
 	var i Product
 	err := row.Scan(
@@ -530,26 +992,505 @@ func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
 
-const updateProduct = `-- name: UpdateProduct :one
-UPDATE products
-SET name = $2, price = $3, stock = $4, discount = $5, type = $6, description = $7, updated_at = NOW()
-WHERE id = $1 AND seller_id = $8
-RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
-`
-
-type UpdateProductParams struct {
-	ID          uuid.UUID
-	Name        string
-	Price       int32
-	Stock       int32
-	Discount    sql.NullInt32
+const listActiveProductsPage = `-- name: ListActiveProductsPage :many
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL AND status = 'active'
+  AND (
+    $1::timestamp IS NULL
+    OR (created_at, id) > ($1::timestamp, $2::uuid)
+  )
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListActiveProductsPageParams struct {
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	RowLimit       int32
+}
+
+type ListActiveProductsPageRow struct {
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Keyset pagination berdasarkan (created_at, id); cursor kosong berarti halaman pertama
+func (q *Queries) ListActiveProductsPage(ctx context.Context, arg ListActiveProductsPageParams) ([]ListActiveProductsPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveProductsPage, arg.AfterCreatedAt, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveProductsPageRow
+	for rows.Next() {
+		var i ListActiveProductsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockProductsByFilter = `-- name: LockProductsByFilter :many
+SELECT id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes FROM products
+WHERE deleted_at IS NULL
+  AND ($1::uuid IS NULL OR seller_id = $1)
+  AND ($2::text IS NULL OR "type" = $2)
+ORDER BY id -- urutan lock sama dengan LockProductsByIDs
+LIMIT $3
+FOR UPDATE
+`
+
+type LockProductsByFilterParams struct {
+	SellerID    uuid.NullUUID
+	ProductType sql.NullString
+	RowLimit    int32
+}
+
+func (q *Queries) LockProductsByFilter(ctx context.Context, arg LockProductsByFilterParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, lockProductsByFilter, arg.SellerID, arg.ProductType, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockProductsByIDs = `-- name: LockProductsByIDs :many
+SELECT id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes FROM products
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+ORDER BY id -- urutan lock konsisten supaya transaksi stok paralel tidak deadlock
+FOR UPDATE
+`
+
+func (q *Queries) LockProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, lockProductsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProducts = `-- name: SearchProducts :many
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+  AND ($1::bool OR status = 'active')
+  AND ($2::text IS NULL OR "name" ILIKE $2)
+  AND ($3::text IS NULL OR "type" = $3)
+  AND ($4::uuid IS NULL OR seller_id = $4)
+  AND ($5::jsonpath IS NULL OR attributes @@ $5::jsonpath)
+  AND ($6::float8 IS NULL OR rating_avg >= $6)
+ORDER BY created_at DESC, id
+LIMIT $8 OFFSET $7
+`
+
+type SearchProductsParams struct {
+	IncludeInactive bool
+	NamePattern     sql.NullString
+	ProductType     sql.NullString
+	SellerID        uuid.NullUUID
+	AttributeFilter interface{}
+	MinRating       sql.NullFloat64
+	RowOffset       int32
+	RowLimit        int32
+}
+
+type SearchProductsRow struct {
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// attribute_filter adalah predikat jsonpath yang dibangun service dari filter attr.*; didukung index GIN jsonb_path_ops
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProducts,
+		arg.IncludeInactive,
+		arg.NamePattern,
+		arg.ProductType,
+		arg.SellerID,
+		arg.AttributeFilter,
+		arg.MinRating,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsRow
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProductsByRating = `-- name: SearchProductsByRating :many
+SELECT 
+  id,
+  seller_id,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  external_sku,
+  rating_avg,
+  rating_count,
+  status,
+  status_reason,
+  attributes,
+  created_at,
+  updated_at
+FROM products
+WHERE deleted_at IS NULL
+  AND ($1::bool OR status = 'active')
+  AND ($2::text IS NULL OR "name" ILIKE $2)
+  AND ($3::text IS NULL OR "type" = $3)
+  AND ($4::uuid IS NULL OR seller_id = $4)
+  AND ($5::jsonpath IS NULL OR attributes @@ $5::jsonpath)
+  AND ($6::float8 IS NULL OR rating_avg >= $6)
+ORDER BY rating_avg DESC, rating_count DESC, id
+LIMIT $8 OFFSET $7
+`
+
+type SearchProductsByRatingParams struct {
+	IncludeInactive bool
+	NamePattern     sql.NullString
+	ProductType     sql.NullString
+	SellerID        uuid.NullUUID
+	AttributeFilter interface{}
+	MinRating       sql.NullFloat64
+	RowOffset       int32
+	RowLimit        int32
+}
+
+type SearchProductsByRatingRow struct {
+	ID           uuid.UUID
+	SellerID     uuid.UUID
+	Name         string
+	Price        int32
+	Stock        int32
+	Discount     sql.NullInt32
+	Type         sql.NullString
+	Description  sql.NullString
+	ExternalSku  sql.NullString
+	RatingAvg    float64
+	RatingCount  int32
+	Status       string
+	StatusReason sql.NullString
+	Attributes   json.RawMessage
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Filter sama dengan SearchProducts, diurutkan mengikuti idx_products_rating; id menjaga urutan halaman tetap stabil
+func (q *Queries) SearchProductsByRating(ctx context.Context, arg SearchProductsByRatingParams) ([]SearchProductsByRatingRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProductsByRating,
+		arg.IncludeInactive,
+		arg.NamePattern,
+		arg.ProductType,
+		arg.SellerID,
+		arg.AttributeFilter,
+		arg.MinRating,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsByRatingRow
+	for rows.Next() {
+		var i SearchProductsByRatingRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Discount,
+			&i.Type,
+			&i.Description,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.Status,
+			&i.StatusReason,
+			&i.Attributes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const transitionProductStatus = `-- name: TransitionProductStatus :one
+UPDATE products
+SET
+  status = $1,
+  status_reason = $2,
+  status_changed_at = NOW(),
+  updated_at = NOW()
+WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type TransitionProductStatusParams struct {
+	ToStatus   string
+	Reason     sql.NullString
+	ID         uuid.UUID
+	FromStatus string
+}
+
+// Hanya berhasil jika status belum diubah oleh request lain sejak dibaca
+func (q *Queries) TransitionProductStatus(ctx context.Context, arg TransitionProductStatusParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, transitionProductStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.Discount,
+		&i.Type,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
+	)
+	return i, err
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+UPDATE products
+SET name = $2, price = $3, stock = $4, discount = $5, type = $6, description = $7, attributes = $9, updated_at = NOW()
+WHERE id = $1 AND seller_id = $8
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type UpdateProductParams struct {
+	ID          uuid.UUID
+	Name        string
+	Price       int32
+	Stock       int32
+	Discount    sql.NullInt32
 	Type        sql.NullString
 	Description sql.NullString
 	SellerID    uuid.UUID
+	Attributes  json.RawMessage
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
@@ -562,6 +1503,7 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		arg.Type,
 		arg.Description,
 		arg.SellerID,
+		arg.Attributes,
 	)
 	var i Product
 	err := row.Scan(
@@ -576,13 +1518,20 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
 
 const updateProductStock = `-- name: UpdateProductStock :one
 UPDATE products SET stock = $2 WHERE id = $1 
-RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
 `
 
 type UpdateProductStockParams struct {
@@ -605,6 +1554,94 @@ func (q *Queries) UpdateProductStock(ctx context.Context, arg UpdateProductStock
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
+	)
+	return i, err
+}
+
+const upsertProductBySKU = `-- name: UpsertProductBySKU :one
+INSERT INTO products (
+  id,
+  seller_id,
+  external_sku,
+  "name",
+  price,
+  stock,
+  discount,
+  "type",
+  "description",
+  attributes,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW()
+)
+ON CONFLICT (seller_id, external_sku) DO UPDATE
+SET
+  "name" = EXCLUDED."name",
+  price = EXCLUDED.price,
+  stock = EXCLUDED.stock,
+  discount = EXCLUDED.discount,
+  "type" = EXCLUDED."type",
+  "description" = EXCLUDED."description",
+  attributes = EXCLUDED.attributes,
+  updated_at = NOW()
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+type UpsertProductBySKUParams struct {
+	ID          uuid.UUID
+	SellerID    uuid.UUID
+	ExternalSku sql.NullString
+	Name        string
+	Price       int32
+	Stock       int32
+	Discount    sql.NullInt32
+	Type        sql.NullString
+	Description sql.NullString
+	Attributes  json.RawMessage
+}
+
+// attributes sudah divalidasi service terhadap type baris import (atribut lama dipakai jika baris tidak membawanya)
+func (q *Queries) UpsertProductBySKU(ctx context.Context, arg UpsertProductBySKUParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, upsertProductBySKU,
+		arg.ID,
+		arg.SellerID,
+		arg.ExternalSku,
+		arg.Name,
+		arg.Price,
+		arg.Stock,
+		arg.Discount,
+		arg.Type,
+		arg.Description,
+		arg.Attributes,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.Discount,
+		&i.Type,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
 	)
 	return i, err
 }
@@ -0,0 +1,122 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: product_attribute.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const deleteAttributeDefinition = `-- name: DeleteAttributeDefinition :execrows
+DELETE FROM product_attribute_definitions
+WHERE product_type = $1 AND key = $2
+`
+
+type DeleteAttributeDefinitionParams struct {
+	ProductType string
+	Key         string
+}
+
+func (q *Queries) DeleteAttributeDefinition(ctx context.Context, arg DeleteAttributeDefinitionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAttributeDefinition, arg.ProductType, arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAttributeDefinitionsByType = `-- name: GetAttributeDefinitionsByType :many
+SELECT product_type, key, data_type, required, allowed_values, unit, created_at, updated_at FROM product_attribute_definitions
+WHERE product_type = $1
+ORDER BY key
+`
+
+func (q *Queries) GetAttributeDefinitionsByType(ctx context.Context, productType string) ([]ProductAttributeDefinition, error) {
+	rows, err := q.db.QueryContext(ctx, getAttributeDefinitionsByType, productType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductAttributeDefinition
+	for rows.Next() {
+		var i ProductAttributeDefinition
+		if err := rows.Scan(
+			&i.ProductType,
+			&i.Key,
+			&i.DataType,
+			&i.Required,
+			pq.Array(&i.AllowedValues),
+			&i.Unit,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertAttributeDefinition = `-- name: UpsertAttributeDefinition :one
+INSERT INTO product_attribute_definitions (
+  product_type,
+  key,
+  data_type,
+  required,
+  allowed_values,
+  unit
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (product_type, key) DO UPDATE
+SET
+  data_type = EXCLUDED.data_type,
+  required = EXCLUDED.required,
+  allowed_values = EXCLUDED.allowed_values,
+  unit = EXCLUDED.unit,
+  updated_at = NOW()
+RETURNING product_type, key, data_type, required, allowed_values, unit, created_at, updated_at
+`
+
+type UpsertAttributeDefinitionParams struct {
+	ProductType   string
+	Key           string
+	DataType      string
+	Required      bool
+	AllowedValues []string
+	Unit          sql.NullString
+}
+
+func (q *Queries) UpsertAttributeDefinition(ctx context.Context, arg UpsertAttributeDefinitionParams) (ProductAttributeDefinition, error) {
+	row := q.db.QueryRowContext(ctx, upsertAttributeDefinition,
+		arg.ProductType,
+		arg.Key,
+		arg.DataType,
+		arg.Required,
+		pq.Array(arg.AllowedValues),
+		arg.Unit,
+	)
+	var i ProductAttributeDefinition
+	err := row.Scan(
+		&i.ProductType,
+		&i.Key,
+		&i.DataType,
+		&i.Required,
+		pq.Array(&i.AllowedValues),
+		&i.Unit,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,105 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: product_change.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const deleteExpiredProductChanges = `-- name: DeleteExpiredProductChanges :execrows
+DELETE FROM product_changes
+WHERE id IN (
+    SELECT pc.id FROM product_changes pc
+    WHERE pc.created_at < $1
+        AND pc.tx_id < txid_snapshot_xmin(txid_current_snapshot())
+    ORDER BY pc.created_at
+    LIMIT $2
+)
+`
+
+type DeleteExpiredProductChangesParams struct {
+	Cutoff  time.Time
+	MaxRows int32
+}
+
+// Hanya baris dari transaksi yang sudah final (di bawah watermark) yang dihapus, per batch supaya lock tidak lama
+func (q *Queries) DeleteExpiredProductChanges(ctx context.Context, arg DeleteExpiredProductChangesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredProductChanges, arg.Cutoff, arg.MaxRows)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getProductChangeWatermark = `-- name: GetProductChangeWatermark :one
+SELECT txid_snapshot_xmin(txid_current_snapshot())::bigint
+`
+
+func (q *Queries) GetProductChangeWatermark(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getProductChangeWatermark)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listProductChangesAfter = `-- name: ListProductChangesAfter :many
+SELECT id, product_id, seller_id, change_type, product, tx_id, created_at FROM product_changes
+WHERE (tx_id, id) > ($1::bigint, $2::bigint)
+    AND tx_id < txid_snapshot_xmin(txid_current_snapshot())
+    AND ($3::uuid IS NULL OR seller_id = $3::uuid)
+    AND (cardinality($4::uuid[]) = 0 OR product_id = ANY($4::uuid[]))
+ORDER BY tx_id, id
+LIMIT $5
+`
+
+type ListProductChangesAfterParams struct {
+	AfterTxID  int64
+	AfterID    int64
+	SellerID   uuid.NullUUID
+	ProductIds []uuid.UUID
+	MaxRows    int32
+}
+
+func (q *Queries) ListProductChangesAfter(ctx context.Context, arg ListProductChangesAfterParams) ([]ProductChange, error) {
+	rows, err := q.db.QueryContext(ctx, listProductChangesAfter,
+		arg.AfterTxID,
+		arg.AfterID,
+		arg.SellerID,
+		pq.Array(arg.ProductIds),
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductChange
+	for rows.Next() {
+		var i ProductChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.SellerID,
+			&i.ChangeType,
+			&i.Product,
+			&i.TxID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
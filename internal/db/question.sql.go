@@ -0,0 +1,375 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: question.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const answerProductQuestion = `-- name: AnswerProductQuestion :one
+UPDATE product_questions
+SET answer = $2, answered_by = $3, answered_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at
+`
+
+type AnswerProductQuestionParams struct {
+	ID         uuid.UUID
+	Answer     sql.NullString
+	AnsweredBy uuid.NullUUID
+}
+
+func (q *Queries) AnswerProductQuestion(ctx context.Context, arg AnswerProductQuestionParams) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, answerProductQuestion, arg.ID, arg.Answer, arg.AnsweredBy)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Body,
+		&i.Answer,
+		&i.AnsweredBy,
+		&i.AnsweredAt,
+		&i.Upvotes,
+		&i.FlagCount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const flagProductQuestion = `-- name: FlagProductQuestion :execrows
+WITH inserted AS (
+  INSERT INTO product_question_flags (question_id, user_id, reason)
+  VALUES ($1, $2, $3)
+  ON CONFLICT (question_id, user_id) DO NOTHING
+  RETURNING question_id
+)
+UPDATE product_questions
+SET flag_count = flag_count + 1
+WHERE id IN (SELECT question_id FROM inserted)
+`
+
+type FlagProductQuestionParams struct {
+	QuestionID uuid.UUID
+	UserID     uuid.UUID
+	Reason     sql.NullString
+}
+
+func (q *Queries) FlagProductQuestion(ctx context.Context, arg FlagProductQuestionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, flagProductQuestion, arg.QuestionID, arg.UserID, arg.Reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFlaggedProductQuestions = `-- name: GetFlaggedProductQuestions :many
+SELECT id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at FROM product_questions
+WHERE flag_count > 0 AND status = $1
+ORDER BY flag_count DESC, created_at
+LIMIT $3 OFFSET $2
+`
+
+type GetFlaggedProductQuestionsParams struct {
+	Status    string
+	RowOffset int32
+	RowLimit  int32
+}
+
+func (q *Queries) GetFlaggedProductQuestions(ctx context.Context, arg GetFlaggedProductQuestionsParams) ([]ProductQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, getFlaggedProductQuestions, arg.Status, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductQuestion
+	for rows.Next() {
+		var i ProductQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Body,
+			&i.Answer,
+			&i.AnsweredBy,
+			&i.AnsweredAt,
+			&i.Upvotes,
+			&i.FlagCount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductQuestionByID = `-- name: GetProductQuestionByID :one
+SELECT id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at FROM product_questions WHERE id = $1
+`
+
+func (q *Queries) GetProductQuestionByID(ctx context.Context, id uuid.UUID) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, getProductQuestionByID, id)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Body,
+		&i.Answer,
+		&i.AnsweredBy,
+		&i.AnsweredAt,
+		&i.Upvotes,
+		&i.FlagCount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getProductQuestionsByProductID = `-- name: GetProductQuestionsByProductID :many
+SELECT id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at FROM product_questions
+WHERE product_id = $1 AND status = 'visible'
+ORDER BY
+  CASE WHEN $2::bool THEN upvotes END DESC,
+  created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type GetProductQuestionsByProductIDParams struct {
+	ProductID     uuid.UUID
+	SortByUpvotes bool
+	RowOffset     int32
+	RowLimit      int32
+}
+
+func (q *Queries) GetProductQuestionsByProductID(ctx context.Context, arg GetProductQuestionsByProductIDParams) ([]ProductQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, getProductQuestionsByProductID,
+		arg.ProductID,
+		arg.SortByUpvotes,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductQuestion
+	for rows.Next() {
+		var i ProductQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Body,
+			&i.Answer,
+			&i.AnsweredBy,
+			&i.AnsweredAt,
+			&i.Upvotes,
+			&i.FlagCount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnansweredQuestionsBySellerID = `-- name: GetUnansweredQuestionsBySellerID :many
+SELECT q.id, q.product_id, q.user_id, q.body, q.answer, q.answered_by, q.answered_at, q.upvotes, q.flag_count, q.status, q.created_at, q.updated_at FROM product_questions q
+JOIN products p ON p.id = q.product_id
+WHERE p.seller_id = $1
+  AND p.deleted_at IS NULL
+  AND q.answer IS NULL
+  AND q.status = 'visible'
+ORDER BY q.created_at
+LIMIT $3 OFFSET $2
+`
+
+type GetUnansweredQuestionsBySellerIDParams struct {
+	SellerID  uuid.UUID
+	RowOffset int32
+	RowLimit  int32
+}
+
+func (q *Queries) GetUnansweredQuestionsBySellerID(ctx context.Context, arg GetUnansweredQuestionsBySellerIDParams) ([]ProductQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, getUnansweredQuestionsBySellerID, arg.SellerID, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductQuestion
+	for rows.Next() {
+		var i ProductQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Body,
+			&i.Answer,
+			&i.AnsweredBy,
+			&i.AnsweredAt,
+			&i.Upvotes,
+			&i.FlagCount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProductQuestion = `-- name: InsertProductQuestion :one
+INSERT INTO product_questions (id, product_id, user_id, body)
+VALUES ($1, $2, $3, $4)
+RETURNING id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at
+`
+
+type InsertProductQuestionParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	UserID    uuid.UUID
+	Body      string
+}
+
+func (q *Queries) InsertProductQuestion(ctx context.Context, arg InsertProductQuestionParams) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, insertProductQuestion,
+		arg.ID,
+		arg.ProductID,
+		arg.UserID,
+		arg.Body,
+	)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Body,
+		&i.Answer,
+		&i.AnsweredBy,
+		&i.AnsweredAt,
+		&i.Upvotes,
+		&i.FlagCount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const removeProductQuestionUpvote = `-- name: RemoveProductQuestionUpvote :execrows
+WITH deleted AS (
+  DELETE FROM product_question_votes
+  WHERE product_question_votes.question_id = $1 AND product_question_votes.user_id = $2
+  RETURNING question_id
+)
+UPDATE product_questions
+SET upvotes = GREATEST(upvotes - 1, 0)
+WHERE id IN (SELECT question_id FROM deleted)
+`
+
+type RemoveProductQuestionUpvoteParams struct {
+	QuestionID uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) RemoveProductQuestionUpvote(ctx context.Context, arg RemoveProductQuestionUpvoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeProductQuestionUpvote, arg.QuestionID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setProductQuestionStatus = `-- name: SetProductQuestionStatus :one
+UPDATE product_questions
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, product_id, user_id, body, answer, answered_by, answered_at, upvotes, flag_count, status, created_at, updated_at
+`
+
+type SetProductQuestionStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+func (q *Queries) SetProductQuestionStatus(ctx context.Context, arg SetProductQuestionStatusParams) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, setProductQuestionStatus, arg.ID, arg.Status)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Body,
+		&i.Answer,
+		&i.AnsweredBy,
+		&i.AnsweredAt,
+		&i.Upvotes,
+		&i.FlagCount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upvoteProductQuestion = `-- name: UpvoteProductQuestion :execrows
+WITH inserted AS (
+  INSERT INTO product_question_votes (question_id, user_id)
+  VALUES ($1, $2)
+  ON CONFLICT (question_id, user_id) DO NOTHING
+  RETURNING question_id
+)
+UPDATE product_questions
+SET upvotes = upvotes + 1
+WHERE id IN (SELECT question_id FROM inserted)
+`
+
+type UpvoteProductQuestionParams struct {
+	QuestionID uuid.UUID
+	UserID     uuid.UUID
+}
+
+// Counter hanya naik jika vote benar-benar baru
+func (q *Queries) UpvoteProductQuestion(ctx context.Context, arg UpvoteProductQuestionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upvoteProductQuestion, arg.QuestionID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
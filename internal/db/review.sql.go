@@ -0,0 +1,436 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: review.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteProductReview = `-- name: DeleteProductReview :execrows
+DELETE FROM product_reviews WHERE id = $1
+`
+
+func (q *Queries) DeleteProductReview(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProductReview, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getProductReviewByID = `-- name: GetProductReviewByID :one
+SELECT id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at FROM product_reviews WHERE id = $1
+`
+
+func (q *Queries) GetProductReviewByID(ctx context.Context, id uuid.UUID) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, getProductReviewByID, id)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getProductReviewsByProductID = `-- name: GetProductReviewsByProductID :many
+SELECT id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at FROM product_reviews
+WHERE product_id = $1 AND status = 'approved'
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetProductReviewsByProductIDParams struct {
+	ProductID uuid.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) GetProductReviewsByProductID(ctx context.Context, arg GetProductReviewsByProductIDParams) ([]ProductReview, error) {
+	rows, err := q.db.QueryContext(ctx, getProductReviewsByProductID, arg.ProductID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductReview
+	for rows.Next() {
+		var i ProductReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Rating,
+			&i.Title,
+			&i.Body,
+			&i.VerifiedPurchase,
+			&i.Status,
+			&i.SellerReply,
+			&i.SellerRepliedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductReviewsByStatus = `-- name: GetProductReviewsByStatus :many
+SELECT id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at FROM product_reviews
+WHERE status = $1
+ORDER BY created_at
+LIMIT $2 OFFSET $3
+`
+
+type GetProductReviewsByStatusParams struct {
+	Status string
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetProductReviewsByStatus(ctx context.Context, arg GetProductReviewsByStatusParams) ([]ProductReview, error) {
+	rows, err := q.db.QueryContext(ctx, getProductReviewsByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductReview
+	for rows.Next() {
+		var i ProductReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Rating,
+			&i.Title,
+			&i.Body,
+			&i.VerifiedPurchase,
+			&i.Status,
+			&i.SellerReply,
+			&i.SellerRepliedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hasUserPurchased = `-- name: HasUserPurchased :one
+SELECT EXISTS (
+  SELECT 1 FROM user_purchases WHERE user_id = $1 AND product_id = $2
+)
+`
+
+type HasUserPurchasedParams struct {
+	UserID    uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) HasUserPurchased(ctx context.Context, arg HasUserPurchasedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasUserPurchased, arg.UserID, arg.ProductID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const insertProductReview = `-- name: InsertProductReview :one
+INSERT INTO product_reviews (
+  id,
+  product_id,
+  user_id,
+  rating,
+  title,
+  body,
+  verified_purchase,
+  status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (product_id, user_id) DO NOTHING -- satu review per user per produk
+RETURNING id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at
+`
+
+type InsertProductReviewParams struct {
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	UserID           uuid.UUID
+	Rating           int16
+	Title            string
+	Body             string
+	VerifiedPurchase bool
+	Status           string
+}
+
+func (q *Queries) InsertProductReview(ctx context.Context, arg InsertProductReviewParams) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, insertProductReview,
+		arg.ID,
+		arg.ProductID,
+		arg.UserID,
+		arg.Rating,
+		arg.Title,
+		arg.Body,
+		arg.VerifiedPurchase,
+		arg.Status,
+	)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertUserPurchase = `-- name: InsertUserPurchase :exec
+INSERT INTO user_purchases (user_id, product_id, order_id, purchased_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, product_id) DO NOTHING
+`
+
+type InsertUserPurchaseParams struct {
+	UserID      uuid.UUID
+	ProductID   uuid.UUID
+	OrderID     string
+	PurchasedAt time.Time
+}
+
+func (q *Queries) InsertUserPurchase(ctx context.Context, arg InsertUserPurchaseParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserPurchase,
+		arg.UserID,
+		arg.ProductID,
+		arg.OrderID,
+		arg.PurchasedAt,
+	)
+	return err
+}
+
+const lockProductReviewByID = `-- name: LockProductReviewByID :one
+SELECT id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at FROM product_reviews WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockProductReviewByID(ctx context.Context, id uuid.UUID) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, lockProductReviewByID, id)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markProductReviewVerified = `-- name: MarkProductReviewVerified :execrows
+UPDATE product_reviews
+SET verified_purchase = TRUE
+WHERE user_id = $1 AND product_id = $2 AND NOT verified_purchase
+`
+
+type MarkProductReviewVerifiedParams struct {
+	UserID    uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) MarkProductReviewVerified(ctx context.Context, arg MarkProductReviewVerifiedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markProductReviewVerified, arg.UserID, arg.ProductID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const refreshProductRating = `-- name: RefreshProductRating :one
+UPDATE products
+SET
+  rating_avg = COALESCE((
+    SELECT AVG(r.rating)::float8 FROM product_reviews r
+    WHERE r.product_id = products.id AND r.status = 'approved'
+  ), 0),
+  rating_count = (
+    SELECT COUNT(*)::int FROM product_reviews r
+    WHERE r.product_id = products.id AND r.status = 'approved'
+  )
+WHERE products.id = $1
+RETURNING id, seller_id, name, price, stock, discount, type, description, created_at, updated_at, deleted_at, external_sku, rating_avg, rating_count, status, status_reason, status_changed_at, attributes
+`
+
+// Agregat hanya menghitung review yang sudah disetujui moderator
+func (q *Queries) RefreshProductRating(ctx context.Context, productID uuid.UUID) (Product, error) {
+	row := q.db.QueryRowContext(ctx, refreshProductRating, productID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.Discount,
+		&i.Type,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.Attributes,
+	)
+	return i, err
+}
+
+const setProductReviewReply = `-- name: SetProductReviewReply :one
+UPDATE product_reviews
+SET seller_reply = $2, seller_replied_at = NOW()
+WHERE id = $1
+RETURNING id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at
+`
+
+type SetProductReviewReplyParams struct {
+	ID          uuid.UUID
+	SellerReply sql.NullString
+}
+
+func (q *Queries) SetProductReviewReply(ctx context.Context, arg SetProductReviewReplyParams) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, setProductReviewReply, arg.ID, arg.SellerReply)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setProductReviewStatus = `-- name: SetProductReviewStatus :one
+UPDATE product_reviews
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at
+`
+
+type SetProductReviewStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+func (q *Queries) SetProductReviewStatus(ctx context.Context, arg SetProductReviewStatusParams) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, setProductReviewStatus, arg.ID, arg.Status)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateProductReview = `-- name: UpdateProductReview :one
+UPDATE product_reviews
+SET rating = $2, title = $3, body = $4, status = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING id, product_id, user_id, rating, title, body, verified_purchase, status, seller_reply, seller_replied_at, created_at, updated_at
+`
+
+type UpdateProductReviewParams struct {
+	ID     uuid.UUID
+	Rating int16
+	Title  string
+	Body   string
+	Status string
+}
+
+func (q *Queries) UpdateProductReview(ctx context.Context, arg UpdateProductReviewParams) (ProductReview, error) {
+	row := q.db.QueryRowContext(ctx, updateProductReview,
+		arg.ID,
+		arg.Rating,
+		arg.Title,
+		arg.Body,
+		arg.Status,
+	)
+	var i ProductReview
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.VerifiedPurchase,
+		&i.Status,
+		&i.SellerReply,
+		&i.SellerRepliedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
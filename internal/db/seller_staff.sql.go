@@ -0,0 +1,93 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: seller_staff.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addSellerStaff = `-- name: AddSellerStaff :one
+INSERT INTO seller_staff (seller_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (seller_id, user_id) DO UPDATE SET seller_id = EXCLUDED.seller_id
+RETURNING seller_id, user_id, created_at
+`
+
+type AddSellerStaffParams struct {
+	SellerID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) AddSellerStaff(ctx context.Context, arg AddSellerStaffParams) (SellerStaff, error) {
+	row := q.db.QueryRowContext(ctx, addSellerStaff, arg.SellerID, arg.UserID)
+	var i SellerStaff
+	err := row.Scan(&i.SellerID, &i.UserID, &i.CreatedAt)
+	return i, err
+}
+
+const getSellerStaff = `-- name: GetSellerStaff :many
+SELECT seller_id, user_id, created_at FROM seller_staff WHERE seller_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetSellerStaff(ctx context.Context, sellerID uuid.UUID) ([]SellerStaff, error) {
+	rows, err := q.db.QueryContext(ctx, getSellerStaff, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SellerStaff
+	for rows.Next() {
+		var i SellerStaff
+		if err := rows.Scan(&i.SellerID, &i.UserID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isSellerStaff = `-- name: IsSellerStaff :one
+SELECT EXISTS (
+  SELECT 1 FROM seller_staff WHERE seller_id = $1 AND user_id = $2
+)
+`
+
+type IsSellerStaffParams struct {
+	SellerID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) IsSellerStaff(ctx context.Context, arg IsSellerStaffParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isSellerStaff, arg.SellerID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const removeSellerStaff = `-- name: RemoveSellerStaff :execrows
+DELETE FROM seller_staff WHERE seller_id = $1 AND user_id = $2
+`
+
+type RemoveSellerStaffParams struct {
+	SellerID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) RemoveSellerStaff(ctx context.Context, arg RemoveSellerStaffParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeSellerStaff, arg.SellerID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
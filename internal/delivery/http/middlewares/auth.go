@@ -1,11 +1,15 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
-	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/grpc/account"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/services"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/labstack/echo/v4"
@@ -32,7 +36,8 @@ func RequireRoles(allowedRoles ...string) echo.MiddlewareFunc {
 		}
 	}
 }
-func AuthMiddleware(authClient *account.AuthClient, log *logrus.Logger) echo.MiddlewareFunc {
+
+func AuthMiddleware(authService services.AuthService, log *logrus.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
@@ -47,18 +52,55 @@ func AuthMiddleware(authClient *account.AuthClient, log *logrus.Logger) echo.Mid
 				return c.JSON(http.StatusBadRequest, echo.Map{"message": "Invalid token format (expected Bearer token)"})
 			}
 
-			isValid, userID, username, role, errMsg, err := authClient.ValidateToken(token)
+			identity, err := authService.Authenticate(c.Request().Context(), token)
 			if err != nil {
+				switch {
+				case errors.Is(err, apperrors.ErrInvalidToken), errors.Is(err, apperrors.ErrTokenRevoked):
+					return c.JSON(http.StatusUnauthorized, echo.Map{"message": err.Error()})
+				case errors.Is(err, apperrors.ErrAuthUnavailable):
+					return c.JSON(http.StatusServiceUnavailable, echo.Map{"message": apperrors.ErrAuthUnavailable.Error()})
+				}
+
+				log.WithError(err).Error("Unexpected error during token validation")
 				return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Server error during token validation"})
 			}
 
-			if !isValid {
-				return c.JSON(http.StatusUnauthorized, echo.Map{"message": errMsg})
+			c.Set("userID", identity.UserID)
+			c.Set("username", identity.Username)
+			c.Set("role", identity.Role)
+
+			return next(c)
+		}
+	}
+}
+
+// GuestTokenHeader dipakai untuk mengenali pengunjung yang belum login; token baru dikirim balik lewat header yang sama
+const GuestTokenHeader = "X-Guest-Token"
+
+// OptionalAuthMiddleware dipakai di route publik: token yang valid mengisi identitas user,
+// sedangkan request tanpa token (atau token tidak valid) tetap dilayani sebagai guest
+func OptionalAuthMiddleware(authService services.AuthService, log *logrus.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if len(authHeader) > 7 && strings.HasPrefix(authHeader, "Bearer ") {
+				identity, err := authService.Authenticate(c.Request().Context(), authHeader[7:])
+				if err == nil {
+					c.Set("userID", identity.UserID)
+					c.Set("username", identity.Username)
+					c.Set("role", identity.Role)
+					return next(c)
+				}
+				log.WithError(err).Debug("Optional authentication failed, continuing as guest")
 			}
 
-			c.Set("userID", userID)
-			c.Set("username", username)
-			c.Set("role", role)
+			guestToken := c.Request().Header.Get(GuestTokenHeader)
+			if _, err := uuid.Parse(guestToken); err != nil {
+				guestToken = helpers.GenerateNewID().String()
+				c.Set("newGuest", true)
+			}
+			c.Response().Header().Set(GuestTokenHeader, guestToken)
+			c.Set("guestToken", guestToken)
 
 			return next(c)
 		}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+)
+
+// MetricsMiddleware memakai template route (c.Path()) sebagai label supaya kardinalitas tidak meledak karena ID di URL
+func MetricsMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+
+			metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			metrics.HTTPRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+
+			return nil
+		}
+	}
+}
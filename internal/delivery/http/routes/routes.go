@@ -1,22 +1,30 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/delivery/http/middlewares"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/handlers"
 
 	"github.com/labstack/echo/v4"
 )
 
-func InitRoutes(e *echo.Echo, handler *handlers.API, authMiddleware echo.MiddlewareFunc) {
+func InitRoutes(e *echo.Echo, handler *handlers.API, authMiddleware, optionalAuthMiddleware echo.MiddlewareFunc) {
 
 	publicGroup := e.Group("/api/v1")
 
-	productPublicGroup := publicGroup.Group("/products")
+	productPublicGroup := publicGroup.Group("/products", optionalAuthMiddleware)
 	{
 		productPublicGroup.GET("/", handler.GetAllProducts())
+		productPublicGroup.GET("/recently-viewed", handler.GetRecentlyViewedProducts())
+		productPublicGroup.GET("/trending", handler.GetTrendingProducts())
 		productPublicGroup.GET("/name/:name", handler.GetProductsByName())
 		productPublicGroup.GET("/category/:type", handler.GetProductsByType())
+		productPublicGroup.GET("/category/:type/attributes", handler.GetAttributeDefinitions())
 		productPublicGroup.GET("/:id", handler.GetProductByID())
+		productPublicGroup.GET("/:id/related", handler.GetRelatedProducts())
+		productPublicGroup.GET("/:id/reviews", handler.GetProductReviews())
+		productPublicGroup.GET("/:id/questions", handler.GetProductQuestions())
 		productPublicGroup.GET("/seller/:seller_id", handler.GetProductsBySellerID())
 	}
 
@@ -26,9 +34,68 @@ func InitRoutes(e *echo.Echo, handler *handlers.API, authMiddleware echo.Middlew
 	productAuthGroup := authGroup.Group("/products")
 	{
 		productAuthGroup.POST("/create", handler.CreateProduct(), middlewares.RequireRoles("admin", "seller"))
-		productAuthGroup.PUT("/update/:product_id", handler.UpdateProduct(), middlewares.RequireRoles("admin", "seller"))
+		productAuthGroup.PUT("/update/:product_id", handler.UpdateProduct(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
+		productAuthGroup.PUT("/status/:product_id", handler.ChangeProductStatus(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
 		productAuthGroup.DELETE("/delete/:product_id", handler.DeleteProduct(), middlewares.RequireRoles("admin", "seller"))
+		productAuthGroup.POST("/bulk", handler.BulkUpdateProducts(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
+		productAuthGroup.POST("/import", handler.ImportProducts(), middlewares.RequireRoles("admin", "seller"))
+		productAuthGroup.GET("/import/:job_id", handler.GetImportJob(), middlewares.RequireRoles("admin", "seller"))
+		productAuthGroup.GET("/export", handler.ExportProducts(), middlewares.RequireRoles("admin", "seller"))
 		productAuthGroup.DELETE("/clear-cache", handler.ClearProductCaches(), middlewares.RequireRoles("admin")) // Reset cache harus diproteksi
+		productAuthGroup.POST("/:id/reviews", handler.CreateReview())
+		productAuthGroup.POST("/:id/questions", handler.AskQuestion())
+	}
+
+	reviewGroup := authGroup.Group("/reviews")
+	{
+		reviewGroup.PUT("/:review_id", handler.UpdateReview())
+		reviewGroup.DELETE("/:review_id", handler.DeleteReview())
+		reviewGroup.PUT("/:review_id/reply", handler.ReplyToReview(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
+	}
+
+	questionGroup := authGroup.Group("/questions")
+	{
+		questionGroup.PUT("/:question_id/answer", handler.AnswerQuestion(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
+		questionGroup.POST("/:question_id/upvote", handler.UpvoteQuestion())
+		questionGroup.DELETE("/:question_id/upvote", handler.RemoveQuestionUpvote())
+		questionGroup.POST("/:question_id/flag", handler.FlagQuestion())
+	}
+
+	authGroup.GET("/sellers/questions/inbox", handler.GetSellerQuestionInbox(), middlewares.RequireRoles("admin", "seller", "seller_staff"))
+
+	questionAdminGroup := authGroup.Group("/admin/questions", middlewares.RequireRoles("admin"))
+	{
+		questionAdminGroup.GET("/flagged", handler.GetFlaggedQuestions())
+		questionAdminGroup.PUT("/:question_id/status", handler.ModerateQuestion())
+	}
+
+	attributeAdminGroup := authGroup.Group("/admin/attributes", middlewares.RequireRoles("admin"))
+	{
+		attributeAdminGroup.PUT("/:type/:key", handler.UpsertAttributeDefinition())
+		attributeAdminGroup.DELETE("/:type/:key", handler.DeleteAttributeDefinition())
+	}
+
+	reviewAdminGroup := authGroup.Group("/admin/reviews", middlewares.RequireRoles("admin"))
+	{
+		reviewAdminGroup.GET("/", handler.GetReviewsForModeration())
+		reviewAdminGroup.PUT("/:review_id/status", handler.ModerateReview())
+	}
+
+	cacheAdminGroup := authGroup.Group("/admin/cache", middlewares.RequireRoles("admin"))
+	{
+		cacheAdminGroup.GET("/", handler.GetCacheStats())
+		cacheAdminGroup.DELETE("/families/:family", handler.PurgeCacheFamily())
+		cacheAdminGroup.DELETE("/sellers/:seller_id", handler.PurgeSellerCache())
+		cacheAdminGroup.DELETE("/types/:type", handler.PurgeTypeCache())
+		cacheAdminGroup.DELETE("/products/:product_id", handler.PurgeProductCache())
+		cacheAdminGroup.POST("/warm", handler.WarmCache())
+	}
+
+	sellerStaffGroup := authGroup.Group("/sellers/staff", middlewares.RequireRoles("seller"))
+	{
+		sellerStaffGroup.GET("/", handler.GetSellerStaff())
+		sellerStaffGroup.POST("/:user_id", handler.AddSellerStaff())
+		sellerStaffGroup.DELETE("/:user_id", handler.RemoveSellerStaff())
 	}
 
 	cartGroup := authGroup.Group("/cart")
@@ -37,5 +104,16 @@ func InitRoutes(e *echo.Echo, handler *handlers.API, authMiddleware echo.Middlew
 		cartGroup.POST("/add/:product_id", handler.AddToCart())
 		cartGroup.PUT("/update/:product_id", handler.UpdateCartItem())
 		cartGroup.DELETE("/remove/:product_id", handler.RemoveFromCart())
+		cartGroup.PUT("/sellers/:seller_id/note", handler.SetCartSellerNote())
 	}
 }
+
+// InitHealthRoutes didaftarkan di root (tanpa /api/v1 dan tanpa auth) untuk probe orchestrator
+func InitHealthRoutes(e *echo.Echo, healthHandler *handlers.HealthHandler) {
+	e.GET("/healthz", healthHandler.Liveness())
+	e.GET("/readyz", healthHandler.Readiness())
+}
+
+func InitMetricsRoutes(e *echo.Echo, metricsHandler http.Handler) {
+	e.GET("/metrics", echo.WrapHandler(metricsHandler))
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "github.com/google/uuid"
+
+type CacheFamilyStats struct {
+	Family      string `json:"family"`
+	Keys        int    `json:"keys"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// CachePurgeTarget hanya boleh berisi salah satu field
+type CachePurgeTarget struct {
+	Family    string
+	SellerID  uuid.UUID
+	Type      string
+	ProductID uuid.UUID
+}
+
+// CacheWarmTarget mengisi cache untuk katalog seller jika SellerID diisi, atau TopN produk jika tidak
+type CacheWarmTarget struct {
+	TopN     int
+	SellerID uuid.UUID
+}
@@ -12,8 +12,12 @@ type CartItem struct {
 	Stock           int
 	SellerID        uuid.UUID
 	SellerName      string
+	SellerUnknown   bool // nama seller tidak bisa diambil dari account service maupun cache
 	Quantity        int
 	Description     string
+	GiftWrap        bool
+	GiftMessage     string
+	VariantNote     string
 	Checked         bool
 }
 
@@ -21,4 +25,8 @@ type Cart struct {
 	UserID     uuid.UUID
 	Items      []CartItem
 	TotalItems int
+	Degraded   bool // sebagian data pelengkap (mis. nama seller) tidak tersedia
+	// SellerNotes berisi catatan pengiriman per seller yang masih punya item di cart
+	SellerNotes map[string]string
+	Version     int64 // naik setiap kali cart berubah, dipakai untuk If-Match
 }
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Identity adalah hasil autentikasi sebuah access token
+type Identity struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Viewer adalah pengunjung katalog; UserID kosong berarti guest yang dikenali lewat GuestToken.
+// NewGuest menandai token yang baru dibuat di request ini (klien belum pernah mengirimnya balik).
+type Viewer struct {
+	UserID     string
+	GuestToken string
+	NewGuest   bool
+}
+
+func (v Viewer) IsGuest() bool {
+	return v.UserID == ""
+}
+
+func (v Viewer) IsZero() bool {
+	return v.UserID == "" && v.GuestToken == ""
+}
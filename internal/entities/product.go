@@ -7,6 +7,15 @@ import (
 	"gorm.io/gorm"
 )
 
+type ProductStatus string
+
+const (
+	ProductStatusDraft    ProductStatus = "draft"
+	ProductStatusActive   ProductStatus = "active"
+	ProductStatusArchived ProductStatus = "archived"
+	ProductStatusBanned   ProductStatus = "banned"
+)
+
 type Product struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	SellerID    uuid.UUID `gorm:"type:uuid" json:"seller_id"`
@@ -16,12 +25,32 @@ type Product struct {
 	Discount    int       `json:"discount"`
 	Type        string    `json:"type"`
 	Description string    `gorm:"type:text" json:"description"`
+	ExternalSKU string    `json:"external_sku,omitempty"`
+	RatingAvg   float64   `json:"rating_avg"`
+	RatingCount int       `json:"rating_count"`
+
+	Status       ProductStatus `json:"status"`
+	StatusReason string        `json:"status_reason,omitempty"`
+
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
 
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
 }
 
+// ProductPage adalah satu halaman listing produk active; NextPageToken kosong jika sudah halaman terakhir
+type ProductPage struct {
+	Products      []Product
+	NextPageToken string
+	TotalSize     int
+}
+
+// IsActive menganggap status kosong sebagai active karena entry cache lama dibuat sebelum kolom status ada
+func (c *Product) IsActive() bool {
+	return c.Status == ProductStatusActive || c.Status == ""
+}
+
 func (c *Product) TableName() string {
 	return "product"
 }
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttributeDataType string
+
+const (
+	AttributeTypeString  AttributeDataType = "string"
+	AttributeTypeNumber  AttributeDataType = "number"
+	AttributeTypeBoolean AttributeDataType = "boolean"
+)
+
+// AttributeDefinition mendeskripsikan satu atribut yang boleh (atau wajib) dimiliki produk dengan type tertentu
+type AttributeDefinition struct {
+	ProductType   string
+	Key           string
+	DataType      AttributeDataType
+	Required      bool
+	AllowedValues []string
+	Unit          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type AttributeOperator string
+
+const (
+	AttributeOpEq  AttributeOperator = "="
+	AttributeOpNe  AttributeOperator = "!="
+	AttributeOpGt  AttributeOperator = ">"
+	AttributeOpGte AttributeOperator = ">="
+	AttributeOpLt  AttributeOperator = "<"
+	AttributeOpLte AttributeOperator = "<="
+)
+
+// AttributeCondition adalah satu filter attr.* dari query string, mis. attr.ram_gb>=8
+type AttributeCondition struct {
+	Key      string
+	Operator AttributeOperator
+	Value    string
+}
+
+// ProductSearchQuery membatasi pencarian ke list yang sama dengan endpoint asalnya (semua, nama, type atau seller).
+// MinRating nil berarti tanpa batas rating.
+type ProductSearchQuery struct {
+	Name         string
+	Type         string
+	SellerID     uuid.UUID
+	Conditions   []AttributeCondition
+	MinRating    *float64
+	SortByRating bool
+	Limit        int
+	Offset       int
+}
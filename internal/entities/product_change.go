@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProductChangeCreated      = "created"
+	ProductChangeUpdated      = "updated"
+	ProductChangeDeleted      = "deleted"
+	ProductChangeStockChanged = "stock_changed"
+)
+
+// ProductChangePosition adalah posisi di change log, diurutkan berdasarkan (TxID, ID)
+type ProductChangePosition struct {
+	TxID int64
+	ID   int64
+}
+
+// ProductChange adalah satu baris change log produk; Product berisi snapshot setelah perubahan (atau sebelum dihapus)
+type ProductChange struct {
+	Position   ProductChangePosition
+	Type       string
+	Product    Product
+	OccurredAt time.Time
+}
+
+type ProductChangeFilter struct {
+	SellerID   uuid.NullUUID
+	ProductIDs []uuid.UUID
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+type ProductImportRowError struct {
+	Row         int    `json:"row"`
+	ExternalSKU string `json:"external_sku,omitempty"`
+	Message     string `json:"message"`
+}
+
+type ProductImportJob struct {
+	ID         uuid.UUID               `json:"id"`
+	SellerID   uuid.UUID               `json:"seller_id"`
+	Format     string                  `json:"format"`
+	Status     string                  `json:"status"`
+	TotalRows  int                     `json:"total_rows"`
+	Succeeded  int                     `json:"succeeded"`
+	Failed     int                     `json:"failed"`
+	Errors     []ProductImportRowError `json:"errors"`
+	CreatedAt  time.Time               `json:"created_at"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+}
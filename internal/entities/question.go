@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuestionStatus diatur admin; pertanyaan hidden tidak tampil di halaman produk maupun inbox seller
+type QuestionStatus string
+
+const (
+	QuestionStatusVisible QuestionStatus = "visible"
+	QuestionStatusHidden  QuestionStatus = "hidden"
+)
+
+// QuestionSort menentukan urutan pertanyaan di halaman produk
+type QuestionSort string
+
+const (
+	QuestionSortRecent QuestionSort = "recent"
+	QuestionSortTop    QuestionSort = "top"
+)
+
+type ProductQuestion struct {
+	ID         uuid.UUID
+	ProductID  uuid.UUID
+	UserID     uuid.UUID
+	Body       string
+	Answer     string
+	AnsweredBy uuid.UUID
+	AnsweredAt *time.Time
+	Upvotes    int
+	FlagCount  int
+	Status     QuestionStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,15 @@
+package entities
+
+// RelatedReason menjelaskan dari mana sebuah rekomendasi produk berasal
+type RelatedReason string
+
+const (
+	RelatedReasonCoPurchase RelatedReason = "co_purchase"
+	RelatedReasonSameType   RelatedReason = "same_type"
+	RelatedReasonSameSeller RelatedReason = "same_seller"
+)
+
+type RelatedProduct struct {
+	Product Product
+	Reason  RelatedReason
+}
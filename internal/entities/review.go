@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewStatus adalah status moderasi review; hanya review approved yang tampil dan dihitung ke rating produk
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+type ProductReview struct {
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	UserID           uuid.UUID
+	Rating           int
+	Title            string
+	Body             string
+	VerifiedPurchase bool
+	Status           ReviewStatus
+	SellerReply      string
+	SellerRepliedAt  *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,13 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SellerStaff struct {
+	SellerID  uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "github.com/google/uuid"
+
+// TrendingSignal adalah jenis interaksi yang menaikkan skor trending sebuah produk
+type TrendingSignal string
+
+const (
+	TrendingSignalView      TrendingSignal = "view"
+	TrendingSignalAddToCart TrendingSignal = "add_to_cart"
+	TrendingSignalPurchase  TrendingSignal = "purchase"
+)
+
+type TrendingScore struct {
+	ProductID uuid.UUID
+	Score     float64
+}
+
+type TrendingProduct struct {
+	Product Product
+	Score   float64
+}
@@ -0,0 +1,77 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/tracing"
+)
+
+// OrderEventConsumer membaca OrderCreatedEvent dari exchange order lewat queue milik catalog
+type OrderEventConsumer struct {
+	channel *amqp.Channel
+	queue   string
+	log     *logrus.Logger
+}
+
+func NewOrderEventConsumer(ch *amqp.Channel, queue string, log *logrus.Logger) (*OrderEventConsumer, error) {
+	if err := ch.ExchangeDeclare(OrderExchange, "fanout", true, false, false, false, nil); err != nil {
+		return nil, fmt.Errorf("gagal mendeklarasikan exchange: %w", err)
+	}
+
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return nil, fmt.Errorf("gagal mendeklarasikan queue %s: %w", queue, err)
+	}
+
+	if err := ch.QueueBind(queue, "", OrderExchange, false, nil); err != nil {
+		return nil, fmt.Errorf("gagal bind queue %s: %w", queue, err)
+	}
+
+	return &OrderEventConsumer{channel: ch, queue: queue, log: log}, nil
+}
+
+// Consume memanggil handle untuk setiap event sampai ctx selesai atau channel ditutup.
+// Pesan yang gagal diproses dikembalikan ke queue sekali; jika gagal lagi pesan dibuang.
+func (c *OrderEventConsumer) Consume(ctx context.Context, handle func(ctx context.Context, event models.OrderCreatedEvent) error) error {
+	deliveries, err := c.channel.Consume(c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start consuming %s: %w", c.queue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case d, ok := <-deliveries:
+			if !ok {
+				return amqp.ErrClosed
+			}
+			c.handleDelivery(ctx, d, handle)
+		}
+	}
+}
+
+func (c *OrderEventConsumer) handleDelivery(ctx context.Context, d amqp.Delivery, handle func(ctx context.Context, event models.OrderCreatedEvent) error) {
+	var event models.OrderCreatedEvent
+	if err := json.Unmarshal(d.Body, &event); err != nil {
+		c.log.WithError(err).Warn("Failed to decode OrderCreated event, message dropped")
+		_ = d.Nack(false, false)
+		return
+	}
+
+	logger := c.log.WithField("order_id", event.OrderID)
+	if err := handle(tracing.ExtractAMQP(ctx, d.Headers), event); err != nil {
+		logger.WithError(err).WithField("redelivered", d.Redelivered).Warn("Failed to process OrderCreated event")
+		_ = d.Nack(false, !d.Redelivered)
+		return
+	}
+
+	if err := d.Ack(false); err != nil {
+		logger.WithError(err).Warn("Failed to ack OrderCreated event")
+	}
+}
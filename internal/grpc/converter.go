@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+
+	productpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/product"
+)
+
+func toProtoProduct(p *entities.Product) *productpb.Product {
+	return &productpb.Product{
+		Id:          p.ID.String(),
+		SellerId:    p.SellerID.String(),
+		Name:        p.Name,
+		Price:       int32(p.Price),
+		Stock:       int32(p.Stock),
+		Description: p.Description,
+		CreatedAt:   timestamppb.New(p.CreatedAt),
+		UpdatedAt:   timestamppb.New(p.UpdatedAt),
+	}
+}
+
+func toProtoProducts(products []entities.Product) []*productpb.Product {
+	pbProducts := make([]*productpb.Product, len(products))
+	for i := range products {
+		pbProducts[i] = toProtoProduct(&products[i])
+	}
+
+	return pbProducts
+}
+
+func toProtoProductPtrs(products []*entities.Product) []*productpb.Product {
+	pbProducts := make([]*productpb.Product, len(products))
+	for i, p := range products {
+		pbProducts[i] = toProtoProduct(p)
+	}
+
+	return pbProducts
+}
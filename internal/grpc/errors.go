@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/grpc/interceptors"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+// PreconditionFailure.Violation.Type untuk kekurangan stok dan produk yang tidak active
+const (
+	violationTypeStock  = "STOCK"
+	violationTypeStatus = "STATUS"
+)
+
+// toStatusError memetakan error dari service ke status gRPC yang konsisten, lengkap dengan errdetails jika tersedia.
+// action dipakai sebagai prefix pesan, mis. "failed to decrease stock". Error yang tidak dikenal hanya dicatat di log
+// dan dikembalikan sebagai Internal dengan pesan tetap, supaya detail internal tidak bocor ke pemanggil.
+func (s *ProductServer) toStatusError(ctx context.Context, err error, action string) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	msg := fmt.Sprintf("%s: %v", action, err)
+
+	var shortageErr *apperrors.StockShortageError
+	if errors.As(err, &shortageErr) {
+		violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(shortageErr.Shortages))
+		for _, s := range shortageErr.Shortages {
+			violations = append(violations, &errdetails.PreconditionFailure_Violation{
+				Type:        violationTypeStock,
+				Subject:     fmt.Sprintf("product:%s", s.ProductID),
+				Description: fmt.Sprintf("requested %d, available %d", s.Requested, s.Available),
+			})
+		}
+		return withDetails(codes.FailedPrecondition, msg, &errdetails.PreconditionFailure{Violations: violations})
+	}
+
+	var notActiveErr *apperrors.ProductsNotActiveError
+	if errors.As(err, &notActiveErr) {
+		violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(notActiveErr.Products))
+		for _, p := range notActiveErr.Products {
+			violations = append(violations, &errdetails.PreconditionFailure_Violation{
+				Type:        violationTypeStatus,
+				Subject:     fmt.Sprintf("product:%s", p.ProductID),
+				Description: fmt.Sprintf("product status is %s", p.Status),
+			})
+		}
+		return withDetails(codes.FailedPrecondition, msg, &errdetails.PreconditionFailure{Violations: violations})
+	}
+
+	var notFoundErr *apperrors.ProductsNotFoundError
+	if errors.As(err, &notFoundErr) {
+		details := make([]protoadapt.MessageV1, 0, len(notFoundErr.ProductIDs))
+		for _, id := range notFoundErr.ProductIDs {
+			details = append(details, &errdetails.ResourceInfo{
+				ResourceType: "product",
+				ResourceName: id,
+				Description:  apperrors.ErrProductNotFound.Error(),
+			})
+		}
+		return withDetails(codes.NotFound, msg, details...)
+	}
+
+	switch {
+	case errors.Is(err, apperrors.ErrProductNotFound),
+		errors.Is(err, apperrors.ErrImportJobNotFound),
+		errors.Is(err, apperrors.ErrSellerStaffNotFound):
+		return status.Error(codes.NotFound, msg)
+
+	case errors.Is(err, apperrors.ErrProductOutOfStock),
+		errors.Is(err, apperrors.ErrInsufficientStock),
+		errors.Is(err, apperrors.ErrProductNotActive),
+		errors.Is(err, apperrors.ErrInvalidProductTransition),
+		errors.Is(err, apperrors.ErrResumeTokenExpired):
+		return status.Error(codes.FailedPrecondition, msg)
+
+	case errors.Is(err, apperrors.ErrInvalidRequestPayload),
+		errors.Is(err, apperrors.ErrInvalidUserInput),
+		errors.Is(err, apperrors.ErrInvalidResumeToken),
+		errors.Is(err, apperrors.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, msg)
+
+	case errors.Is(err, apperrors.ErrActionNotPermitted),
+		errors.Is(err, apperrors.ErrProductNotBelongToSeller):
+		return status.Error(codes.PermissionDenied, msg)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"request_id": interceptors.RequestIDFromContext(ctx),
+		"action":     action,
+	}).WithError(err).Error("gRPC handler failed with an internal error")
+	return status.Error(codes.Internal, "internal error")
+}
+
+// invalidArgument membuat status InvalidArgument dengan BadRequest.FieldViolation untuk field yang salah
+func invalidArgument(field, description string) error {
+	return withDetails(codes.InvalidArgument, fmt.Sprintf("invalid %s: %s", field, description), &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	})
+}
+
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, msg).WithDetails(details...)
+	if err != nil {
+		return status.Error(code, msg)
+	}
+
+	return st.Err()
+}
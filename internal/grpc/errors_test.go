@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func TestToStatusErrorHidesInternalErrors(t *testing.T) {
+	var logs bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&logs)
+
+	s := NewProductServer(nil, nil, log)
+
+	err := s.toStatusError(context.Background(), fmt.Errorf("service: %w", errors.New("pq: connection refused to 10.0.0.5:5432")), "failed to decrease stock")
+	st, _ := status.FromError(err)
+	if st.Code() != codes.Internal || st.Message() != "internal error" {
+		t.Fatalf("expected fixed internal error, got %v: %q", st.Code(), st.Message())
+	}
+
+	if !strings.Contains(logs.String(), "10.0.0.5:5432") || !strings.Contains(logs.String(), "failed to decrease stock") {
+		t.Fatalf("expected the wrapped error to be logged, got %q", logs.String())
+	}
+}
+
+func TestToStatusErrorMapsKnownErrors(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(&bytes.Buffer{})
+	s := NewProductServer(nil, nil, log)
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{fmt.Errorf("%w: product x", apperrors.ErrProductNotFound), codes.NotFound},
+		{apperrors.ErrInsufficientStock, codes.FailedPrecondition},
+		{fmt.Errorf("%w: bad id", apperrors.ErrInvalidRequestPayload), codes.InvalidArgument},
+		{apperrors.ErrActionNotPermitted, codes.PermissionDenied},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.Unavailable, "down"), codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := status.Code(s.toStatusError(context.Background(), tt.err, "failed")); got != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AuthModeSecret = "secret"
+	AuthModeMTLS   = "mtls"
+
+	ServiceNameHeader   = "x-service-name"
+	ServiceSecretHeader = "x-service-secret"
+)
+
+// healthMethodPrefix dikecualikan dari ServiceAuth karena dipanggil oleh probe orchestrator, bukan service internal
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+type serviceNameKey struct{}
+
+type ServiceAuthConfig struct {
+	Mode            string
+	Secrets         map[string]string // nama service -> shared secret (mode secret)
+	AllowedServices []string          // CN sertifikat client yang diizinkan (mode mtls)
+}
+
+// ServiceAuth memastikan pemanggil adalah service internal yang dikenal, lalu menyimpan namanya di context
+func ServiceAuth(cfg ServiceAuthConfig, log *logrus.Logger) grpc.UnaryServerInterceptor {
+	authenticate := newServiceAuthenticator(cfg)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+			return handler(ctx, req)
+		}
+
+		serviceName, err := authenticate(ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"request_id": RequestIDFromContext(ctx),
+				"method":     info.FullMethod,
+			}).WithError(err).Warn("Rejected unauthenticated gRPC call")
+			return nil, err
+		}
+
+		return handler(context.WithValue(ctx, serviceNameKey{}, serviceName), req)
+	}
+}
+
+// MethodAuthorization membatasi method tertentu (nama pendek, mis. "DecreaseStock") ke daftar service.
+// Method yang tidak terdaftar boleh dipanggil semua service yang lolos ServiceAuth.
+func MethodAuthorization(methodServices map[string][]string) grpc.UnaryServerInterceptor {
+	rules := make(map[string]map[string]struct{}, len(methodServices))
+	for method, services := range methodServices {
+		rules[method] = make(map[string]struct{}, len(services))
+		for _, name := range services {
+			rules[method][name] = struct{}{}
+		}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if allowed, ok := rules[path.Base(info.FullMethod)]; ok {
+			if _, ok := allowed[ServiceNameFromContext(ctx)]; !ok {
+				return nil, status.Errorf(codes.PermissionDenied, "service '%s' is not allowed to call %s", ServiceNameFromContext(ctx), info.FullMethod)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func ServiceNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(serviceNameKey{}).(string)
+	return name
+}
+
+func newServiceAuthenticator(cfg ServiceAuthConfig) func(ctx context.Context) (string, error) {
+	if cfg.Mode == AuthModeMTLS {
+		allowed := make(map[string]struct{}, len(cfg.AllowedServices))
+		for _, name := range cfg.AllowedServices {
+			allowed[name] = struct{}{}
+		}
+
+		return func(ctx context.Context) (string, error) {
+			return authenticateCertificate(ctx, allowed)
+		}
+	}
+
+	return func(ctx context.Context) (string, error) {
+		return authenticateSecret(ctx, cfg.Secrets)
+	}
+}
+
+func authenticateSecret(ctx context.Context, secrets map[string]string) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing service credentials")
+	}
+
+	names, providedSecrets := md.Get(ServiceNameHeader), md.Get(ServiceSecretHeader)
+	if len(names) == 0 || len(providedSecrets) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing service credentials")
+	}
+
+	expected, ok := secrets[names[0]]
+	if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(providedSecrets[0])) != 1 {
+		return "", status.Error(codes.Unauthenticated, "invalid service credentials")
+	}
+
+	return names[0], nil
+}
+
+func authenticateCertificate(ctx context.Context, allowed map[string]struct{}) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing peer information")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", status.Error(codes.Unauthenticated, "client certificate is required")
+	}
+
+	serviceName := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	if _, ok := allowed[serviceName]; !ok {
+		return "", status.Errorf(codes.Unauthenticated, "service '%s' is not in the allowlist", serviceName)
+	}
+
+	return serviceName, nil
+}
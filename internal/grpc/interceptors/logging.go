@@ -0,0 +1,39 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Logging(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		res, err := handler(ctx, req)
+
+		code := status.Code(err)
+		entry := log.WithFields(logrus.Fields{
+			"request_id":  RequestIDFromContext(ctx),
+			"method":      info.FullMethod,
+			"service":     ServiceNameFromContext(ctx),
+			"code":        code.String(),
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+
+		switch code {
+		case codes.OK:
+			entry.Info("Finished gRPC call")
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			entry.WithError(err).Error("gRPC call failed")
+		default:
+			entry.WithError(err).Warn("gRPC call rejected")
+		}
+
+		return res, err
+	}
+}
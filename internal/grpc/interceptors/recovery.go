@@ -0,0 +1,30 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recovery mengubah panic di handler menjadi codes.Internal supaya proses tidak ikut mati
+func Recovery(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(logrus.Fields{
+					"request_id": RequestIDFromContext(ctx),
+					"method":     info.FullMethod,
+					"panic":      r,
+				}).Errorf("Recovered from panic in gRPC handler\n%s", debug.Stack())
+
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
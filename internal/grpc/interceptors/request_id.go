@@ -0,0 +1,37 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const RequestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestID mengambil x-request-id dari metadata pemanggil (atau membuat yang baru) dan mengirimkannya balik di header response
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(RequestIDHeader); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+		return handler(context.WithValue(ctx, requestIDKey{}, requestID), req)
+	}
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,120 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// wrappedStream mengganti context stream supaya nilai dari interceptor (request ID, nama service) ikut terbawa ke handler
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(RequestIDHeader); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		_ = ss.SetHeader(metadata.Pairs(RequestIDHeader, requestID))
+
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: context.WithValue(ctx, requestIDKey{}, requestID)})
+	}
+}
+
+func StreamLogging(log *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+
+		log.WithFields(logrus.Fields{
+			"request_id": RequestIDFromContext(ctx),
+			"method":     info.FullMethod,
+		}).Info("Started gRPC stream")
+
+		err := handler(srv, ss)
+
+		code := status.Code(err)
+		entry := log.WithFields(logrus.Fields{
+			"request_id":  RequestIDFromContext(ctx),
+			"method":      info.FullMethod,
+			"service":     ServiceNameFromContext(ctx),
+			"code":        code.String(),
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+
+		switch code {
+		case codes.OK, codes.Canceled:
+			entry.Info("Finished gRPC stream")
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			entry.WithError(err).Error("gRPC stream failed")
+		default:
+			entry.WithError(err).Warn("gRPC stream rejected")
+		}
+
+		return err
+	}
+}
+
+func StreamRecovery(log *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(logrus.Fields{
+					"request_id": RequestIDFromContext(ss.Context()),
+					"method":     info.FullMethod,
+					"panic":      r,
+				}).Errorf("Recovered from panic in gRPC stream handler\n%s", debug.Stack())
+
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func StreamServiceAuth(cfg ServiceAuthConfig, log *logrus.Logger) grpc.StreamServerInterceptor {
+	authenticate := newServiceAuthenticator(cfg)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+			return handler(srv, ss)
+		}
+
+		ctx := ss.Context()
+
+		serviceName, err := authenticate(ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"request_id": RequestIDFromContext(ctx),
+				"method":     info.FullMethod,
+			}).WithError(err).Warn("Rejected unauthenticated gRPC stream")
+			return err
+		}
+
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: context.WithValue(ctx, serviceNameKey{}, serviceName)})
+	}
+}
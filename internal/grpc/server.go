@@ -2,27 +2,34 @@ package grpc
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"github.com/sirupsen/logrus"
 
-	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/services"
 
 	productpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/product"
 )
 
+// serviceSubject mewakili service internal yang memanggil RPC stok
+var serviceSubject = policies.Subject{Role: policies.RoleService}
+
 type ProductServer struct {
 	productpb.UnimplementedProductServiceServer
-	ProductSvc services.ProductService
+	ProductSvc  services.ProductService
+	TrendingSvc services.TrendingService
+
+	log *logrus.Logger
 }
 
-func NewProductServer(productSvc services.ProductService) *ProductServer {
+func NewProductServer(productSvc services.ProductService, trendingSvc services.TrendingService, log *logrus.Logger) *ProductServer {
 	return &ProductServer{
-		ProductSvc: productSvc,
+		ProductSvc:  productSvc,
+		TrendingSvc: trendingSvc,
+		log:         log,
 	}
 }
 
@@ -32,81 +39,47 @@ func (s *ProductServer) GetProducts(ctx context.Context, req *productpb.GetProdu
 	for _, idStr := range req.GetIds() {
 		parsedID, err := uuid.Parse(idStr)
 		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "format Product ID '%s' tidak valid", idStr)
+			return nil, invalidArgument("ids", fmt.Sprintf("format Product ID '%s' tidak valid", idStr))
 		}
 		ids = append(ids, parsedID)
 	}
 
 	dbProducts, err := s.ProductSvc.GetProductByIDs(ctx, ids)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatusError(ctx, err, "failed to get products")
 	}
 
-	var protoProducts []*productpb.Product
-	for _, p := range dbProducts {
-		protoProducts = append(protoProducts, &productpb.Product{
-			Id:        p.ID.String(),
-			SellerId:  p.SellerID.String(),
-			Name:      p.Name,
-			Price:     int32(p.Price),
-			Stock:     int32(p.Stock),
-			CreatedAt: timestamppb.New(p.CreatedAt),
-			UpdatedAt: timestamppb.New(p.UpdatedAt),
-		})
-	}
-
-	return &productpb.GetProductsResponse{Products: protoProducts}, nil
+	return &productpb.GetProductsResponse{Products: toProtoProducts(dbProducts)}, nil
 }
 
 func (s *ProductServer) DecreaseStock(ctx context.Context, req *productpb.DecreaseStockRequest) (*productpb.DecreaseStockResponse, error) {
-	updatedProducts, err := s.ProductSvc.DecreaseStock(ctx, req.GetItems())
+	updatedProducts, err := s.ProductSvc.DecreaseStock(ctx, serviceSubject, req.GetItems())
 	if err != nil {
-		if errors.Is(err, apperrors.ErrProductOutOfStock) {
-			return nil, status.Errorf(codes.FailedPrecondition, "product out of stock: %v", err.Error())
-		}
-		return nil, status.Errorf(codes.Internal, "failed to decrease stock: %v", err)
+		return nil, s.toStatusError(ctx, err, "failed to decrease stock")
 	}
 
-	pbProducts := make([]*productpb.Product, len(updatedProducts))
-	for i, p := range updatedProducts {
-		pbProducts[i] = &productpb.Product{
-			Id:          p.ID.String(),
-			SellerId:    p.SellerID.String(),
-			Name:        p.Name,
-			Price:       int32(p.Price),
-			Stock:       int32(p.Stock),
-			Description: p.Description,
-			CreatedAt:   timestamppb.New(p.CreatedAt),
-			UpdatedAt:   timestamppb.New(p.UpdatedAt),
-		}
+	// Pengurangan stok adalah pembelian yang berhasil, jadi dipakai sebagai sinyal trending
+	purchased := make(map[string]int, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		purchased[item.GetProductId()] += int(item.GetQuantityToDecrease())
+	}
+	// Co-purchase tidak dicatat di sini: OrderCreatedEvent untuk order yang sama sudah menghitungnya
+	for _, p := range updatedProducts {
+		s.TrendingSvc.RecordSignal(ctx, p.ID, p.Type, entities.TrendingSignalPurchase, purchased[p.ID.String()])
 	}
 
 	return &productpb.DecreaseStockResponse{
-		Products: pbProducts,
+		Products: toProtoProductPtrs(updatedProducts),
 	}, nil
 }
 
 func (s *ProductServer) IncreaseStock(ctx context.Context, req *productpb.IncreaseStockRequest) (*productpb.IncreaseStockResponse, error) {
-	updatedProducts, err := s.ProductSvc.IncreaseStock(ctx, req.GetItems())
+	updatedProducts, err := s.ProductSvc.IncreaseStock(ctx, serviceSubject, req.GetItems())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to increase stock: %v", err)
-	}
-
-	pbProducts := make([]*productpb.Product, len(updatedProducts))
-	for i, p := range updatedProducts {
-		pbProducts[i] = &productpb.Product{
-			Id:          p.ID.String(),
-			SellerId:    p.SellerID.String(),
-			Name:        p.Name,
-			Price:       int32(p.Price),
-			Stock:       int32(p.Stock),
-			Description: p.Description,
-			CreatedAt:   timestamppb.New(p.CreatedAt),
-			UpdatedAt:   timestamppb.New(p.UpdatedAt),
-		}
+		return nil, s.toStatusError(ctx, err, "failed to increase stock")
 	}
 
 	return &productpb.IncreaseStockResponse{
-		Products: pbProducts,
+		Products: toProtoProductPtrs(updatedProducts),
 	}, nil
 }
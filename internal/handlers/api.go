@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/services"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -10,20 +12,38 @@ import (
 )
 
 type API struct {
-	ProductSvc services.ProductService
-	CartSvc    services.CartService
-	log        *logrus.Logger
+	ProductSvc        services.ProductService
+	CartSvc           services.CartService
+	StaffSvc          services.SellerStaffService
+	RecentlyViewedSvc services.RecentlyViewedService
+	TrendingSvc       services.TrendingService
+	RelatedSvc        services.RelatedService
+	ReviewSvc         services.ReviewService
+	QuestionSvc       services.QuestionService
+	log               *logrus.Logger
 }
 
 func NewHandler(
 	productSvc services.ProductService,
 	cartSvc services.CartService,
+	staffSvc services.SellerStaffService,
+	recentlyViewedSvc services.RecentlyViewedService,
+	trendingSvc services.TrendingService,
+	relatedSvc services.RelatedService,
+	reviewSvc services.ReviewService,
+	questionSvc services.QuestionService,
 	log *logrus.Logger,
 ) *API {
 	return &API{
-		ProductSvc: productSvc,
-		CartSvc:    cartSvc,
-		log:        log,
+		ProductSvc:        productSvc,
+		CartSvc:           cartSvc,
+		StaffSvc:          staffSvc,
+		RecentlyViewedSvc: recentlyViewedSvc,
+		TrendingSvc:       trendingSvc,
+		RelatedSvc:        relatedSvc,
+		ReviewSvc:         reviewSvc,
+		QuestionSvc:       questionSvc,
+		log:               log,
 	}
 }
 
@@ -46,6 +66,22 @@ func getUserIDFromContext(c echo.Context) (uuid.UUID, error) {
 	return uuid.Nil, errors.ErrInvalidUserSession
 }
 
+// getViewerFromContext membaca user atau guest yang diisi OptionalAuthMiddleware
+func getViewerFromContext(c echo.Context) entities.Viewer {
+	var viewer entities.Viewer
+	if userID, ok := c.Get("userID").(string); ok {
+		viewer.UserID = userID
+	}
+	if guestToken, ok := c.Get("guestToken").(string); ok {
+		viewer.GuestToken = guestToken
+	}
+	if newGuest, ok := c.Get("newGuest").(bool); ok {
+		viewer.NewGuest = newGuest
+	}
+
+	return viewer
+}
+
 func getRoleFromContext(c echo.Context) (string, error) {
 	if val := c.Get("role"); val != nil {
 		if role, ok := val.(string); ok {
@@ -56,6 +92,30 @@ func getRoleFromContext(c echo.Context) (string, error) {
 	return "", errors.ErrInvalidUserSession
 }
 
+func getSubjectFromContext(c echo.Context) (policies.Subject, error) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return policies.Subject{}, err
+	}
+
+	role, err := getRoleFromContext(c)
+	if err != nil {
+		return policies.Subject{}, err
+	}
+
+	return policies.Subject{UserID: userID, Role: role}, nil
+}
+
+// getOptionalSubjectFromContext dipakai di route publik; guest mendapat Subject kosong yang tidak lolos policy apa pun
+func getOptionalSubjectFromContext(c echo.Context) policies.Subject {
+	subject, err := getSubjectFromContext(c)
+	if err != nil {
+		return policies.Subject{}
+	}
+
+	return subject
+}
+
 func getIDFromPathParam(c echo.Context, key string) (uuid.UUID, error) {
 	val := c.Param(key)
 	if val == "" || !helpers.IsValidUUID(val) {
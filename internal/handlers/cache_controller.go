@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func (api *API) GetCacheStats() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		res, err := api.ProductSvc.GetCacheStats(ctx)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		stats := make([]*models.CacheFamilyStatsResponse, 0, len(res))
+		for _, s := range res {
+			stats = append(stats, &models.CacheFamilyStatsResponse{
+				Family:      s.Family,
+				Keys:        s.Keys,
+				MemoryBytes: s.MemoryBytes,
+			})
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgCacheStatsRetrieved, stats)
+	}
+}
+
+func (api *API) PurgeCacheFamily() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		family, err := getFromPathParam(c, "family")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		return api.purgeCache(c, entities.CachePurgeTarget{Family: family})
+	}
+}
+
+func (api *API) PurgeSellerCache() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sellerID, err := getIDFromPathParam(c, "seller_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		return api.purgeCache(c, entities.CachePurgeTarget{SellerID: sellerID})
+	}
+}
+
+func (api *API) PurgeTypeCache() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		productType, err := getFromPathParam(c, "type")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		return api.purgeCache(c, entities.CachePurgeTarget{Type: productType})
+	}
+}
+
+func (api *API) PurgeProductCache() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		productID, err := getIDFromPathParam(c, "product_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		return api.purgeCache(c, entities.CachePurgeTarget{ProductID: productID})
+	}
+}
+
+func (api *API) WarmCache() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req models.CacheWarmRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		target := entities.CacheWarmTarget{TopN: req.TopN}
+		if req.SellerID != "" {
+			if !helpers.IsValidUUID(req.SellerID) {
+				return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+			}
+			target.SellerID = uuid.MustParse(req.SellerID)
+		}
+
+		warmed, err := api.ProductSvc.WarmProductCaches(ctx, target)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgCacheWarmed, &models.CacheOperationResponse{Keys: warmed})
+	}
+}
+
+func (api *API) purgeCache(c echo.Context, target entities.CachePurgeTarget) error {
+	deleted, err := api.ProductSvc.PurgeProductCaches(c.Request().Context(), target)
+	if err != nil {
+		return handleOperationError(c, err)
+	}
+
+	return respondSuccess(c, http.StatusOK, MsgCachePurged, &models.CacheOperationResponse{Keys: deleted})
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/services"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -25,16 +30,23 @@ func (a *API) AddToCart() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
+		expectedVersion, err := getExpectedCartVersion(c)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
 		var req models.CartRequest
 		if err := c.Bind(&req); err != nil {
 			return respondError(c, http.StatusBadRequest, errors.ErrInvalidRequestPayload)
 		}
 
-		if err := a.CartSvc.AddItemToCart(ctx, userID, productID, &req); err != nil {
+		version, err := a.CartSvc.AddItemToCart(ctx, userID, productID, &req, expectedVersion)
+		setCartVersionHeader(c, version)
+		if err != nil {
 			return handleOperationError(c, err)
 		}
 
-		return respondSuccess(c, http.StatusOK, MsgCartCreated, nil)
+		return respondSuccess(c, http.StatusOK, MsgCartCreated, &models.CartVersionResponse{Version: version})
 	}
 }
 
@@ -52,6 +64,7 @@ func (a *API) GetCartItemsByUserID() echo.HandlerFunc {
 			return handleGetError(c, err)
 		}
 
+		setCartVersionHeader(c, res.Version)
 		return respondSuccess(c, http.StatusOK, MsgCartRetrieved, toCartResponse(res))
 	}
 }
@@ -71,6 +84,11 @@ func (a *API) UpdateCartItem() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, "Invalid Product ID format")
 		}
 
+		expectedVersion, err := getExpectedCartVersion(c)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
 		var req models.UpdateCartRequest
 		if err := c.Bind(&req); err != nil {
 			return c.JSON(http.StatusBadRequest, "Invalid request body: ‘quantity’ is required")
@@ -79,13 +97,51 @@ func (a *API) UpdateCartItem() echo.HandlerFunc {
 		logger := a.log.WithFields(logrus.Fields{"user_id": userID, "product_id": productID, "new_quantity": req.Quantity})
 		logger.Info("Receiving UpdateCartItem requests")
 
-		err = a.CartSvc.UpdateItem(ctx, userID, productID, req.Quantity, req.Description)
+		version, err := a.CartSvc.UpdateItem(ctx, userID, productID, req.Quantity, req.Description, req.CartItemOptions, expectedVersion)
+		setCartVersionHeader(c, version)
+		if stderrors.Is(err, errors.ErrInvalidRequestPayload) || stderrors.Is(err, errors.ErrCartVersionConflict) {
+			return handleOperationError(c, err)
+		}
 		if err != nil {
 			logger.WithError(err).Error("Error dari service saat memperbarui item keranjang")
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 		}
 
-		return respondSuccess(c, http.StatusOK, MsgCartUpdated, nil)
+		return respondSuccess(c, http.StatusOK, MsgCartUpdated, &models.CartVersionResponse{Version: version})
+	}
+}
+
+func (a *API) SetCartSellerNote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, errors.ErrInvalidUserSession)
+		}
+
+		sellerID, err := getIDFromPathParam(c, "seller_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		expectedVersion, err := getExpectedCartVersion(c)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		var req models.CartSellerNoteRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, errors.ErrInvalidRequestPayload)
+		}
+
+		version, err := a.CartSvc.SetSellerNote(ctx, userID, sellerID, req.Note, expectedVersion)
+		setCartVersionHeader(c, version)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgCartSellerNoteSaved, &models.CartVersionResponse{Version: version})
 	}
 }
 
@@ -103,22 +159,54 @@ func (a *API) RemoveFromCart() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		err = a.CartSvc.RemoveItemFromCart(ctx, userID, productID)
+		expectedVersion, err := getExpectedCartVersion(c)
 		if err != nil {
 			return handleOperationError(c, err)
 		}
 
-		return respondSuccess(c, http.StatusOK, MsgCartDeleted, nil)
+		version, err := a.CartSvc.RemoveItemFromCart(ctx, userID, productID, expectedVersion)
+		setCartVersionHeader(c, version)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgCartDeleted, &models.CartVersionResponse{Version: version})
 	}
 }
 
 // ------- HELPERS -------
 
+// getExpectedCartVersion membaca versi cart dari header If-Match; tanpa header (atau "*") versi tidak dicek
+func getExpectedCartVersion(c echo.Context) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return services.AnyCartVersion, nil
+	}
+
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.ParseInt(ifMatch, 10, 64)
+	if err != nil || version < 0 {
+		return 0, errors.ErrInvalidCartVersion
+	}
+
+	return version, nil
+}
+
+// setCartVersionHeader juga dipanggil saat konflik supaya klien tahu versi terbaru
+func setCartVersionHeader(c echo.Context, version int64) {
+	if version > 0 {
+		c.Response().Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+	}
+}
+
 func toCartResponse(cart *entities.Cart) *models.CartResponse {
 	return &models.CartResponse{
-		UserID:     cart.UserID.String(),
-		TotalItems: cart.TotalItems,
-		Items:      toCartItemsResponse(cart.Items),
+		UserID:      cart.UserID.String(),
+		TotalItems:  cart.TotalItems,
+		Version:     cart.Version,
+		Degraded:    cart.Degraded,
+		Items:       toCartItemsResponse(cart.Items),
+		SellerNotes: cart.SellerNotes,
 	}
 }
 
@@ -134,13 +222,17 @@ func toCartItemsResponse(items []entities.CartItem) []models.CartItemResponse {
 
 func toCartItemResponse(item entities.CartItem) *models.CartItemResponse {
 	return &models.CartItemResponse{
-		SellerName:   item.SellerName,
-		ProductID:    item.ProductID.String(),
-		ProductName:  item.ProductName,
-		ProductImage: "",
-		Price:        item.Price,
-		Quantity:     item.Quantity,
-		Description:  item.Description,
-		Checked:      item.Checked,
+		SellerName:    item.SellerName,
+		SellerUnknown: item.SellerUnknown,
+		ProductID:     item.ProductID.String(),
+		ProductName:   item.ProductName,
+		ProductImage:  "",
+		Price:         item.Price,
+		Quantity:      item.Quantity,
+		Description:   item.Description,
+		GiftWrap:      item.GiftWrap,
+		GiftMessage:   item.GiftMessage,
+		VariantNote:   item.VariantNote,
+		Checked:       item.Checked,
 	}
 }
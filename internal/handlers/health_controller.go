@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/health"
+)
+
+// HealthHandler dipisah dari API karena probe tidak butuh service domain dan tidak memakai envelope response
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+func (h *HealthHandler) Liveness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"status": "ok"})
+	}
+}
+
+func (h *HealthHandler) Readiness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := h.checker.Check(c.Request().Context())
+		if !report.Ready {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
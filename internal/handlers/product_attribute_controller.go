@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+const attributeQueryPrefix = "attr."
+
+// Operator dua karakter dicek lebih dulu supaya ">=" tidak terbaca sebagai ">"
+var attributeOperators = []entities.AttributeOperator{
+	entities.AttributeOpGte,
+	entities.AttributeOpLte,
+	entities.AttributeOpNe,
+	entities.AttributeOpEq,
+	entities.AttributeOpGt,
+	entities.AttributeOpLt,
+}
+
+func (api *API) GetAttributeDefinitions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		productType, err := getFromPathParam(c, "type")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ProductSvc.GetAttributeDefinitions(ctx, productType)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgAttributeDefinitionRetrieved, toAttributeDefinitionResponseList(res))
+	}
+}
+
+func (api *API) UpsertAttributeDefinition() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		productType, err := getFromPathParam(c, "type")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		key, err := getFromPathParam(c, "key")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.AttributeDefinitionRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ProductSvc.UpsertAttributeDefinition(ctx, subject, productType, key, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgAttributeDefinitionSaved, toAttributeDefinitionResponse(res))
+	}
+}
+
+func (api *API) DeleteAttributeDefinition() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		productType, err := getFromPathParam(c, "type")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		key, err := getFromPathParam(c, "key")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		if err := api.ProductSvc.DeleteAttributeDefinition(ctx, subject, productType, key); err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgAttributeDefinitionDeleted, nil)
+	}
+}
+
+// ------- HELPERS -------
+
+// listProducts memakai pencarian di DB jika ada filter attr.*, min_rating, sort=rating atau limit/offset;
+// tanpa itu list yang di-cache tetap dipakai
+func (api *API) listProducts(c echo.Context, query entities.ProductSearchQuery, cached func(ctx context.Context) ([]entities.Product, error)) ([]entities.Product, error) {
+	ctx := c.Request().Context()
+
+	conditions, err := getAttributeConditionsFromQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := getRatingQueryFromQuery(c, &query); err != nil {
+		return nil, err
+	}
+	if query.Limit, query.Offset, err = getPageFromQueryParam(c); err != nil {
+		return nil, err
+	}
+
+	if len(conditions) == 0 && query.MinRating == nil && !query.SortByRating && query.Limit == 0 && query.Offset == 0 {
+		return cached(ctx)
+	}
+
+	query.Conditions = conditions
+	return api.ProductSvc.SearchProducts(ctx, getOptionalSubjectFromContext(c), query)
+}
+
+// getRatingQueryFromQuery membaca ?min_rating= dan ?sort=rating
+func getRatingQueryFromQuery(c echo.Context, query *entities.ProductSearchQuery) error {
+	if minRatingStr := c.QueryParam("min_rating"); minRatingStr != "" {
+		minRating, err := strconv.ParseFloat(minRatingStr, 64)
+		if err != nil || minRating < 0 || minRating > 5 {
+			return fmt.Errorf("%w: min_rating must be between 0 and 5", apperrors.ErrInvalidRequestPayload)
+		}
+		query.MinRating = &minRating
+	}
+
+	switch sortBy := c.QueryParam("sort"); sortBy {
+	case "":
+	case "rating":
+		query.SortByRating = true
+	default:
+		return fmt.Errorf("%w: unsupported sort '%s'", apperrors.ErrInvalidRequestPayload, sortBy)
+	}
+
+	return nil
+}
+
+// getAttributeConditionsFromQuery membaca filter seperti attr.brand=acme dan attr.ram_gb>=8.
+// Parser query string memotong di "=" pertama, jadi "attr.ram_gb>=8" datang sebagai key "attr.ram_gb>" dengan value "8"
+// dan "attr.ram_gb>8" sebagai key tanpa value; keduanya disusun ulang dulu sebelum operatornya dicari.
+func getAttributeConditionsFromQuery(c echo.Context) ([]entities.AttributeCondition, error) {
+	var conditions []entities.AttributeCondition
+	for key, values := range c.QueryParams() {
+		if !strings.HasPrefix(key, attributeQueryPrefix) {
+			continue
+		}
+
+		for _, value := range values {
+			raw := strings.TrimPrefix(key, attributeQueryPrefix)
+			if value != "" || !strings.ContainsAny(raw, "<>") {
+				raw += "=" + value
+			}
+
+			condition, ok := parseAttributeCondition(raw)
+			if !ok {
+				return nil, fmt.Errorf("%w: invalid attribute filter '%s%s'", apperrors.ErrInvalidRequestPayload, attributeQueryPrefix, raw)
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return conditions, nil
+}
+
+// parseAttributeCondition memotong di operator paling awal; key kosong (mis. ">=8") ditolak
+func parseAttributeCondition(raw string) (entities.AttributeCondition, bool) {
+	idx, op := -1, entities.AttributeOperator("")
+	for _, candidate := range attributeOperators {
+		if i := strings.Index(raw, string(candidate)); i >= 0 && (idx == -1 || i < idx) {
+			idx, op = i, candidate
+		}
+	}
+	if idx <= 0 {
+		return entities.AttributeCondition{}, false
+	}
+
+	return entities.AttributeCondition{
+		Key:      raw[:idx],
+		Operator: op,
+		Value:    raw[idx+len(op):],
+	}, true
+}
+
+func toAttributeDefinitionResponse(def *entities.AttributeDefinition) *models.AttributeDefinitionResponse {
+	return &models.AttributeDefinitionResponse{
+		ProductType:   def.ProductType,
+		Key:           def.Key,
+		DataType:      string(def.DataType),
+		Required:      def.Required,
+		AllowedValues: def.AllowedValues,
+		Unit:          def.Unit,
+		CreatedAt:     def.CreatedAt.Format(helpers.LAYOUTFORMAT),
+		UpdatedAt:     def.UpdatedAt.Format(helpers.LAYOUTFORMAT),
+	}
+}
+
+func toAttributeDefinitionResponseList(defs []entities.AttributeDefinition) []*models.AttributeDefinitionResponse {
+	res := make([]*models.AttributeDefinitionResponse, len(defs))
+	for i := range defs {
+		res[i] = toAttributeDefinitionResponse(&defs[i])
+	}
+
+	return res
+}
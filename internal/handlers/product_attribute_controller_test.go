@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func TestParseAttributeCondition(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want entities.AttributeCondition
+	}{
+		{"brand=acme", entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpEq, Value: "acme"}},
+		{"brand!=acme", entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpNe, Value: "acme"}},
+		{"ram_gb>8", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpGt, Value: "8"}},
+		{"ram_gb>=8", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpGte, Value: "8"}},
+		{"ram_gb<8", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpLt, Value: "8"}},
+		{"ram_gb<=8", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpLte, Value: "8"}},
+
+		// operator pertama yang menentukan; sisanya bagian dari value
+		{"brand=a=b", entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpEq, Value: "a=b"}},
+		{"brand=>=x", entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpEq, Value: ">=x"}},
+		{"ram_gb>=<8", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpGte, Value: "<8"}},
+		{"brand!==acme", entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpNe, Value: "=acme"}},
+		{"ram_gb>", entities.AttributeCondition{Key: "ram_gb", Operator: entities.AttributeOpGt, Value: ""}},
+		{`brand=ac"me`, entities.AttributeCondition{Key: "brand", Operator: entities.AttributeOpEq, Value: `ac"me`}},
+
+		// key tidak divalidasi di sini; service yang menolak key tidak valid
+		{"Bad Key=x", entities.AttributeCondition{Key: "Bad Key", Operator: entities.AttributeOpEq, Value: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := parseAttributeCondition(tt.raw)
+			if !ok {
+				t.Fatalf("expected %q to parse", tt.raw)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseAttributeConditionRejectsMissingKeyOrOperator(t *testing.T) {
+	for _, raw := range []string{"", "brand", "=acme", ">=8", "!=x", "<8"} {
+		if got, ok := parseAttributeCondition(raw); ok {
+			t.Errorf("expected %q to be rejected, got %+v", raw, got)
+		}
+	}
+}
+
+func TestGetAttributeConditionsFromQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []entities.AttributeCondition
+	}{
+		{"no attribute filters", "min_rating=4&sort=rating", nil},
+		{"equals", "attr.brand=acme", []entities.AttributeCondition{{Key: "brand", Operator: entities.AttributeOpEq, Value: "acme"}}},
+		// "attr.ram_gb>=8" dipotong parser query string menjadi key "attr.ram_gb>" dan value "8"
+		{"greater or equal", "attr.ram_gb>=8", []entities.AttributeCondition{{Key: "ram_gb", Operator: entities.AttributeOpGte, Value: "8"}}},
+		{"less or equal", "attr.ram_gb<=8", []entities.AttributeCondition{{Key: "ram_gb", Operator: entities.AttributeOpLte, Value: "8"}}},
+		{"not equals", "attr.brand!=acme", []entities.AttributeCondition{{Key: "brand", Operator: entities.AttributeOpNe, Value: "acme"}}},
+		// tanpa "=" sama sekali: key "attr.ram_gb>8" dengan value kosong
+		{"greater than", "attr.ram_gb>8", []entities.AttributeCondition{{Key: "ram_gb", Operator: entities.AttributeOpGt, Value: "8"}}},
+		{"less than", "attr.ram_gb<8", []entities.AttributeCondition{{Key: "ram_gb", Operator: entities.AttributeOpLt, Value: "8"}}},
+		{"encoded operator", "attr.ram_gb%3E%3D8", []entities.AttributeCondition{{Key: "ram_gb", Operator: entities.AttributeOpGte, Value: "8"}}},
+		{"encoded value", "attr.brand=ac%22me%20co", []entities.AttributeCondition{{Key: "brand", Operator: entities.AttributeOpEq, Value: `ac"me co`}}},
+		{"empty value is kept for the service to reject", "attr.brand=", []entities.AttributeCondition{{Key: "brand", Operator: entities.AttributeOpEq, Value: ""}}},
+		{"repeated key", "attr.brand=acme&attr.brand!=other", []entities.AttributeCondition{
+			{Key: "brand", Operator: entities.AttributeOpEq, Value: "acme"},
+			{Key: "brand", Operator: entities.AttributeOpNe, Value: "other"},
+		}},
+		{"range pair", "attr.ram_gb>=8&attr.ram_gb<=32", []entities.AttributeCondition{
+			{Key: "ram_gb", Operator: entities.AttributeOpGte, Value: "8"},
+			{Key: "ram_gb", Operator: entities.AttributeOpLte, Value: "32"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getAttributeConditionsFromQuery(newQueryContext(tt.query))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Urutan map query params tidak stabil
+			sortConditions(got)
+			sortConditions(tt.want)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %+v, got %+v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAttributeConditionsFromQueryRejectsInvalidFilters(t *testing.T) {
+	for _, query := range []string{"attr.=acme", "attr.%3D8", "attr.%3E8"} {
+		t.Run(query, func(t *testing.T) {
+			_, err := getAttributeConditionsFromQuery(newQueryContext(query))
+			if !errors.Is(err, apperrors.ErrInvalidRequestPayload) {
+				t.Fatalf("expected %v, got %v", apperrors.ErrInvalidRequestPayload, err)
+			}
+		})
+	}
+}
+
+func newQueryContext(query string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/products?"+query, nil)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func sortConditions(conditions []entities.AttributeCondition) {
+	sort.Slice(conditions, func(i, j int) bool {
+		if conditions[i].Key != conditions[j].Key {
+			return conditions[i].Key < conditions[j].Key
+		}
+		if conditions[i].Operator != conditions[j].Operator {
+			return conditions[i].Operator < conditions[j].Operator
+		}
+		return conditions[i].Value < conditions[j].Value
+	})
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
@@ -15,7 +17,7 @@ func (api *API) CreateProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		userID, err := getUserIDFromContext(c)
+		subject, err := getSubjectFromContext(c)
 		if err != nil {
 			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
 		}
@@ -25,7 +27,7 @@ func (api *API) CreateProduct() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
 		}
 
-		res, err := api.ProductSvc.CreateProduct(ctx, userID, &req)
+		res, err := api.ProductSvc.CreateProduct(ctx, subject, &req)
 		if err != nil {
 			return handleOperationError(c, err)
 		}
@@ -36,9 +38,7 @@ func (api *API) CreateProduct() echo.HandlerFunc {
 
 func (api *API) GetAllProducts() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		ctx := c.Request().Context()
-
-		res, err := api.ProductSvc.GetAllProducts(ctx)
+		res, err := api.listProducts(c, entities.ProductSearchQuery{}, api.ProductSvc.GetAllProducts)
 		if err != nil {
 			return handleGetError(c, err)
 		}
@@ -49,14 +49,14 @@ func (api *API) GetAllProducts() echo.HandlerFunc {
 
 func (api *API) GetProductsByName() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		ctx := c.Request().Context()
-
 		productName, err := getFromPathParam(c, "name")
 		if err != nil {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		res, err := api.ProductSvc.GetProductsByName(ctx, productName)
+		res, err := api.listProducts(c, entities.ProductSearchQuery{Name: productName}, func(ctx context.Context) ([]entities.Product, error) {
+			return api.ProductSvc.GetProductsByName(ctx, productName)
+		})
 		if err != nil {
 			return handleGetError(c, err)
 		}
@@ -67,14 +67,14 @@ func (api *API) GetProductsByName() echo.HandlerFunc {
 
 func (api *API) GetProductsByType() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		ctx := c.Request().Context()
-
 		productType, err := getFromPathParam(c, "type")
 		if err != nil {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		res, err := api.ProductSvc.GetProductsByType(ctx, productType)
+		res, err := api.listProducts(c, entities.ProductSearchQuery{Type: productType}, func(ctx context.Context) ([]entities.Product, error) {
+			return api.ProductSvc.GetProductsByType(ctx, productType)
+		})
 		if err != nil {
 			return handleGetError(c, err)
 		}
@@ -92,25 +92,99 @@ func (api *API) GetProductByID() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		res, err := api.ProductSvc.GetProductByID(ctx, productID)
+		res, err := api.ProductSvc.GetVisibleProductByID(ctx, getOptionalSubjectFromContext(c), productID)
 		if err != nil {
 			return handleGetError(c, err)
 		}
 
+		api.RecentlyViewedSvc.RecordView(ctx, getViewerFromContext(c), productID)
+		api.TrendingSvc.RecordSignal(ctx, productID, res.Type, entities.TrendingSignalView, 1)
+
 		return respondSuccess(c, http.StatusOK, MsgProductRetrieved, toProductResponse(res))
 	}
 }
 
-func (api *API) GetProductsBySellerID() echo.HandlerFunc {
+func (api *API) GetRecentlyViewedProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		limit := 0
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed <= 0 {
+				return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+			}
+			limit = parsed
+		}
+
+		res, err := api.RecentlyViewedSvc.GetRecentlyViewed(ctx, getViewerFromContext(c), limit)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductRetrieved, toProductResponseList(res))
+	}
+}
+
+func (api *API) GetTrendingProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		limit := 0
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed <= 0 {
+				return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+			}
+			limit = parsed
+		}
+
+		res, err := api.TrendingSvc.GetTrending(ctx, c.QueryParam("window"), c.QueryParam("type"), limit)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductRetrieved, toTrendingProductResponseList(res))
+	}
+}
+
+func (api *API) GetRelatedProducts() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
+		productID, err := getIDFromPathParam(c, "id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		limit := 0
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed <= 0 {
+				return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+			}
+			limit = parsed
+		}
+
+		res, err := api.RelatedSvc.GetRelatedProducts(ctx, productID, limit)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductRetrieved, toRelatedProductResponseList(res))
+	}
+}
+
+func (api *API) GetProductsBySellerID() echo.HandlerFunc {
+	return func(c echo.Context) error {
 		sellerID, err := getIDFromPathParam(c, "seller_id")
 		if err != nil {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		res, err := api.ProductSvc.GetProductsBySellerID(ctx, sellerID)
+		res, err := api.listProducts(c, entities.ProductSearchQuery{SellerID: sellerID}, func(ctx context.Context) ([]entities.Product, error) {
+			return api.ProductSvc.GetVisibleProductsBySellerID(ctx, getOptionalSubjectFromContext(c), sellerID)
+		})
 		if err != nil {
 			return handleGetError(c, err)
 		}
@@ -123,12 +197,7 @@ func (api *API) UpdateProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		userID, err := getUserIDFromContext(c)
-		if err != nil {
-			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
-		}
-
-		role, err := getRoleFromContext(c)
+		subject, err := getSubjectFromContext(c)
 		if err != nil {
 			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
 		}
@@ -143,7 +212,7 @@ func (api *API) UpdateProduct() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
 		}
 
-		res, err := api.ProductSvc.UpdateProduct(ctx, &productData, productID, userID, role)
+		res, err := api.ProductSvc.UpdateProduct(ctx, &productData, productID, subject)
 		if err != nil {
 			return handleOperationError(c, err)
 		}
@@ -157,12 +226,30 @@ func (api *API) DeleteProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		sellerID, err := getUserIDFromContext(c)
+		subject, err := getSubjectFromContext(c)
 		if err != nil {
 			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
 		}
 
-		role, err := getRoleFromContext(c)
+		productID, err := getIDFromPathParam(c, "product_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ProductSvc.DeleteProduct(ctx, productID, subject)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductDeleted, toProductResponse(res))
+	}
+}
+
+func (api *API) ChangeProductStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
 		if err != nil {
 			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
 		}
@@ -172,12 +259,44 @@ func (api *API) DeleteProduct() echo.HandlerFunc {
 			return respondError(c, http.StatusBadRequest, err)
 		}
 
-		res, err := api.ProductSvc.DeleteProduct(ctx, productID, sellerID, role)
+		var req models.ProductStatusRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ProductSvc.ChangeProductStatus(ctx, subject, productID, &req)
 		if err != nil {
 			return handleOperationError(c, err)
 		}
 
-		return respondSuccess(c, http.StatusOK, MsgProductDeleted, toProductResponse(res))
+		return respondSuccess(c, http.StatusOK, MsgProductStatusChanged, toProductResponse(res))
+	}
+}
+
+func (api *API) BulkUpdateProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		var req models.BulkProductRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ProductSvc.BulkUpdateProducts(ctx, &req, subject)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductBulkApplied, &models.BulkProductResponse{
+			Operation: req.Operation,
+			Affected:  len(res),
+			Products:  toProductResponseList(res),
+		})
 	}
 }
 
@@ -195,18 +314,25 @@ func (api *API) ClearProductCaches() echo.HandlerFunc {
 }
 
 // ------- HELPERS -------
+
 func toProductResponse(product *entities.Product) *models.ProductResponse {
 	return &models.ProductResponse{
-		ID:          product.ID,
-		SellerID:    product.SellerID,
-		Name:        product.Name,
-		Price:       product.Price,
-		Stock:       product.Stock,
-		Discount:    product.Discount,
-		Type:        product.Type,
-		Description: product.Description,
-		CreatedAt:   product.CreatedAt.Format(helpers.LAYOUTFORMAT),
-		UpdatedAt:   product.UpdatedAt.Format(helpers.LAYOUTFORMAT),
+		ID:           product.ID,
+		SellerID:     product.SellerID,
+		Name:         product.Name,
+		Price:        product.Price,
+		Stock:        product.Stock,
+		Discount:     product.Discount,
+		Type:         product.Type,
+		Description:  product.Description,
+		ExternalSKU:  product.ExternalSKU,
+		RatingAvg:    product.RatingAvg,
+		RatingCount:  product.RatingCount,
+		Status:       string(product.Status),
+		StatusReason: product.StatusReason,
+		Attributes:   product.Attributes,
+		CreatedAt:    product.CreatedAt.Format(helpers.LAYOUTFORMAT),
+		UpdatedAt:    product.UpdatedAt.Format(helpers.LAYOUTFORMAT),
 	}
 }
 
@@ -219,3 +345,27 @@ func toProductResponseList(products []entities.Product) []*models.ProductRespons
 
 	return productResponses
 }
+
+func toTrendingProductResponseList(trending []entities.TrendingProduct) []*models.TrendingProductResponse {
+	res := make([]*models.TrendingProductResponse, len(trending))
+	for i := range trending {
+		res[i] = &models.TrendingProductResponse{
+			Product: toProductResponse(&trending[i].Product),
+			Score:   trending[i].Score,
+		}
+	}
+
+	return res
+}
+
+func toRelatedProductResponseList(related []entities.RelatedProduct) []*models.RelatedProductResponse {
+	res := make([]*models.RelatedProductResponse, len(related))
+	for i := range related {
+		res[i] = &models.RelatedProductResponse{
+			Product: toProductResponse(&related[i].Product),
+			Reason:  string(related[i].Reason),
+		}
+	}
+
+	return res
+}
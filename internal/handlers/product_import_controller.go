@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func (api *API) ImportProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		format, err := getImportFormat(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ProductSvc.ImportProducts(ctx, subject, format, c.Request().Body)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusAccepted, MsgProductImportAccepted, toProductImportJobResponse(res))
+	}
+}
+
+func (api *API) GetImportJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		jobID, err := getIDFromPathParam(c, "job_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ProductSvc.GetImportJob(ctx, jobID, subject)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgProductImportRetrieved, toProductImportJobResponse(res))
+	}
+}
+
+func (api *API) ExportProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		format, err := getImportFormat(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		contentType := "text/csv"
+		if format == entities.ImportFormatNDJSON {
+			contentType = "application/x-ndjson"
+		}
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, "attachment; filename=products."+format)
+		res.WriteHeader(http.StatusOK)
+
+		// header sudah terkirim, error di tengah stream hanya bisa dicatat
+		if err := api.ProductSvc.ExportProducts(ctx, userID, format, res); err != nil {
+			api.log.WithField("seller_id", userID).WithError(err).Error("Failed to stream product export")
+		}
+
+		return nil
+	}
+}
+
+// ------- HELPERS -------
+
+// getImportFormat membaca format dari query ?format=, lalu dari Content-Type, default csv
+func getImportFormat(c echo.Context) (string, error) {
+	format := strings.ToLower(c.QueryParam("format"))
+
+	if format == "" {
+		contentType := c.Request().Header.Get(echo.HeaderContentType)
+		switch {
+		case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonlines"):
+			format = entities.ImportFormatNDJSON
+		default:
+			format = entities.ImportFormatCSV
+		}
+	}
+
+	if format == "jsonl" {
+		format = entities.ImportFormatNDJSON
+	}
+
+	if format != entities.ImportFormatCSV && format != entities.ImportFormatNDJSON {
+		return "", apperrors.ErrUnsupportedImportFormat
+	}
+
+	return format, nil
+}
+
+func toProductImportJobResponse(job *entities.ProductImportJob) *models.ProductImportJobResponse {
+	rowErrors := make([]models.ProductImportRowErrorResponse, len(job.Errors))
+	for i, e := range job.Errors {
+		rowErrors[i] = models.ProductImportRowErrorResponse{
+			Row:         e.Row,
+			ExternalSKU: e.ExternalSKU,
+			Message:     e.Message,
+		}
+	}
+
+	res := &models.ProductImportJobResponse{
+		ID:        job.ID.String(),
+		SellerID:  job.SellerID.String(),
+		Format:    job.Format,
+		Status:    job.Status,
+		TotalRows: job.TotalRows,
+		Succeeded: job.Succeeded,
+		Failed:    job.Failed,
+		Errors:    rowErrors,
+		CreatedAt: job.CreatedAt.Format(helpers.LAYOUTFORMAT),
+	}
+
+	if job.FinishedAt != nil {
+		res.FinishedAt = job.FinishedAt.Format(helpers.LAYOUTFORMAT)
+	}
+
+	return res
+}
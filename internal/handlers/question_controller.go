@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func (api *API) GetProductQuestions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		productID, err := getIDFromPathParam(c, "id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		limit, offset, err := getPageFromQueryParam(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.QuestionSvc.GetProductQuestions(ctx, productID, entities.QuestionSort(c.QueryParam("sort")), limit, offset)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionRetrieved, toQuestionResponseList(res, false))
+	}
+}
+
+func (api *API) AskQuestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		productID, err := getIDFromPathParam(c, "id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.QuestionRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.QuestionSvc.AskQuestion(ctx, userID, productID, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusCreated, MsgQuestionCreated, toQuestionResponse(res, false))
+	}
+}
+
+func (api *API) AnswerQuestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		questionID, err := getIDFromPathParam(c, "question_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.QuestionAnswerRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.QuestionSvc.AnswerQuestion(ctx, subject, questionID, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionAnswered, toQuestionResponse(res, false))
+	}
+}
+
+func (api *API) UpvoteQuestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		questionID, err := getIDFromPathParam(c, "question_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.QuestionSvc.UpvoteQuestion(ctx, userID, questionID)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionUpvoted, toQuestionResponse(res, false))
+	}
+}
+
+func (api *API) RemoveQuestionUpvote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		questionID, err := getIDFromPathParam(c, "question_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.QuestionSvc.RemoveUpvote(ctx, userID, questionID)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionUpvoteRemoved, toQuestionResponse(res, false))
+	}
+}
+
+func (api *API) FlagQuestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		questionID, err := getIDFromPathParam(c, "question_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.QuestionFlagRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		if err := api.QuestionSvc.FlagQuestion(ctx, userID, questionID, &req); err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionFlagged, nil)
+	}
+}
+
+func (api *API) GetSellerQuestionInbox() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		sellerID := uuid.Nil
+		if sellerIDStr := c.QueryParam("seller_id"); sellerIDStr != "" {
+			sellerID, err = helpers.StringToUUID(sellerIDStr)
+			if err != nil {
+				return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+			}
+		}
+
+		limit, offset, err := getPageFromQueryParam(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.QuestionSvc.GetSellerInbox(ctx, subject, sellerID, limit, offset)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionRetrieved, toQuestionResponseList(res, false))
+	}
+}
+
+func (api *API) GetFlaggedQuestions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		limit, offset, err := getPageFromQueryParam(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.QuestionSvc.GetFlaggedQuestions(ctx, subject, entities.QuestionStatus(c.QueryParam("status")), limit, offset)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionRetrieved, toQuestionResponseList(res, true))
+	}
+}
+
+func (api *API) ModerateQuestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		questionID, err := getIDFromPathParam(c, "question_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.QuestionModerationRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.QuestionSvc.ModerateQuestion(ctx, subject, questionID, entities.QuestionStatus(req.Status))
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgQuestionModerated, toQuestionResponse(res, true))
+	}
+}
+
+// ------- HELPERS -------
+
+// Jumlah flag hanya ditampilkan di endpoint moderasi
+func toQuestionResponse(question *entities.ProductQuestion, withModeration bool) *models.QuestionResponse {
+	res := &models.QuestionResponse{
+		ID:        question.ID.String(),
+		ProductID: question.ProductID.String(),
+		UserID:    question.UserID.String(),
+		Body:      question.Body,
+		Answer:    question.Answer,
+		Upvotes:   question.Upvotes,
+		Status:    string(question.Status),
+		CreatedAt: question.CreatedAt.Format(helpers.LAYOUTFORMAT),
+		UpdatedAt: question.UpdatedAt.Format(helpers.LAYOUTFORMAT),
+	}
+	if question.AnsweredAt != nil {
+		res.AnsweredBy = question.AnsweredBy.String()
+		res.AnsweredAt = question.AnsweredAt.Format(helpers.LAYOUTFORMAT)
+	}
+	if withModeration {
+		res.FlagCount = question.FlagCount
+	}
+
+	return res
+}
+
+func toQuestionResponseList(questions []entities.ProductQuestion, withModeration bool) []*models.QuestionResponse {
+	res := make([]*models.QuestionResponse, len(questions))
+	for i := range questions {
+		res[i] = toQuestionResponse(&questions[i], withModeration)
+	}
+
+	return res
+}
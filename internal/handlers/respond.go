@@ -16,17 +16,51 @@ const (
 	MsgProductUpdated   = "Product updated successfully"
 	MsgProductDeleted   = "Product deleted successfully"
 
+	MsgProductStatusChanged = "Product status changed successfully"
+
+	MsgAttributeDefinitionRetrieved = "Attribute definitions retrieved successfully"
+	MsgAttributeDefinitionSaved     = "Attribute definition saved successfully"
+	MsgAttributeDefinitionDeleted   = "Attribute definition deleted successfully"
+
+	MsgProductBulkApplied     = "Bulk product operation applied successfully"
+	MsgProductImportAccepted  = "Product import accepted"
+	MsgProductImportRetrieved = "Product import job retrieved successfully"
+
 	MsgFailedToRetrieveProduct = "Failed to retrieve product"
 	MsgFailedToCreateProduct   = "Failed to create product"
 	MsgFailedToUpdateProduct   = "Failed to update product"
 	MsgFailedToDeleteProduct   = "Failed to delete product"
 
+	MsgCacheStatsRetrieved = "Cache stats retrieved successfully"
+	MsgCachePurged         = "Cache purged successfully"
+	MsgCacheWarmed         = "Cache warmed successfully"
+
+	MsgSellerStaffRetrieved = "Seller staff retrieved successfully"
+	MsgSellerStaffAdded     = "Seller staff added successfully"
+	MsgSellerStaffRemoved   = "Seller staff removed successfully"
+
+	MsgReviewRetrieved = "Reviews retrieved successfully"
+	MsgReviewCreated   = "Review created successfully"
+	MsgReviewUpdated   = "Review updated successfully"
+	MsgReviewDeleted   = "Review deleted successfully"
+	MsgReviewReplied   = "Review reply saved successfully"
+	MsgReviewModerated = "Review moderated successfully"
+
+	MsgQuestionRetrieved     = "Questions retrieved successfully"
+	MsgQuestionCreated       = "Question created successfully"
+	MsgQuestionAnswered      = "Question answered successfully"
+	MsgQuestionUpvoted       = "Question upvoted successfully"
+	MsgQuestionUpvoteRemoved = "Question upvote removed successfully"
+	MsgQuestionFlagged       = "Question flagged successfully"
+	MsgQuestionModerated     = "Question moderated successfully"
+
 	MsgCartRetrieved       = "Cart retrieved successfully"
 	MsgCartCreated         = "Cart created successfully"
 	MsgCartUpdated         = "Cart updated successfully"
 	MsgCartDeleted         = "Cart deleted successfully"
 	MsgCartCleared         = "Cart cleared successfully"
 	MsgCartCheckedOut      = "Cart checked out successfully"
+	MsgCartSellerNoteSaved = "Seller note saved successfully"
 	MsgFailedToRestoreCart = "Failed to restore cart"
 
 	MsgFailedToAddItemToCart = "Failed to add item to cart"
@@ -51,13 +85,21 @@ func respondError(c echo.Context, status int, err error) error {
 func handleGetError(c echo.Context, err error) error {
 	switch {
 	case errors.Is(err, apperrors.ErrInvalidUserInput),
-		errors.Is(err, apperrors.ErrInvalidCartOperation):
+		errors.Is(err, apperrors.ErrInvalidCartOperation),
+		errors.Is(err, apperrors.ErrUnknownTrendingWindow),
+		errors.Is(err, apperrors.ErrInvalidRequestPayload):
 		return respondError(c, http.StatusBadRequest, err)
 
 	case errors.Is(err, apperrors.ErrInsufficientStock),
 		errors.Is(err, apperrors.ErrCartAlreadyCheckedOut):
 		return respondError(c, http.StatusForbidden, err)
 
+	case errors.Is(err, apperrors.ErrImportJobNotFound),
+		errors.Is(err, apperrors.ErrProductNotFound),
+		errors.Is(err, apperrors.ErrReviewNotFound),
+		errors.Is(err, apperrors.ErrQuestionNotFound):
+		return respondError(c, http.StatusNotFound, err)
+
 	case errors.Is(err, apperrors.ErrInternalServerError):
 		return respondError(c, http.StatusInternalServerError, err)
 
@@ -69,10 +111,34 @@ func handleGetError(c echo.Context, err error) error {
 func handleOperationError(c echo.Context, err error) error {
 	switch {
 	case errors.Is(err, apperrors.ErrProductNotBelongToSeller),
+		errors.Is(err, apperrors.ErrActionNotPermitted),
 		errors.Is(err, apperrors.ErrInvalidUserInput),
 		errors.Is(err, apperrors.ErrInvalidCartOperation):
 		return respondError(c, http.StatusForbidden, err)
 
+	case errors.Is(err, apperrors.ErrProductNotFound),
+		errors.Is(err, apperrors.ErrSellerStaffNotFound),
+		errors.Is(err, apperrors.ErrCartItemNotFound),
+		errors.Is(err, apperrors.ErrReviewNotFound),
+		errors.Is(err, apperrors.ErrQuestionNotFound),
+		errors.Is(err, apperrors.ErrAttributeDefinitionNotFound):
+		return respondError(c, http.StatusNotFound, err)
+
+	case errors.Is(err, apperrors.ErrCartVersionConflict),
+		errors.Is(err, apperrors.ErrReviewAlreadyExists),
+		errors.Is(err, apperrors.ErrInvalidProductTransition),
+		errors.Is(err, apperrors.ErrProductNotActive):
+		return respondError(c, http.StatusConflict, err)
+
+	case errors.Is(err, apperrors.ErrUnsupportedImportFormat),
+		errors.Is(err, apperrors.ErrImportTooLarge),
+		errors.Is(err, apperrors.ErrUnknownCacheFamily),
+		errors.Is(err, apperrors.ErrInvalidCacheTarget),
+		errors.Is(err, apperrors.ErrInvalidCartVersion),
+		errors.Is(err, apperrors.ErrInvalidProductAttributes),
+		errors.Is(err, apperrors.ErrInvalidRequestPayload):
+		return respondError(c, http.StatusBadRequest, err)
+
 	case err.Error() == apperrors.ErrInvalidProductUpdatePayload.Error(),
 		errors.Is(err, apperrors.ErrInsufficientStock),
 		errors.Is(err, apperrors.ErrCartAlreadyCheckedOut):
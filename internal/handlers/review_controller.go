@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func (api *API) GetProductReviews() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		productID, err := getIDFromPathParam(c, "id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		limit, offset, err := getPageFromQueryParam(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ReviewSvc.GetProductReviews(ctx, productID, limit, offset)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewRetrieved, toReviewResponseList(res))
+	}
+}
+
+func (api *API) CreateReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		productID, err := getIDFromPathParam(c, "id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.ReviewRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ReviewSvc.CreateReview(ctx, userID, productID, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusCreated, MsgReviewCreated, toReviewResponse(res))
+	}
+}
+
+func (api *API) UpdateReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		reviewID, err := getIDFromPathParam(c, "review_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.ReviewRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ReviewSvc.UpdateReview(ctx, userID, reviewID, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewUpdated, toReviewResponse(res))
+	}
+}
+
+func (api *API) DeleteReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		reviewID, err := getIDFromPathParam(c, "review_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		if err := api.ReviewSvc.DeleteReview(ctx, subject, reviewID); err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewDeleted, nil)
+	}
+}
+
+func (api *API) ReplyToReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		reviewID, err := getIDFromPathParam(c, "review_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.ReviewReplyRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ReviewSvc.ReplyToReview(ctx, subject, reviewID, &req)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewReplied, toReviewResponse(res))
+	}
+}
+
+func (api *API) GetReviewsForModeration() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		limit, offset, err := getPageFromQueryParam(c)
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.ReviewSvc.GetReviewsByStatus(ctx, subject, entities.ReviewStatus(c.QueryParam("status")), limit, offset)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewRetrieved, toReviewResponseList(res))
+	}
+}
+
+func (api *API) ModerateReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		reviewID, err := getIDFromPathParam(c, "review_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		var req models.ReviewModerationRequest
+		if err := c.Bind(&req); err != nil {
+			return respondError(c, http.StatusBadRequest, apperrors.ErrInvalidRequestPayload)
+		}
+
+		res, err := api.ReviewSvc.ModerateReview(ctx, subject, reviewID, entities.ReviewStatus(req.Status))
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgReviewModerated, toReviewResponse(res))
+	}
+}
+
+// ------- HELPERS -------
+
+func getPageFromQueryParam(c echo.Context) (limit, offset int, err error) {
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return 0, 0, apperrors.ErrInvalidRequestPayload
+		}
+	}
+
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return 0, 0, apperrors.ErrInvalidRequestPayload
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func toReviewResponse(review *entities.ProductReview) *models.ReviewResponse {
+	res := &models.ReviewResponse{
+		ID:               review.ID.String(),
+		ProductID:        review.ProductID.String(),
+		UserID:           review.UserID.String(),
+		Rating:           review.Rating,
+		Title:            review.Title,
+		Body:             review.Body,
+		VerifiedPurchase: review.VerifiedPurchase,
+		Status:           string(review.Status),
+		SellerReply:      review.SellerReply,
+		CreatedAt:        review.CreatedAt.Format(helpers.LAYOUTFORMAT),
+		UpdatedAt:        review.UpdatedAt.Format(helpers.LAYOUTFORMAT),
+	}
+	if review.SellerRepliedAt != nil {
+		res.SellerRepliedAt = review.SellerRepliedAt.Format(helpers.LAYOUTFORMAT)
+	}
+
+	return res
+}
+
+func toReviewResponseList(reviews []entities.ProductReview) []*models.ReviewResponse {
+	res := make([]*models.ReviewResponse, len(reviews))
+	for i := range reviews {
+		res[i] = toReviewResponse(&reviews[i])
+	}
+
+	return res
+}
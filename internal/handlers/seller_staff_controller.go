@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func (api *API) GetSellerStaff() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		res, err := api.StaffSvc.GetStaff(ctx, subject)
+		if err != nil {
+			return handleGetError(c, err)
+		}
+
+		staff := make([]*models.SellerStaffResponse, len(res))
+		for i := range res {
+			staff[i] = toSellerStaffResponse(&res[i])
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgSellerStaffRetrieved, staff)
+	}
+}
+
+func (api *API) AddSellerStaff() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		userID, err := getIDFromPathParam(c, "user_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		res, err := api.StaffSvc.AddStaff(ctx, subject, userID)
+		if err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusCreated, MsgSellerStaffAdded, toSellerStaffResponse(res))
+	}
+}
+
+func (api *API) RemoveSellerStaff() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		subject, err := getSubjectFromContext(c)
+		if err != nil {
+			return respondError(c, http.StatusUnauthorized, apperrors.ErrInvalidUserSession)
+		}
+
+		userID, err := getIDFromPathParam(c, "user_id")
+		if err != nil {
+			return respondError(c, http.StatusBadRequest, err)
+		}
+
+		if err := api.StaffSvc.RemoveStaff(ctx, subject, userID); err != nil {
+			return handleOperationError(c, err)
+		}
+
+		return respondSuccess(c, http.StatusOK, MsgSellerStaffRemoved, nil)
+	}
+}
+
+// ------- HELPERS -------
+
+func toSellerStaffResponse(staff *entities.SellerStaff) *models.SellerStaffResponse {
+	return &models.SellerStaffResponse{
+		SellerID:  staff.SellerID.String(),
+		UserID:    staff.UserID.String(),
+		CreatedAt: staff.CreatedAt.Format(helpers.LAYOUTFORMAT),
+	}
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -30,6 +31,13 @@ func StringToNullString(val string) sql.NullString {
 	}
 }
 
+func OptionalStringToNullString(val string) sql.NullString {
+	return sql.NullString{
+		String: val,
+		Valid:  val != "",
+	}
+}
+
 func ConvertNullInt32(v reflect.Value) int {
 	nullInt, ok := v.Interface().(sql.NullInt32)
 	if !ok {
@@ -55,3 +63,18 @@ func ConvertNullString(v reflect.Value) string {
 
 	return ""
 }
+
+// ConvertJSONMap membaca kolom JSONB object; nilai kosong atau tidak valid menjadi nil
+func ConvertJSONMap(v reflect.Value) map[string]interface{} {
+	raw, ok := v.Interface().(json.RawMessage)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	var res map[string]interface{}
+	if err := json.Unmarshal(raw, &res); err != nil || len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
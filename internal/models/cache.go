@@ -0,0 +1,16 @@
+package models
+
+type CacheWarmRequest struct {
+	TopN     int    `json:"top_n"`
+	SellerID string `json:"seller_id"`
+}
+
+type CacheFamilyStatsResponse struct {
+	Family      string `json:"family"`
+	Keys        int    `json:"keys"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+type CacheOperationResponse struct {
+	Keys int `json:"keys"`
+}
@@ -6,12 +6,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// CartItemOptions adalah opsi tambahan per baris cart. Catalog tidak menjalankan checkout: order service membacanya
+// dari GET /cart (CartResponse) saat membuat order dan menyimpannya di order miliknya.
+type CartItemOptions struct {
+	GiftWrap    bool   `json:"gift_wrap,omitempty"`
+	GiftMessage string `json:"gift_message,omitempty"`
+	VariantNote string `json:"variant_note,omitempty"`
+}
+
 type RedisCartItem struct {
-	Quantity    int       `json:"quantity"`
-	Description string    `json:"description,omitempty"`
-	Checked     bool      `json:"checked"`
-	AddedAt     time.Time `json:"added_at"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Description string `json:"description,omitempty"`
+	CartItemOptions
+	Checked   bool      `json:"checked"`
+	AddedAt   time.Time `json:"added_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
 }
 
 type CartItem struct {
@@ -27,28 +36,46 @@ type CartItem struct {
 }
 
 type CartItemResponse struct {
-	SellerName   string  `json:"seller_name"`
-	ProductID    string  `json:"product_id"`
-	ProductName  string  `json:"product_name"`
-	ProductImage string  `json:"product_image"`
-	Price        float64 `json:"price"`
-	Quantity     int     `json:"quantity"`
-	Description  string  `json:"description"`
-	Checked      bool    `json:"checked"`
+	SellerName    string  `json:"seller_name"`
+	SellerUnknown bool    `json:"seller_unknown,omitempty"`
+	ProductID     string  `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	ProductImage  string  `json:"product_image"`
+	Price         float64 `json:"price"`
+	Quantity      int     `json:"quantity"`
+	Description   string  `json:"description"`
+	GiftWrap      bool    `json:"gift_wrap"`
+	GiftMessage   string  `json:"gift_message,omitempty"`
+	VariantNote   string  `json:"variant_note,omitempty"`
+	Checked       bool    `json:"checked"`
 }
 
 type CartResponse struct {
 	UserID     string             `json:"user_id"`
 	TotalItems int                `json:"total_items"`
+	Version    int64              `json:"version"`
+	Degraded   bool               `json:"degraded,omitempty"`
 	Items      []CartItemResponse `json:"items"`
+	// SellerNotes berisi catatan pengiriman per seller, dengan key seller_id; dibaca order service saat checkout
+	SellerNotes map[string]string `json:"seller_notes,omitempty"`
 }
 
 type CartRequest struct {
 	Quantity    int    `json:"quantity" validate:"required,min=1"`
 	Description string `json:"description"`
+	CartItemOptions
 }
 
 type UpdateCartRequest struct {
 	Quantity    int    `json:"quantity" validate:"required"`
 	Description string `json:"description"`
+	CartItemOptions
+}
+
+type CartVersionResponse struct {
+	Version int64 `json:"version"`
+}
+
+type CartSellerNoteRequest struct {
+	Note string `json:"note"`
 }
@@ -13,18 +13,34 @@ type ProductRequest struct {
 	Discount    int    `json:"discount" validate:"gte=0,lte=100"`
 	Type        string `json:"type" validate:"required"`
 	Description string `json:"description"`
+	ExternalSKU string `json:"external_sku" validate:"omitempty,max=64"`
+	// Status hanya dipakai saat create; perubahan berikutnya lewat endpoint transisi status
+	Status string `json:"status" validate:"omitempty,oneof=draft active"`
+	// Attributes divalidasi terhadap definisi atribut type produk; saat update nil berarti atribut lama dipertahankan
+	Attributes map[string]interface{} `json:"attributes" validate:"omitempty,max=50"`
+}
+
+type ProductStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft active archived banned"`
+	Reason string `json:"reason" validate:"max=500"`
 }
 type ProductResponse struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	Name        string    `json:"name"`
-	Price       int       `json:"price"`
-	Stock       int       `json:"stock"`
-	Discount    int       `json:"discount"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	CreatedAt   string    `json:"created_at"`
-	UpdatedAt   string    `json:"updated_at"`
+	ID           uuid.UUID              `json:"id"`
+	SellerID     uuid.UUID              `json:"seller_id"`
+	Name         string                 `json:"name"`
+	Price        int                    `json:"price"`
+	Stock        int                    `json:"stock"`
+	Discount     int                    `json:"discount"`
+	Type         string                 `json:"type"`
+	Description  string                 `json:"description"`
+	ExternalSKU  string                 `json:"external_sku,omitempty"`
+	RatingAvg    float64                `json:"rating_avg"`
+	RatingCount  int                    `json:"rating_count"`
+	Status       string                 `json:"status"`
+	StatusReason string                 `json:"status_reason,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
+	UpdatedAt    string                 `json:"updated_at"`
 }
 
 type ProductWithSeller struct {
@@ -39,3 +55,31 @@ type ProductWithSeller struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+type BulkProductFilter struct {
+	SellerID string `json:"seller_id" validate:"omitempty,uuid"`
+	Type     string `json:"type"`
+}
+
+type BulkProductRequest struct {
+	IDs       []string           `json:"ids" validate:"omitempty,max=1000,dive,uuid"`
+	Filter    *BulkProductFilter `json:"filter"`
+	Operation string             `json:"operation" validate:"required,oneof=adjust_price set_discount set_type delete"`
+
+	PricePercent float64 `json:"price_percent" validate:"omitempty,gt=-100,lte=1000"`
+	Discount     *int    `json:"discount" validate:"omitempty,gte=0,lte=100"`
+	Type         string  `json:"type" validate:"omitempty,min=1,max=100"`
+}
+
+type BulkProductResponse struct {
+	Operation string             `json:"operation"`
+	Affected  int                `json:"affected"`
+	Products  []*ProductResponse `json:"products"`
+}
+
+const (
+	BulkOperationAdjustPrice = "adjust_price"
+	BulkOperationSetDiscount = "set_discount"
+	BulkOperationSetType     = "set_type"
+	BulkOperationDelete      = "delete"
+)
@@ -0,0 +1,19 @@
+package models
+
+type AttributeDefinitionRequest struct {
+	DataType      string   `json:"data_type" validate:"required,oneof=string number boolean"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values" validate:"omitempty,max=100,dive,min=1,max=100"`
+	Unit          string   `json:"unit" validate:"max=20"`
+}
+
+type AttributeDefinitionResponse struct {
+	ProductType   string   `json:"product_type"`
+	Key           string   `json:"key"`
+	DataType      string   `json:"data_type"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+	Unit          string   `json:"unit,omitempty"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+type ProductImportRowErrorResponse struct {
+	Row         int    `json:"row"`
+	ExternalSKU string `json:"external_sku,omitempty"`
+	Message     string `json:"message"`
+}
+
+type ProductImportJobResponse struct {
+	ID         string                          `json:"id"`
+	SellerID   string                          `json:"seller_id"`
+	Format     string                          `json:"format"`
+	Status     string                          `json:"status"`
+	TotalRows  int                             `json:"total_rows"`
+	Succeeded  int                             `json:"succeeded"`
+	Failed     int                             `json:"failed"`
+	Errors     []ProductImportRowErrorResponse `json:"errors"`
+	CreatedAt  string                          `json:"created_at"`
+	FinishedAt string                          `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+type QuestionRequest struct {
+	Body string `json:"body"`
+}
+
+type QuestionAnswerRequest struct {
+	Answer string `json:"answer"`
+}
+
+type QuestionFlagRequest struct {
+	Reason string `json:"reason"`
+}
+
+type QuestionModerationRequest struct {
+	Status string `json:"status"`
+}
+
+type QuestionResponse struct {
+	ID         string `json:"id"`
+	ProductID  string `json:"product_id"`
+	UserID     string `json:"user_id"`
+	Body       string `json:"body"`
+	Answer     string `json:"answer,omitempty"`
+	AnsweredBy string `json:"answered_by,omitempty"`
+	AnsweredAt string `json:"answered_at,omitempty"`
+	Upvotes    int    `json:"upvotes"`
+	FlagCount  int    `json:"flag_count,omitempty"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type RelatedProductResponse struct {
+	Product *ProductResponse `json:"product"`
+	Reason  string           `json:"reason"`
+}
@@ -0,0 +1,30 @@
+package models
+
+type ReviewRequest struct {
+	Rating int    `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply"`
+}
+
+type ReviewModerationRequest struct {
+	Status string `json:"status"`
+}
+
+type ReviewResponse struct {
+	ID               string `json:"id"`
+	ProductID        string `json:"product_id"`
+	UserID           string `json:"user_id"`
+	Rating           int    `json:"rating"`
+	Title            string `json:"title"`
+	Body             string `json:"body"`
+	VerifiedPurchase bool   `json:"verified_purchase"`
+	Status           string `json:"status"`
+	SellerReply      string `json:"seller_reply,omitempty"`
+	SellerRepliedAt  string `json:"seller_replied_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type SellerStaffResponse struct {
+	SellerID  string `json:"seller_id"`
+	UserID    string `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
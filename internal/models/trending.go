@@ -0,0 +1,6 @@
+package models
+
+type TrendingProductResponse struct {
+	Product *ProductResponse `json:"product"`
+	Score   float64          `json:"score"`
+}
@@ -0,0 +1,68 @@
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker mencatat goroutine background (cache, import, dll) supaya bisa ditunggu saat shutdown
+type Tracker struct {
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// workerCtx dibatalkan di awal Shutdown untuk menghentikan loop yang berjalan terus (consumer, job periodik)
+	workerCtx    context.Context
+	cancelWorker context.CancelFunc
+}
+
+func NewTracker() *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	workerCtx, cancelWorker := context.WithCancel(ctx)
+	return &Tracker{
+		ctx:          ctx,
+		cancel:       cancel,
+		workerCtx:    workerCtx,
+		cancelWorker: cancelWorker,
+	}
+}
+
+// Go menjalankan fn di goroutine baru. ctx yang diterima fn tidak terikat request dan baru dibatalkan
+// jika Shutdown melewati deadline.
+func (t *Tracker) Go(fn func(ctx context.Context)) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		fn(t.ctx)
+	}()
+}
+
+// GoWorker menjalankan fn yang baru berhenti saat ctx-nya dibatalkan. ctx dibatalkan begitu Shutdown dipanggil,
+// lalu Shutdown menunggu fn selesai seperti task lain.
+func (t *Tracker) GoWorker(fn func(ctx context.Context)) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		fn(t.workerCtx)
+	}()
+}
+
+// Shutdown menghentikan worker lalu menunggu semua task selesai. Jika ctx habis lebih dulu, task yang masih jalan dibatalkan.
+func (t *Tracker) Shutdown(ctx context.Context) error {
+	t.cancelWorker()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.cancel()
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,487 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/configs"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+const (
+	// negativeMarker disimpan sebagai pengganti nilai untuk hasil "tidak ditemukan"
+	negativeMarker = "\x00nf"
+	tagKeyPrefix   = "cache_tag:"
+)
+
+// invalidateScript menghapus semua key yang terdaftar di tag beserta set tag-nya dalam satu round trip,
+// lalu mengembalikan daftar key tersebut untuk disiarkan ke cache lokal instance lain
+var invalidateScript = redis.NewScript(`
+local invalidated = {}
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call('SMEMBERS', tag)
+	for i = 1, #keys, 500 do
+		redis.call('DEL', unpack(keys, i, math.min(i + 499, #keys)))
+	end
+	for _, key in ipairs(keys) do
+		table.insert(invalidated, key)
+	end
+	redis.call('DEL', tag)
+end
+return invalidated
+`)
+
+// invalidationMessage dikirim lewat Redis pub/sub supaya semua instance mengosongkan cache lokal bersamaan
+type invalidationMessage struct {
+	Keys []string `json:"keys,omitempty"`
+	All  bool     `json:"all,omitempty"`
+}
+
+// Cache adalah lapisan cache-aside di atas Redis. Miss untuk key yang sama digabung lewat singleflight,
+// TTL diberi jitter, hasil "tidak ditemukan" bisa di-cache, dan setiap entry dapat ditandai dengan tag
+// sehingga satu perubahan data bisa menghapus semua entry yang memuatnya.
+// Entry dengan Options.Local juga disimpan di cache lokal (L1) jika diaktifkan.
+type Cache struct {
+	client      *redis.Client
+	group       singleflight.Group
+	local       *localCache // nil jika L1 dimatikan
+	channel     string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	jitter      float64
+	log         *logrus.Logger
+}
+
+func New(redisClient *customRedis.RedisClient, cfg *configs.CacheConfig, log *logrus.Logger) *Cache {
+	c := &Cache{
+		client:      redisClient.Client,
+		channel:     cfg.InvalidationChannel,
+		ttl:         cfg.TTL,
+		negativeTTL: cfg.NegativeTTL,
+		jitter:      cfg.TTLJitter,
+		log:         log,
+	}
+
+	if cfg.LocalEnabled && cfg.LocalSize > 0 {
+		c.local = newLocalCache(cfg.LocalSize, cfg.LocalTTL)
+	}
+
+	return c
+}
+
+type Options[T any] struct {
+	TTL time.Duration // 0 berarti memakai TTL default
+	// Local menyimpan entry juga di cache lokal (L1); cocok untuk key per-entity yang sering dibaca.
+	// Nilai di L1 dipakai bersama oleh semua pemanggil, jadi tidak boleh dimodifikasi.
+	Local bool
+	// Tags dipanggil dengan nilai hasil load untuk menentukan tag entry
+	Tags func(value T) []string
+	// NotFound adalah error dari loader yang di-cache sebagai negative entry dan dikembalikan saat hit
+	NotFound error
+}
+
+type Entry[T any] struct {
+	Key   string
+	Value T
+	Tags  []string
+}
+
+// GetOrLoad mengembalikan nilai dari cache, atau memanggil load sekali untuk semua pemanggil yang miss bersamaan.
+// Loader berjalan dengan context yang tidak ikut dibatalkan supaya pembatalan satu request tidak menggagalkan request lain.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, opts Options[T], load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	local := c.localFor(opts.Local)
+	var gen uint64
+	if local != nil {
+		if entry, ok := local.get(key); ok {
+			metrics.ObserveLocalCache(key, true)
+			if entry.negative {
+				return zero, opts.NotFound
+			}
+			return entry.value.(T), nil
+		}
+		metrics.ObserveLocalCache(key, false)
+		gen = local.generation()
+	}
+
+	val, err := c.client.Get(ctx, key).Result()
+	switch {
+	case err == nil && val == negativeMarker && opts.NotFound != nil:
+		metrics.ObserveCache(key, true)
+		if local != nil {
+			local.set(key, nil, true, gen)
+		}
+		return zero, opts.NotFound
+	case err == nil:
+		var value T
+		if err := json.Unmarshal([]byte(val), &value); err == nil {
+			metrics.ObserveCache(key, true)
+			if local != nil {
+				local.set(key, value, false, gen)
+			}
+			return value, nil
+		}
+		c.log.WithField("key", key).Warn("Failed to unmarshal cached value, reloading")
+	case err != redis.Nil:
+		c.log.WithError(err).WithField("key", key).Warn("Failed to read cache, falling back to loader")
+	}
+	metrics.ObserveCache(key, false)
+
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+
+		value, err := load(loadCtx)
+		if err != nil {
+			if opts.NotFound != nil && errors.Is(err, opts.NotFound) {
+				c.SetNegative(loadCtx, key, nil)
+				if local != nil {
+					local.set(key, nil, true, gen)
+				}
+			}
+			return nil, err
+		}
+
+		var tags []string
+		if opts.Tags != nil {
+			tags = opts.Tags(value)
+		}
+		c.set(loadCtx, key, value, opts.TTL, tags)
+		if local != nil {
+			local.set(key, value, false, gen)
+		}
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+// GetMany membaca banyak key sekaligus, dari cache lokal lalu MGET untuk sisanya. missed berisi indeks key
+// yang harus diambil dari sumber data; key dengan negative entry tidak muncul di values maupun missed.
+// Hanya opts.Local yang dipakai.
+func GetMany[T any](ctx context.Context, c *Cache, keys []string, opts Options[T]) (values []T, missed []int) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	values = make([]T, 0, len(keys))
+	remaining := make([]int, 0, len(keys))
+
+	local := c.localFor(opts.Local)
+	var gen uint64
+	if local != nil {
+		gen = local.generation()
+	}
+
+	for i, key := range keys {
+		if local == nil {
+			remaining = append(remaining, i)
+			continue
+		}
+
+		entry, ok := local.get(key)
+		metrics.ObserveLocalCache(key, ok)
+		switch {
+		case !ok:
+			remaining = append(remaining, i)
+		case !entry.negative:
+			values = append(values, entry.value.(T))
+		}
+	}
+	if len(remaining) == 0 {
+		return values, nil
+	}
+
+	remoteKeys := make([]string, len(remaining))
+	for i, idx := range remaining {
+		remoteKeys[i] = keys[idx]
+	}
+
+	results, err := c.client.MGet(ctx, remoteKeys...).Result()
+	if err != nil {
+		c.log.WithError(err).Warn("Failed to run MGET, treating every key as a miss")
+		for _, key := range remoteKeys {
+			metrics.ObserveCache(key, false)
+		}
+		return values, remaining
+	}
+
+	for i, result := range results {
+		key := remoteKeys[i]
+
+		raw, ok := result.(string)
+		if ok && raw == negativeMarker {
+			metrics.ObserveCache(key, true)
+			if local != nil {
+				local.set(key, nil, true, gen)
+			}
+			continue
+		}
+
+		var value T
+		if ok && json.Unmarshal([]byte(raw), &value) == nil {
+			metrics.ObserveCache(key, true)
+			if local != nil {
+				local.set(key, value, false, gen)
+			}
+			values = append(values, value)
+			continue
+		}
+
+		metrics.ObserveCache(key, false)
+		missed = append(missed, remaining[i])
+	}
+
+	return values, missed
+}
+
+// SetMany menyimpan banyak entry dalam satu pipeline
+func SetMany[T any](ctx context.Context, c *Cache, entries []Entry[T]) {
+	if len(entries) == 0 {
+		return
+	}
+
+	pipe := c.client.Pipeline()
+	for _, entry := range entries {
+		payload, err := json.Marshal(entry.Value)
+		if err != nil {
+			c.log.WithError(err).WithField("key", entry.Key).Error("Failed to marshal value for caching")
+			continue
+		}
+		c.queueSet(ctx, pipe, entry.Key, payload, c.jittered(c.ttl), entry.Tags)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.WithError(err).Warnf("Failed to cache %d entries", len(entries))
+	}
+}
+
+// SetNegative mencatat bahwa key tidak memiliki data sehingga lookup berikutnya tidak menyentuh database
+func (c *Cache) SetNegative(ctx context.Context, key string, tags []string) {
+	pipe := c.client.Pipeline()
+	c.queueSet(ctx, pipe, key, negativeMarker, c.jittered(c.negativeTTL), tags)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.WithError(err).WithField("key", key).Warn("Failed to cache not-found entry")
+	}
+}
+
+// Invalidate menghapus semua entry yang ditandai dengan salah satu tag dan mengembalikan jumlah key yang terdaftar
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		tagKeys[i] = tagKeyPrefix + tag
+	}
+
+	keys, err := invalidateScript.Run(ctx, c.client, tagKeys).StringSlice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to invalidate cache tags %v: %w", tags, err)
+	}
+
+	c.log.WithField("tags", tags).Debugf("Invalidated %d cache entries", len(keys))
+
+	if len(keys) > 0 {
+		if c.local != nil {
+			c.local.delete(keys...)
+		}
+		c.broadcast(ctx, invalidationMessage{Keys: keys})
+	}
+
+	return len(keys), nil
+}
+
+// DeletePattern menghapus semua key yang cocok dengan pola SCAN, termasuk set tag jika polanya cocok
+func (c *Cache) DeletePattern(ctx context.Context, patterns ...string) (int, error) {
+	deleted := 0
+
+	for _, pattern := range patterns {
+		var cursor uint64
+		for {
+			keys, nextCursor, err := c.client.Scan(ctx, cursor, pattern, 100).Result()
+			if err != nil {
+				return deleted, fmt.Errorf("failed to scan keys with pattern '%s': %w", pattern, err)
+			}
+
+			if len(keys) > 0 {
+				n, err := c.client.Del(ctx, keys...).Result()
+				if err != nil {
+					return deleted, fmt.Errorf("failed to delete %d keys: %w", len(keys), err)
+				}
+				deleted += int(n)
+			}
+
+			cursor = nextCursor
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+
+	// Key yang cocok dengan pola tidak diketahui satu per satu, jadi cache lokal dikosongkan seluruhnya
+	if c.local != nil {
+		c.local.purge()
+	}
+	c.broadcast(ctx, invalidationMessage{All: true})
+
+	return deleted, nil
+}
+
+// Stats menghitung jumlah key dan perkiraan memori (MEMORY USAGE) untuk key yang cocok dengan pola SCAN
+func (c *Cache) Stats(ctx context.Context, pattern string) (keys int, memoryBytes int64, err error) {
+	var cursor uint64
+	for {
+		page, nextCursor, err := c.client.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return keys, memoryBytes, fmt.Errorf("failed to scan keys with pattern '%s': %w", pattern, err)
+		}
+
+		if len(page) > 0 {
+			pipe := c.client.Pipeline()
+			usages := make([]*redis.IntCmd, len(page))
+			for i, key := range page {
+				usages[i] = pipe.MemoryUsage(ctx, key)
+			}
+			// Key yang kedaluwarsa di antara SCAN dan MEMORY USAGE menghasilkan redis.Nil dan dilewati
+			if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+				return keys, memoryBytes, fmt.Errorf("failed to read memory usage: %w", err)
+			}
+
+			for _, usage := range usages {
+				if n, err := usage.Result(); err == nil {
+					keys++
+					memoryBytes += n
+				}
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			return keys, memoryBytes, nil
+		}
+	}
+}
+
+// Subscribe menerima pesan invalidasi dari instance lain sampai ctx dibatalkan.
+// Tidak melakukan apa pun jika cache lokal dimatikan.
+func (c *Cache) Subscribe(ctx context.Context) {
+	if c.local == nil {
+		return
+	}
+
+	pubsub := c.client.Subscribe(ctx, c.channel)
+	defer pubsub.Close()
+
+	c.log.WithField("channel", c.channel).Info("Listening for cache invalidation messages")
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var payload invalidationMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
+				c.log.WithError(err).Warn("Ignoring malformed cache invalidation message")
+				continue
+			}
+
+			if payload.All {
+				c.local.purge()
+			} else {
+				c.local.delete(payload.Keys...)
+			}
+		}
+	}
+}
+
+// broadcast tetap dikirim walaupun L1 instance ini mati, karena instance lain bisa saja mengaktifkannya
+func (c *Cache) broadcast(ctx context.Context, msg invalidationMessage) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		c.log.WithError(err).Error("Failed to marshal cache invalidation message")
+		return
+	}
+
+	if err := c.client.Publish(ctx, c.channel, payload).Err(); err != nil {
+		c.log.WithError(err).Warn("Failed to publish cache invalidation message")
+	}
+}
+
+func (c *Cache) localFor(enabled bool) *localCache {
+	if !enabled {
+		return nil
+	}
+
+	return c.local
+}
+
+func (c *Cache) set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		c.log.WithError(err).WithField("key", key).Error("Failed to marshal value for caching")
+		return
+	}
+
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	pipe := c.client.Pipeline()
+	c.queueSet(ctx, pipe, key, payload, c.jittered(ttl), tags)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.WithError(err).WithField("key", key).Warn("Failed to set cache")
+	}
+}
+
+// queueSet mendaftarkan key ke setiap set tag. Set tag hidup lebih lama dari entry terlama yang mungkin
+// (TTL default + jitter) agar invalidasi tetap menemukan key selama key tersebut masih ada.
+func (c *Cache) queueSet(ctx context.Context, pipe redis.Pipeliner, key string, payload interface{}, ttl time.Duration, tags []string) {
+	pipe.Set(ctx, key, payload, ttl)
+
+	tagTTL := c.ttl + time.Duration(c.jitter*float64(c.ttl))
+	if ttl > tagTTL {
+		tagTTL = ttl
+	}
+	tagTTL += c.ttl
+
+	for _, tag := range tags {
+		tagKey := tagKeyPrefix + tag
+		pipe.SAdd(ctx, tagKey, key)
+		pipe.Expire(ctx, tagKey, tagTTL)
+	}
+}
+
+func (c *Cache) jittered(ttl time.Duration) time.Duration {
+	if c.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	return ttl + time.Duration(rand.Float64()*c.jitter*float64(ttl))
+}
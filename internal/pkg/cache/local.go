@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+)
+
+// localCache adalah LRU berukuran tetap dengan TTL per entry. Nilai disimpan dalam bentuk
+// yang sudah di-decode sehingga hit tidak perlu round trip Redis maupun unmarshal JSON.
+type localCache struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List // depan = paling baru dipakai
+	capacity int
+	ttl      time.Duration
+	// gen naik setiap ada invalidasi. Nilai yang dibaca sebelum invalidasi tidak boleh masuk ke cache
+	// setelahnya, jadi set hanya dilakukan jika gen belum berubah sejak nilai mulai dibaca.
+	gen uint64
+}
+
+type localEntry struct {
+	key       string
+	value     interface{}
+	negative  bool
+	expiresAt time.Time
+}
+
+func newLocalCache(capacity int, ttl time.Duration) *localCache {
+	return &localCache{
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		capacity: capacity,
+		ttl:      ttl,
+	}
+}
+
+func (l *localCache) get(key string) (*localEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*localEntry)
+	if time.Now().After(entry.expiresAt) {
+		l.removeElement(elem, metrics.LocalCacheEvictionExpired)
+		return nil, false
+	}
+
+	l.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (l *localCache) generation() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.gen
+}
+
+func (l *localCache) set(key string, value interface{}, negative bool, gen uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if gen != l.gen {
+		return
+	}
+
+	entry := &localEntry{
+		key:       key,
+		value:     value,
+		negative:  negative,
+		expiresAt: time.Now().Add(l.ttl),
+	}
+
+	if elem, ok := l.items[key]; ok {
+		elem.Value = entry
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(entry)
+	metrics.LocalCacheEntries.Inc()
+
+	for l.order.Len() > l.capacity {
+		l.removeElement(l.order.Back(), metrics.LocalCacheEvictionCapacity)
+	}
+}
+
+func (l *localCache) delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.gen++
+
+	for _, key := range keys {
+		if elem, ok := l.items[key]; ok {
+			l.removeElement(elem, metrics.LocalCacheEvictionInvalidated)
+		}
+	}
+}
+
+func (l *localCache) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.gen++
+
+	metrics.LocalCacheEvictionsTotal.WithLabelValues(metrics.LocalCacheEvictionInvalidated).Add(float64(len(l.items)))
+	metrics.LocalCacheEntries.Sub(float64(len(l.items)))
+
+	l.items = make(map[string]*list.Element, l.capacity)
+	l.order.Init()
+}
+
+func (l *localCache) removeElement(elem *list.Element, reason string) {
+	entry := elem.Value.(*localEntry)
+	delete(l.items, entry.key)
+	l.order.Remove(elem)
+
+	metrics.LocalCacheEvictionsTotal.WithLabelValues(reason).Inc()
+	metrics.LocalCacheEntries.Dec()
+}
@@ -10,8 +10,24 @@ var (
 	ErrInsufficientStock           = errors.New("insufficient stock for this quantity")
 	ErrInvalidUserInput            = errors.New("invalid user input")
 	ErrProductNotBelongToSeller    = errors.New("product does not belong to this seller")
+	ErrActionNotPermitted          = errors.New("action is not permitted for this role")
 	ErrInvalidProductUpdatePayload = errors.New("all required columns must not be empty and valid for update")
 	ErrProductOutOfStock           = errors.New("product out of stock")
+	ErrProductNotFound             = errors.New("product not found")
+	ErrProductNotActive            = errors.New("product is not active")
+	ErrInvalidProductTransition    = errors.New("product status transition is not allowed")
+	ErrInvalidProductAttributes    = errors.New("invalid product attributes")
+	ErrAttributeDefinitionNotFound = errors.New("attribute definition not found")
+
+	ErrUnsupportedImportFormat = errors.New("unsupported import format, expected csv or ndjson")
+	ErrImportTooLarge          = errors.New("import file exceeds the maximum allowed size")
+	ErrImportJobNotFound       = errors.New("import job not found")
+
+	ErrSellerStaffNotFound = errors.New("seller staff not found")
+
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+	ErrResumeTokenExpired = errors.New("resume token is older than the change feed retention, resync and watch without a token")
+	ErrInvalidPageToken   = errors.New("invalid page token")
 
 	ErrCartNotFound          = errors.New("cart item not found")
 	ErrInvalidCartOperation  = errors.New("invalid cart operation")
@@ -22,7 +38,19 @@ var (
 	MsgFailedToClearProductCaches = "failed to clear product cache"
 	MsgProductCacheCleared        = "product cache cleared"
 
-	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrUnknownTrendingWindow = errors.New("unknown trending window")
+
+	ErrUnknownCacheFamily = errors.New("unknown cache family")
+	ErrInvalidCacheTarget = errors.New("exactly one cache target must be specified")
+
+	ErrCartItemNotFound    = errors.New("cart item not found")
+	ErrCartVersionConflict = errors.New("cart was modified by another request")
+	ErrInvalidCartVersion  = errors.New("invalid cart version")
+
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrReviewAlreadyExists = errors.New("user has already reviewed this product")
+
+	ErrQuestionNotFound = errors.New("question not found")
 
 	ErrNotFound = errors.New("not found")
 
@@ -30,4 +58,7 @@ var (
 
 	ErrInvalidUserSession = errors.New("invalid user session")
 	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrAuthUnavailable    = errors.New("authentication service is unavailable")
 )
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StockShortage menjelaskan satu produk yang stoknya tidak mencukupi
+type StockShortage struct {
+	ProductID string
+	Requested int32
+	Available int32
+}
+
+// StockShortageError berisi semua produk yang gagal dalam satu permintaan pengurangan stok
+type StockShortageError struct {
+	Shortages []StockShortage
+}
+
+func (e *StockShortageError) Error() string {
+	parts := make([]string, 0, len(e.Shortages))
+	for _, s := range e.Shortages {
+		parts = append(parts, fmt.Sprintf("%s (requested %d, available %d)", s.ProductID, s.Requested, s.Available))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrProductOutOfStock, strings.Join(parts, ", "))
+}
+
+func (e *StockShortageError) Unwrap() error {
+	return ErrProductOutOfStock
+}
+
+// ProductsNotFoundError berisi ID produk yang tidak ditemukan dalam satu permintaan
+type ProductsNotFoundError struct {
+	ProductIDs []string
+}
+
+func (e *ProductsNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrProductNotFound, strings.Join(e.ProductIDs, ", "))
+}
+
+func (e *ProductsNotFoundError) Unwrap() error {
+	return ErrProductNotFound
+}
+
+// InactiveProduct menjelaskan satu produk yang tidak bisa diproses karena statusnya bukan active
+type InactiveProduct struct {
+	ProductID string
+	Status    string
+}
+
+// ProductsNotActiveError berisi semua produk non-active dalam satu permintaan pengurangan stok
+type ProductsNotActiveError struct {
+	Products []InactiveProduct
+}
+
+func (e *ProductsNotActiveError) Error() string {
+	parts := make([]string, 0, len(e.Products))
+	for _, p := range e.Products {
+		parts = append(parts, fmt.Sprintf("%s (%s)", p.ProductID, p.Status))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrProductNotActive, strings.Join(parts, ", "))
+}
+
+func (e *ProductsNotActiveError) Unwrap() error {
+	return ErrProductNotActive
+}
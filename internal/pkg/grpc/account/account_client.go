@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	accountpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/account"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -14,7 +15,10 @@ type AccountClient struct {
 }
 
 func NewAccountClient(grpcServerAddress string) (*AccountClient, error) {
-	conn, err := grpc.NewClient(grpcServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(grpcServerAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("can't connect to gRPC server: %v", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/labstack/gommon/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -14,13 +15,19 @@ import (
 	authpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/auth"
 )
 
+const defaultValidateTimeout = 5 * time.Second
+
 type AuthClient struct {
 	service authpb.AuthServiceClient
 	conn    *grpc.ClientConn
+	timeout time.Duration
 }
 
 func NewAuthClient(grpcServerAddress string) (*AuthClient, error) {
-	conn, err := grpc.NewClient(grpcServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(grpcServerAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("can't connect to gRPC server: %v", err)
 	}
@@ -29,13 +36,19 @@ func NewAuthClient(grpcServerAddress string) (*AuthClient, error) {
 	return &AuthClient{
 		service: serviceClient,
 		conn:    conn,
+		timeout: defaultValidateTimeout,
 	}, nil
 }
 
-func NewAuthClientFromService(serviceClient authpb.AuthServiceClient, conn *grpc.ClientConn) *AuthClient {
+func NewAuthClientFromService(serviceClient authpb.AuthServiceClient, conn *grpc.ClientConn, timeout time.Duration) *AuthClient {
+	if timeout <= 0 {
+		timeout = defaultValidateTimeout
+	}
+
 	return &AuthClient{
 		service: serviceClient,
 		conn:    conn,
+		timeout: timeout,
 	}
 }
 
@@ -49,8 +62,8 @@ func (rc *AuthClient) Close() {
 	}
 }
 
-func (c *AuthClient) ValidateToken(token string) (isValid bool, userID string, username string, role string, errorMessage string, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *AuthClient) ValidateToken(ctx context.Context, token string) (isValid bool, userID string, username string, role string, errorMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	req := &authpb.ValidateTokenRequest{Token: token}
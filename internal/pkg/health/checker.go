@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type Report struct {
+	Ready      bool              `json:"ready"`
+	Draining   bool              `json:"draining,omitempty"`
+	Components map[string]string `json:"components"`
+}
+
+// Checker menjalankan semua dependency check untuk readiness; liveness tidak memakai Checker
+type Checker struct {
+	mu       sync.RWMutex
+	checks   map[string]CheckFunc
+	timeout  time.Duration
+	draining atomic.Bool
+	log      *logrus.Logger
+}
+
+func NewChecker(timeout time.Duration, log *logrus.Logger) *Checker {
+	return &Checker{
+		checks:  map[string]CheckFunc{},
+		timeout: timeout,
+		log:     log,
+	}
+}
+
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// SetDraining membuat readiness langsung gagal supaya load balancer berhenti mengirim trafik sebelum server ditutup
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	report := Report{Ready: true, Components: make(map[string]string, len(checks))}
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+
+			status := StatusUp
+			if err := check(ctx); err != nil {
+				c.log.WithField("component", name).WithError(err).Warn("Readiness check failed")
+				status = StatusDown
+			}
+
+			mu.Lock()
+			report.Components[name] = status
+			if status != StatusUp {
+				report.Ready = false
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	if c.draining.Load() {
+		report.Ready = false
+		report.Draining = true
+	}
+
+	return report
+}
+
+// SyncGRPC memperbarui status gRPC health service secara berkala berdasarkan hasil Check
+func (c *Checker) SyncGRPC(ctx context.Context, srv *grpchealth.Server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !c.Check(ctx).Ready {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		srv.SetServingStatus("", status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GRPCConnCheck memastikan koneksi client gRPC bisa mencapai state Ready
+func GRPCConnCheck(conn *grpc.ClientConn) CheckFunc {
+	return func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Idle:
+				conn.Connect()
+			case connectivity.Shutdown:
+				return fmt.Errorf("connection is shut down")
+			}
+
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection not ready (last state %s): %w", state, ctx.Err())
+			}
+		}
+	}
+}
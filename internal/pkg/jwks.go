@@ -0,0 +1,258 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// refresh dari Key dibatasi per attempt (berhasil maupun gagal) agar token palsu atau JWKS yang sedang down
+// tidak memicu fetch terus-menerus
+const minJWKSRefreshInterval = time.Minute
+
+// errUnsupportedKey menandai key yang dilewati, bukan membuat seluruh JWKS ditolak
+var errUnsupportedKey = errors.New("unsupported key")
+
+// algoritma yang bisa diverifikasi per kty, mengikuti WithValidMethods di JWTVerifier
+var supportedJWKAlgs = map[string][]string{
+	"RSA": {"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"},
+	"EC":  {"ES256", "ES384", "ES512"},
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// KeySet menyimpan public key dari JWKS (file lokal atau URL) dan me-refresh-nya secara berkala
+type KeySet struct {
+	file            string
+	url             string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+	log             *logrus.Logger
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	lastRefresh time.Time
+	lastAttempt time.Time
+}
+
+func NewKeySet(ctx context.Context, file, url string, refreshInterval time.Duration, log *logrus.Logger) (*KeySet, error) {
+	if file == "" && url == "" {
+		return nil, fmt.Errorf("either a JWKS file or URL is required")
+	}
+
+	ks := &KeySet{
+		file:            file,
+		url:             url,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		log:             log,
+		keys:            map[string]interface{}{},
+	}
+
+	if err := ks.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) Key(kid string) (interface{}, error) {
+	ks.mu.RLock()
+	key, ok := ks.lookup(kid)
+	stale := ks.refreshInterval > 0 && time.Since(ks.lastRefresh) > ks.refreshInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	if ks.claimRefresh() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Jika refresh gagal, key lama tetap dipakai sampai attempt berikutnya
+		if err := ks.Refresh(ctx); err != nil {
+			ks.log.WithError(err).Warn("Failed to refresh JWKS, serving cached keys")
+		} else {
+			ks.mu.RLock()
+			key, ok = ks.lookup(kid)
+			ks.mu.RUnlock()
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: kid '%s'", ErrUnknownSigningKey, kid)
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	data, err := ks.read(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if errors.Is(err, errUnsupportedKey) {
+			ks.log.WithError(err).WithField("kid", jwk.Kid).Warn("Skipping unsupported key in JWKS")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("invalid key '%s' in JWKS: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS contains no supported signing key")
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.lastRefresh = time.Now()
+	ks.lastAttempt = ks.lastRefresh
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// claimRefresh mencatat attempt sebelum fetch, jadi request paralel tidak ikut menunggu fetch yang sama
+func (ks *KeySet) claimRefresh() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if time.Since(ks.lastAttempt) < minJWKSRefreshInterval {
+		return false
+	}
+
+	ks.lastAttempt = time.Now()
+	return true
+}
+
+// lookup harus dipanggil saat memegang lock. Token tanpa kid hanya diterima jika JWKS berisi satu key.
+func (ks *KeySet) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+func (ks *KeySet) read(ctx context.Context) ([]byte, error) {
+	if ks.file != "" {
+		return os.ReadFile(ks.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ks.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", res.StatusCode, ks.url)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, 1<<20))
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	if jwk.Alg != "" && !slices.Contains(supportedJWKAlgs[jwk.Kty], jwk.Alg) {
+		return nil, fmt.Errorf("%w: alg %s for key type %s", errUnsupportedKey, jwk.Alg, jwk.Kty)
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %s", errUnsupportedKey, jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("%w: key type %s", errUnsupportedKey, jwk.Kty)
+}
+
+func decodeBigInt(val string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,300 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+func discardLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...jsonWebKey) {
+	t.Helper()
+
+	data, err := json.Marshal(jsonWebKeySet{Keys: keys})
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+}
+
+func TestKeySetVerifiesRSAAndECTokens(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path,
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		jsonWebKey{
+			Kty: "EC",
+			Kid: "ec-1",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+		// Key enkripsi diabaikan
+		jsonWebKey{Kty: "RSA", Kid: "enc-1", Use: "enc", N: "AQAB", E: "AQAB"},
+	)
+
+	ks, err := NewKeySet(context.Background(), path, "", 0, discardLogger())
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+	verifier := NewJWTVerifier(JWTVerifierConfig{KeySet: ks})
+
+	if _, err := verifier.Verify(signRS256(t, rsaKey, "rsa-1", validClaims(time.Hour))); err != nil {
+		t.Fatalf("expected RSA token to verify, got %v", err)
+	}
+
+	ecToken := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims(time.Hour))
+	ecToken.Header["kid"] = "ec-1"
+	signed, err := ecToken.SignedString(ecKey)
+	if err != nil {
+		t.Fatalf("failed to sign EC token: %v", err)
+	}
+	if _, err := verifier.Verify(signed); err != nil {
+		t.Fatalf("expected EC token to verify, got %v", err)
+	}
+
+	// Token RSA yang ditandatangani key lain dengan kid yang sama harus ditolak
+	if _, err := verifier.Verify(signRS256(t, newRSAKey(t), "rsa-1", validClaims(time.Hour))); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Fatalf("expected %v, got %v", jwt.ErrTokenSignatureInvalid, err)
+	}
+
+	if _, err := ks.Key("enc-1"); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("expected encryption key to be skipped, got %v", err)
+	}
+}
+
+func TestKeySetKidlessTokenNeedsSingleKey(t *testing.T) {
+	first, second := newRSAKey(t), newRSAKey(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	writeJWKS(t, path, rsaJWK("only", &first.PublicKey))
+	ks, err := NewKeySet(context.Background(), path, "", 0, discardLogger())
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+	if _, err := ks.Key(""); err != nil {
+		t.Fatalf("expected kid-less lookup to use the only key, got %v", err)
+	}
+
+	writeJWKS(t, path, rsaJWK("a", &first.PublicKey), rsaJWK("b", &second.PublicKey))
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("failed to refresh JWKS: %v", err)
+	}
+	if _, err := ks.Key(""); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("expected kid-less lookup to fail with several keys, got %v", err)
+	}
+}
+
+func TestKeySetRefreshesUnknownKidAtMostOncePerInterval(t *testing.T) {
+	oldKey, newKey := newRSAKey(t), newRSAKey(t)
+
+	var fetches atomic.Int32
+	var keys atomic.Pointer[[]jsonWebKey]
+	keys.Store(&[]jsonWebKey{rsaJWK("old", &oldKey.PublicKey)})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(jsonWebKeySet{Keys: *keys.Load()})
+	}))
+	defer srv.Close()
+
+	ks, err := NewKeySet(context.Background(), "", srv.URL, 0, discardLogger())
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+
+	// Rotasi key di account service
+	keys.Store(&[]jsonWebKey{rsaJWK("old", &oldKey.PublicKey), rsaJWK("new", &newKey.PublicKey)})
+
+	// Refresh baru saja terjadi, jadi kid tidak dikenal tidak memicu fetch
+	if _, err := ks.Key("new"); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("expected unknown kid before refresh window, got %v", err)
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected 1 fetch, got %d", got)
+	}
+
+	ks.mu.Lock()
+	ks.lastRefresh = time.Now().Add(-2 * minJWKSRefreshInterval)
+	ks.lastAttempt = ks.lastRefresh
+	ks.mu.Unlock()
+
+	if _, err := ks.Key("new"); err != nil {
+		t.Fatalf("expected rotated key after refresh, got %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Fatalf("expected 2 fetches, got %d", got)
+	}
+
+	// Key yang sudah dikenal tidak memicu fetch
+	if _, err := ks.Key("old"); err != nil {
+		t.Fatalf("expected known key, got %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Fatalf("expected no extra fetch for known kid, got %d", got)
+	}
+}
+
+func TestKeySetKeepsOldKeysWhenRefreshFails(t *testing.T) {
+	key := newRSAKey(t)
+
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{rsaJWK("k1", &key.PublicKey)}})
+	}))
+	defer srv.Close()
+
+	ks, err := NewKeySet(context.Background(), "", srv.URL, time.Hour, discardLogger())
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+
+	failing.Store(true)
+	if err := ks.Refresh(context.Background()); err == nil {
+		t.Fatal("expected refresh to fail on non-200 response")
+	}
+
+	// Key set sudah stale dan refresh gagal: key lama tetap dipakai
+	ks.mu.Lock()
+	ks.lastRefresh = time.Now().Add(-2 * time.Hour)
+	ks.lastAttempt = ks.lastRefresh
+	ks.mu.Unlock()
+
+	before := fetches.Load()
+	if _, err := ks.Key("k1"); err != nil {
+		t.Fatalf("expected cached key when refresh fails, got %v", err)
+	}
+	if got := fetches.Load() - before; got != 1 {
+		t.Fatalf("expected 1 refresh attempt for stale keys, got %d", got)
+	}
+
+	// Attempt yang gagal tetap dicatat, jadi request berikutnya tidak fetch ulang secara sinkron
+	for i := 0; i < 5; i++ {
+		if _, err := ks.Key("k1"); err != nil {
+			t.Fatalf("expected cached key during backoff, got %v", err)
+		}
+	}
+	if _, err := ks.Key("unknown"); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("expected %v, got %v", ErrUnknownSigningKey, err)
+	}
+	if got := fetches.Load() - before; got != 1 {
+		t.Fatalf("expected no refresh during backoff, got %d attempts", got)
+	}
+}
+
+func TestKeySetSkipsUnsupportedKeys(t *testing.T) {
+	rsaKey := newRSAKey(t)
+
+	withAlg := rsaJWK("rsa-alg", &rsaKey.PublicKey)
+	withAlg.Alg = "RS256"
+	wrongAlg := rsaJWK("rsa-es", &rsaKey.PublicKey)
+	wrongAlg.Alg = "ES256"
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path,
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		withAlg,
+		wrongAlg,
+		jsonWebKey{Kty: "OKP", Kid: "ed-1", Crv: "Ed25519", X: "AQ"},
+		jsonWebKey{Kty: "EC", Kid: "ec-192", Crv: "P-192", X: "AQ", Y: "AQ"},
+	)
+
+	ks, err := NewKeySet(context.Background(), path, "", 0, discardLogger())
+	if err != nil {
+		t.Fatalf("expected unsupported keys to be skipped, got %v", err)
+	}
+
+	for _, kid := range []string{"rsa-1", "rsa-alg"} {
+		if _, err := ks.Key(kid); err != nil {
+			t.Fatalf("expected key %s to be loaded, got %v", kid, err)
+		}
+	}
+	for _, kid := range []string{"rsa-es", "ed-1", "ec-192"} {
+		if _, err := ks.Key(kid); !errors.Is(err, ErrUnknownSigningKey) {
+			t.Fatalf("expected key %s to be skipped, got %v", kid, err)
+		}
+	}
+}
+
+func TestNewKeySetErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	badJSON := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badJSON, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	badCurve := filepath.Join(dir, "curve.json")
+	writeJWKS(t, badCurve, jsonWebKey{Kty: "EC", Kid: "ec", Crv: "P-192", X: "AQ", Y: "AQ"})
+
+	badType := filepath.Join(dir, "type.json")
+	writeJWKS(t, badType, jsonWebKey{Kty: "oct", Kid: "sym"})
+
+	badBase64 := filepath.Join(dir, "base64.json")
+	writeJWKS(t, badBase64, jsonWebKey{Kty: "RSA", Kid: "rsa", N: "!!!", E: "AQAB"})
+
+	tests := []struct {
+		name string
+		file string
+		url  string
+	}{
+		{"no source", "", ""},
+		{"missing file", filepath.Join(dir, "missing.json"), ""},
+		{"invalid json", badJSON, ""},
+		{"only unsupported curve", badCurve, ""},
+		{"only unsupported key type", badType, ""},
+		{"invalid base64", badBase64, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeySet(context.Background(), tt.file, tt.url, 0, discardLogger()); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -1 +1,110 @@
 package pkg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoVerificationKey = errors.New("no key configured to verify this token")
+	ErrUnknownSigningKey = errors.New("token signed with an unknown key")
+)
+
+// Claims mengikuti payload token yang diterbitkan account service
+type Claims struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type JWTVerifierConfig struct {
+	Secret   string
+	KeySet   *KeySet
+	Issuer   string
+	Audience string
+}
+
+type JWTVerifier struct {
+	secret []byte
+	keySet *KeySet
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(cfg JWTVerifierConfig) *JWTVerifier {
+	opts := []jwt.ParserOption{
+		// keyFunc memastikan secret HMAC tidak pernah dipakai untuk algoritma asimetris, dan sebaliknya
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &JWTVerifier{
+		secret: []byte(cfg.Secret),
+		keySet: cfg.KeySet,
+		parser: jwt.NewParser(opts...),
+	}
+}
+
+// Enabled bernilai false jika tidak ada secret maupun JWKS, sehingga semua token harus divalidasi lewat gRPC
+func (v *JWTVerifier) Enabled() bool {
+	return len(v.secret) > 0 || v.keySet != nil
+}
+
+func (v *JWTVerifier) Verify(rawToken string) (*Claims, error) {
+	claims := &Claims{}
+
+	if _, err := v.parser.ParseWithClaims(rawToken, claims, v.keyFunc); err != nil {
+		return nil, err
+	}
+
+	if claims.UserID == "" {
+		claims.UserID = claims.Subject
+	}
+	if claims.UserID == "" {
+		return nil, fmt.Errorf("%w: token has no user_id or sub claim", jwt.ErrTokenInvalidClaims)
+	}
+
+	return claims, nil
+}
+
+// ParseUnverified hanya membaca claims tanpa memeriksa signature, dipakai untuk menentukan TTL cache
+func ParseUnverified(rawToken string) (*Claims, error) {
+	claims := &Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(rawToken, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(v.secret) == 0 {
+			return nil, ErrNoVerificationKey
+		}
+		return v.secret, nil
+
+	default:
+		if v.keySet == nil {
+			return nil, ErrNoVerificationKey
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.keySet.Key(kid)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+}
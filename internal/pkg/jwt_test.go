@@ -0,0 +1,217 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func signHS256(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return token
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return signed
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	return key
+}
+
+func validClaims(ttl time.Duration) *Claims {
+	return &Claims{
+		UserID:   "user-1",
+		Username: "alice",
+		Role:     "seller",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	verifier := NewJWTVerifier(JWTVerifierConfig{Secret: testSecret})
+
+	claims, err := verifier.Verify(signHS256(t, testSecret, validClaims(time.Hour)))
+	if err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Username != "alice" || claims.Role != "seller" || claims.ID != "jti-1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestVerifyUsesSubjectWhenUserIDMissing(t *testing.T) {
+	verifier := NewJWTVerifier(JWTVerifierConfig{Secret: testSecret})
+
+	c := validClaims(time.Hour)
+	c.UserID = ""
+	c.Subject = "user-from-sub"
+
+	claims, err := verifier.Verify(signHS256(t, testSecret, c))
+	if err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if claims.UserID != "user-from-sub" {
+		t.Fatalf("expected user id from sub, got %q", claims.UserID)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	verifier := NewJWTVerifier(JWTVerifierConfig{Secret: testSecret, Issuer: "account", Audience: "catalog"})
+
+	withIssuer := func(c *Claims) *Claims {
+		c.Issuer = "account"
+		c.Audience = jwt.ClaimStrings{"catalog"}
+		return c
+	}
+
+	valid := signHS256(t, testSecret, withIssuer(validClaims(time.Hour)))
+	parts := strings.Split(valid, ".")
+
+	noUser := withIssuer(validClaims(time.Hour))
+	noUser.UserID = ""
+
+	noExp := withIssuer(validClaims(time.Hour))
+	noExp.ExpiresAt = nil
+
+	wrongIssuer := validClaims(time.Hour)
+	wrongIssuer.Issuer = "someone-else"
+	wrongIssuer.Audience = jwt.ClaimStrings{"catalog"}
+
+	wrongAudience := validClaims(time.Hour)
+	wrongAudience.Issuer = "account"
+	wrongAudience.Audience = jwt.ClaimStrings{"cart"}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"tampered payload", parts[0] + "." + signHS256Payload(t, withIssuer(validClaims(time.Hour)), "admin") + "." + parts[2], jwt.ErrTokenSignatureInvalid},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + flipFirstChar(parts[2]), jwt.ErrTokenSignatureInvalid},
+		{"wrong secret", signHS256(t, "other-secret", withIssuer(validClaims(time.Hour))), jwt.ErrTokenSignatureInvalid},
+		{"expired beyond leeway", signHS256(t, testSecret, withIssuer(validClaims(-time.Minute))), jwt.ErrTokenExpired},
+		{"missing exp", signHS256(t, testSecret, noExp), jwt.ErrTokenRequiredClaimMissing},
+		{"missing user id and sub", signHS256(t, testSecret, noUser), jwt.ErrTokenInvalidClaims},
+		{"wrong issuer", signHS256(t, testSecret, wrongIssuer), jwt.ErrTokenInvalidIssuer},
+		{"wrong audience", signHS256(t, testSecret, wrongAudience), jwt.ErrTokenInvalidAudience},
+		{"RSA token without key set", signRS256(t, rsaKey, "kid-1", withIssuer(validClaims(time.Hour))), ErrNoVerificationKey},
+		{"garbage", "not-a-jwt", jwt.ErrTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsExpiryWithinLeeway(t *testing.T) {
+	verifier := NewJWTVerifier(JWTVerifierConfig{Secret: testSecret})
+
+	if _, err := verifier.Verify(signHS256(t, testSecret, validClaims(-10*time.Second))); err != nil {
+		t.Fatalf("expected token within leeway to be accepted, got %v", err)
+	}
+}
+
+// Token "none" atau HMAC yang ditandatangani dengan public key tidak boleh lolos
+func TestVerifyRejectsAlgorithmConfusion(t *testing.T) {
+	verifier := NewJWTVerifier(JWTVerifierConfig{})
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims(time.Hour)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to build unsigned token: %v", err)
+	}
+	if _, err := verifier.Verify(unsigned); err == nil {
+		t.Fatal("expected alg=none token to be rejected")
+	}
+
+	// Tanpa secret HMAC, token HS256 tidak bisa diverifikasi sama sekali
+	if _, err := verifier.Verify(signHS256(t, "", validClaims(time.Hour))); !errors.Is(err, ErrNoVerificationKey) {
+		t.Fatalf("expected %v, got %v", ErrNoVerificationKey, err)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if NewJWTVerifier(JWTVerifierConfig{}).Enabled() {
+		t.Fatal("verifier without secret or key set must be disabled")
+	}
+	if !NewJWTVerifier(JWTVerifierConfig{Secret: testSecret}).Enabled() {
+		t.Fatal("verifier with secret must be enabled")
+	}
+	if !NewJWTVerifier(JWTVerifierConfig{KeySet: &KeySet{}}).Enabled() {
+		t.Fatal("verifier with key set must be enabled")
+	}
+}
+
+func TestParseUnverified(t *testing.T) {
+	token := signHS256(t, "unknown-secret", validClaims(time.Hour))
+
+	claims, err := ParseUnverified(token)
+	if err != nil {
+		t.Fatalf("expected claims, got %v", err)
+	}
+	if claims.ID != "jti-1" || claims.ExpiresAt == nil {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := ParseUnverified("not-a-jwt"); err == nil {
+		t.Fatal("expected malformed token to fail")
+	}
+}
+
+// signHS256Payload mengembalikan segmen payload dari token dengan role yang diubah, tanpa signature yang cocok
+func signHS256Payload(t *testing.T, c *Claims, role string) string {
+	t.Helper()
+
+	c.Role = role
+	return strings.Split(signHS256(t, "attacker-secret", c), ".")[1]
+}
+
+// Karakter terakhir base64 bisa hanya berisi bit padding, jadi yang diubah karakter pertama
+func flipFirstChar(s string) string {
+	if s[0] == 'A' {
+		return "B" + s[1:]
+	}
+	return "A" + s[1:]
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "catalog"
+
+// Cache family mengikuti prefix key Redis yang dipakai product service
+const (
+	CacheFamilyProduct          = "product"
+	CacheFamilyProductsBySeller = "products_by_seller"
+	CacheFamilyProductsByName   = "products_by_name"
+	CacheFamilyProductsByType   = "products_by_type"
+	CacheFamilyAllProducts      = "all_products"
+	CacheFamilyRelatedProducts  = "related_products"
+	CacheFamilyOther            = "other"
+)
+
+// Alasan entry dikeluarkan dari cache lokal (L1)
+const (
+	LocalCacheEvictionCapacity    = "capacity"
+	LocalCacheEvictionExpired     = "expired"
+	LocalCacheEvictionInvalidated = "invalidated"
+)
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	GRPCRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handled_total",
+		Help:      "Total gRPC calls handled by method and status code.",
+	}, []string{"method", "code"})
+
+	GRPCRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "gRPC call latency by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	CacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by key family and result (hit/miss).",
+	}, []string{"family", "result"})
+
+	LocalCacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "local_cache_requests_total",
+		Help:      "In-process (L1) cache lookups by key family and result (hit/miss).",
+	}, []string{"family", "result"})
+
+	LocalCacheEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "local_cache_evictions_total",
+		Help:      "Entries removed from the in-process (L1) cache, by reason.",
+	}, []string{"reason"})
+
+	LocalCacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "local_cache_entries",
+		Help:      "Number of entries currently held in the in-process (L1) cache.",
+	})
+
+	CircuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "Circuit breaker state per dependency (0=closed, 1=open, 2=half-open).",
+	}, []string{"name"})
+
+	StockDecrementFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stock_decrement_failures_total",
+		Help:      "Products that failed a stock decrement, by reason.",
+	}, []string{"reason"})
+
+	ProductViewsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "product_views_total",
+		Help:      "Product detail views, by viewer kind (user/guest).",
+	}, []string{"viewer"})
+
+	CartSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cart_items",
+		Help:      "Number of distinct items in a cart when it is retrieved.",
+		Buckets:   []float64{0, 1, 2, 3, 5, 8, 13, 21, 34, 55},
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		GRPCRequestsTotal,
+		GRPCRequestDuration,
+		CacheRequestsTotal,
+		LocalCacheRequestsTotal,
+		LocalCacheEvictionsTotal,
+		LocalCacheEntries,
+		CircuitBreakerState,
+		StockDecrementFailuresTotal,
+		ProductViewsTotal,
+		CartSize,
+	)
+}
+
+// RegisterDBStats mengekspos sql.DB.Stats() (open/idle/in-use connection, wait count, dll)
+func RegisterDBStats(db *sql.DB, dbName string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveCache mencatat hit/miss berdasarkan family dari cache key, mis. "product:<id>" -> "product"
+func ObserveCache(cacheKey string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	CacheRequestsTotal.WithLabelValues(cacheFamily(cacheKey), result).Inc()
+}
+
+func ObserveLocalCache(cacheKey string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	LocalCacheRequestsTotal.WithLabelValues(cacheFamily(cacheKey), result).Inc()
+}
+
+func cacheFamily(cacheKey string) string {
+	family, _, _ := strings.Cut(cacheKey, ":")
+
+	switch family {
+	case CacheFamilyProduct, CacheFamilyProductsBySeller, CacheFamilyProductsByName, CacheFamilyProductsByType, CacheFamilyAllProducts, CacheFamilyRelatedProducts:
+		return family
+	}
+
+	return CacheFamilyOther
+}
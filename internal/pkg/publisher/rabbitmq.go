@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/tracing"
 	"github.com/streadway/amqp"
 )
 
@@ -41,15 +42,20 @@ func (p *RabbitMQPublisher) PublishOrderCreated(ctx context.Context, event model
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	}
+
+	// Trace-context ikut dikirim di header pesan supaya consumer bisa melanjutkan trace
+	_, span := tracing.StartAMQPPublish(ctx, OrderExchange, "", &msg)
 	err = p.channel.Publish(
 		OrderExchange, // exchange
 		"",            // routing key
 		false,         // mandatory
 		false,         // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		})
+		msg)
+	tracing.EndSpan(span, err)
 
 	if err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/configs"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/tracing"
 	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
 )
@@ -33,6 +34,7 @@ func NewRedisClient(cfg *configs.RedisConfig, log *logrus.Logger) (*RedisClient,
 		Password: "",
 		DB:       0,
 	})
+	rdb.AddHook(tracing.RedisHook{})
 
 	return &RedisClient{Client: rdb, log: log}, nil
 }
@@ -0,0 +1,117 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker membuka sirkuit setelah threshold kegagalan berturut-turut. Setelah openTimeout,
+// satu request percobaan diizinkan (half-open); jika berhasil sirkuit ditutup, jika gagal dibuka lagi.
+type CircuitBreaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(name string, threshold int, openTimeout time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	b := &CircuitBreaker{
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+	}
+	metrics.CircuitBreakerState.WithLabelValues(name).Set(float64(StateClosed))
+
+	return b
+}
+
+// Allow harus diikuti Record jika mengembalikan nil
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.setState(StateHalfOpen)
+		b.probing = true
+		return nil
+
+	case StateHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.failures = 0
+		b.probing = false
+		if b.state != StateClosed {
+			b.setState(StateClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.probing = false
+		b.openedAt = time.Now()
+		b.setState(StateOpen)
+	}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.state = state
+	metrics.CircuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+}
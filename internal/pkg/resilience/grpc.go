@@ -0,0 +1,72 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ClientPolicy struct {
+	Timeout time.Duration // per percobaan; 0 berarti mengikuti deadline pemanggil
+	Retry   RetryPolicy
+}
+
+// UnaryClientInterceptor membungkus setiap call dengan timeout, retry dan circuit breaker.
+// Hanya dipasang pada koneksi yang semua method-nya idempoten (mis. lookup ke account service).
+// Saat sirkuit terbuka call langsung gagal dengan codes.Unavailable tanpa menyentuh jaringan.
+func UnaryClientInterceptor(policy ClientPolicy, breaker *CircuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 0; ; attempt++ {
+			if err := breaker.Allow(); err != nil {
+				return status.Errorf(codes.Unavailable, "%s: %v", method, err)
+			}
+
+			err := invokeWithTimeout(ctx, policy.Timeout, method, req, reply, cc, invoker, opts...)
+			breaker.Record(!isFailure(err))
+
+			if err == nil || !isRetryable(err) || attempt >= policy.Retry.MaxRetries {
+				return err
+			}
+			if sleepErr := sleep(ctx, policy.Retry.Backoff(attempt+1)); sleepErr != nil {
+				return err
+			}
+		}
+	}
+}
+
+func invokeWithTimeout(ctx context.Context, timeout time.Duration, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// isFailure hanya menghitung error yang menandakan service bermasalah; NotFound, Unauthenticated dan
+// sejenisnya adalah jawaban valid dan tidak boleh membuka sirkuit
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	}
+
+	return false
+}
+
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+
+	return false
+}
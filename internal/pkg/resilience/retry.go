@@ -0,0 +1,39 @@
+package resilience
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff memakai exponential backoff dengan full jitter untuk percobaan ulang ke-attempt (mulai dari 1)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || (p.MaxBackoff > 0 && backoff > p.MaxBackoff) {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(backoff)) + 1)
+}
+
+// sleep menunggu d atau sampai ctx dibatalkan
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
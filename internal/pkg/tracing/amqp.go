@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// amqpHeaderCarrier menyimpan trace-context di header pesan AMQP
+type amqpHeaderCarrier amqp.Table
+
+func (c amqpHeaderCarrier) Get(key string) string {
+	if v, ok := c[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func (c amqpHeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c amqpHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// StartAMQPPublish membuat span producer dan menyisipkan trace-context ke msg.Headers
+func StartAMQPPublish(ctx context.Context, exchange, routingKey string, msg *amqp.Publishing) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, "publish "+exchange,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+		),
+	)
+
+	if msg.Headers == nil {
+		msg.Headers = amqp.Table{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, amqpHeaderCarrier(msg.Headers))
+
+	return ctx, span
+}
+
+// ExtractAMQP mengembalikan context dengan trace-context dari header pesan yang diterima consumer
+func ExtractAMQP(ctx context.Context, headers amqp.Table) context.Context {
+	if headers == nil {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, amqpHeaderCarrier(headers))
+}
+
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook membuat span untuk setiap command dan pipeline go-redis
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = Tracer().Start(ctx, "redis "+cmd.FullName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			attribute.String("db.operation.name", cmd.Name()),
+		),
+	)
+
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	recordRedisError(span, cmd.Err())
+	span.End()
+
+	return nil
+}
+
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		names = append(names, cmd.Name())
+	}
+
+	ctx, _ = Tracer().Start(ctx, "redis pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			attribute.String("db.operation.name", strings.Join(names, " ")),
+			attribute.Int("db.operation.batch.size", len(cmds)),
+		),
+	)
+
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			recordRedisError(span, err)
+			break
+		}
+	}
+	span.End()
+
+	return nil
+}
+
+// redis.Nil (key tidak ada) adalah cache miss biasa, bukan error
+func recordRedisError(span trace.Span, err error) {
+	if err == nil || err == redis.Nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/configs"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+
+	instrumentationName = "github.com/RehanAthallahAzhar/shopeezy-catalog"
+)
+
+// Init memasang tracer provider global beserta propagator W3C trace-context.
+// Fungsi yang dikembalikan harus dipanggil saat shutdown untuk flush span yang tersisa.
+func Init(ctx context.Context, cfg *configs.TracingConfig) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterNone, "":
+		exporter = nil
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter '%s'", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := NewTracerProvider(exporter, cfg.ServiceName, cfg.SampleRatio)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider dipisah dari Init supaya test bisa memakai tracetest.NewInMemoryExporter.
+// exporter nil berarti span tetap dibuat (trace ID tetap dipropagasikan) tetapi tidak diekspor.
+func NewTracerProvider(exporter sdktrace.SpanExporter, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		)),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,146 @@
+package policies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+type Action string
+
+const (
+	ActionCreateProduct    Action = "product:create"
+	ActionUpdateProduct    Action = "product:update"
+	ActionDeleteProduct    Action = "product:delete"
+	ActionAdjustStock      Action = "product:adjust_stock"
+	ActionViewDrafts       Action = "product:view_drafts"
+	ActionBanProduct       Action = "product:ban"
+	ActionManageAttributes Action = "product:manage_attributes"
+	ActionReplyReview      Action = "review:reply"
+	ActionModerateReview   Action = "review:moderate"
+	ActionAnswerQuestion   Action = "question:answer"
+	ActionModerateQuestion Action = "question:moderate"
+)
+
+const (
+	RoleAdmin       = "admin"
+	RoleSeller      = "seller"
+	RoleSellerStaff = "seller_staff"
+	RoleService     = "service" // pemanggil internal antar service (gRPC)
+)
+
+// scope menentukan terhadap resource milik siapa sebuah role boleh melakukan action
+type scope int
+
+const (
+	scopeAny      scope = iota // semua resource
+	scopeOwn                   // resource.SellerID == subject.UserID
+	scopeEmployer              // subject terdaftar sebagai staff dari resource.SellerID
+)
+
+var rolePermissions = map[string]map[Action]scope{
+	RoleAdmin: {
+		ActionCreateProduct:    scopeAny,
+		ActionUpdateProduct:    scopeAny,
+		ActionDeleteProduct:    scopeAny,
+		ActionAdjustStock:      scopeAny,
+		ActionViewDrafts:       scopeAny,
+		ActionBanProduct:       scopeAny,
+		ActionManageAttributes: scopeAny,
+		ActionReplyReview:      scopeAny,
+		ActionModerateReview:   scopeAny,
+		ActionAnswerQuestion:   scopeAny,
+		ActionModerateQuestion: scopeAny,
+	},
+	RoleSeller: {
+		ActionCreateProduct:  scopeOwn,
+		ActionUpdateProduct:  scopeOwn,
+		ActionDeleteProduct:  scopeOwn,
+		ActionAdjustStock:    scopeOwn,
+		ActionViewDrafts:     scopeOwn,
+		ActionReplyReview:    scopeOwn,
+		ActionAnswerQuestion: scopeOwn,
+	},
+	RoleSellerStaff: {
+		ActionUpdateProduct:  scopeEmployer,
+		ActionAdjustStock:    scopeEmployer,
+		ActionViewDrafts:     scopeEmployer,
+		ActionReplyReview:    scopeEmployer,
+		ActionAnswerQuestion: scopeEmployer,
+	},
+	RoleService: {
+		ActionAdjustStock: scopeAny,
+	},
+}
+
+// Subject adalah pihak yang meminta action, diambil dari sesi user atau identitas service pemanggil
+type Subject struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// Resource adalah produk (atau calon produk) yang menjadi target action
+type Resource struct {
+	SellerID uuid.UUID
+}
+
+type ProductPolicy interface {
+	Authorize(ctx context.Context, subject Subject, action Action, resource Resource) error
+}
+
+type productPolicy struct {
+	staffRepo repositories.SellerStaffRepository
+	log       *logrus.Logger
+}
+
+func NewProductPolicy(staffRepo repositories.SellerStaffRepository, log *logrus.Logger) ProductPolicy {
+	return &productPolicy{
+		staffRepo: staffRepo,
+		log:       log,
+	}
+}
+
+func (p *productPolicy) Authorize(ctx context.Context, subject Subject, action Action, resource Resource) error {
+	permissions, ok := rolePermissions[subject.Role]
+	if !ok {
+		return fmt.Errorf("%w: role '%s' cannot perform %s", apperrors.ErrActionNotPermitted, subject.Role, action)
+	}
+
+	sc, ok := permissions[action]
+	if !ok {
+		return fmt.Errorf("%w: role '%s' cannot perform %s", apperrors.ErrActionNotPermitted, subject.Role, action)
+	}
+
+	switch sc {
+	case scopeAny:
+		return nil
+
+	case scopeOwn:
+		if resource.SellerID != subject.UserID {
+			return apperrors.ErrProductNotBelongToSeller
+		}
+		return nil
+
+	case scopeEmployer:
+		isStaff, err := p.staffRepo.IsStaff(ctx, resource.SellerID, subject.UserID)
+		if err != nil {
+			return fmt.Errorf("policy: failed to resolve staff membership: %w", err)
+		}
+		if !isStaff {
+			p.log.WithFields(logrus.Fields{
+				"user_id":   subject.UserID,
+				"seller_id": resource.SellerID,
+				"action":    action,
+			}).Warn("Staff tried to act on a seller they do not belong to")
+			return apperrors.ErrProductNotBelongToSeller
+		}
+		return nil
+	}
+
+	return apperrors.ErrActionNotPermitted
+}
@@ -0,0 +1,160 @@
+package policies
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+type fakeStaffRepo struct {
+	staff map[uuid.UUID]map[uuid.UUID]bool // seller -> user
+	err   error
+	calls int
+}
+
+func (f *fakeStaffRepo) AddStaff(ctx context.Context, sellerID, userID uuid.UUID) (*db.SellerStaff, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeStaffRepo) RemoveStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (f *fakeStaffRepo) GetStaff(ctx context.Context, sellerID uuid.UUID) ([]db.SellerStaff, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeStaffRepo) IsStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+
+	return f.staff[sellerID][userID], nil
+}
+
+func newTestPolicy(repo *fakeStaffRepo) ProductPolicy {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return NewProductPolicy(repo, log)
+}
+
+func TestAuthorize(t *testing.T) {
+	seller := uuid.New()
+	otherSeller := uuid.New()
+	staff := uuid.New()
+	admin := uuid.New()
+	service := uuid.New()
+
+	repo := &fakeStaffRepo{staff: map[uuid.UUID]map[uuid.UUID]bool{seller: {staff: true}}}
+	policy := newTestPolicy(repo)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  Action
+		owner   uuid.UUID
+		wantErr error
+	}{
+		// scopeAny
+		{"admin updates any product", Subject{admin, RoleAdmin}, ActionUpdateProduct, seller, nil},
+		{"admin bans any product", Subject{admin, RoleAdmin}, ActionBanProduct, otherSeller, nil},
+		{"admin moderates reviews", Subject{admin, RoleAdmin}, ActionModerateReview, uuid.Nil, nil},
+		{"admin manages attributes", Subject{admin, RoleAdmin}, ActionManageAttributes, uuid.Nil, nil},
+		{"service adjusts stock of any seller", Subject{service, RoleService}, ActionAdjustStock, otherSeller, nil},
+
+		// scopeOwn
+		{"seller creates own product", Subject{seller, RoleSeller}, ActionCreateProduct, seller, nil},
+		{"seller updates own product", Subject{seller, RoleSeller}, ActionUpdateProduct, seller, nil},
+		{"seller deletes own product", Subject{seller, RoleSeller}, ActionDeleteProduct, seller, nil},
+		{"seller views own drafts", Subject{seller, RoleSeller}, ActionViewDrafts, seller, nil},
+		{"seller updates other seller product", Subject{seller, RoleSeller}, ActionUpdateProduct, otherSeller, apperrors.ErrProductNotBelongToSeller},
+		{"seller views other seller drafts", Subject{seller, RoleSeller}, ActionViewDrafts, otherSeller, apperrors.ErrProductNotBelongToSeller},
+
+		// scopeEmployer
+		{"staff updates employer product", Subject{staff, RoleSellerStaff}, ActionUpdateProduct, seller, nil},
+		{"staff adjusts employer stock", Subject{staff, RoleSellerStaff}, ActionAdjustStock, seller, nil},
+		{"staff answers employer questions", Subject{staff, RoleSellerStaff}, ActionAnswerQuestion, seller, nil},
+		{"staff updates non-employer product", Subject{staff, RoleSellerStaff}, ActionUpdateProduct, otherSeller, apperrors.ErrProductNotBelongToSeller},
+
+		// action tidak ada di role
+		{"seller cannot ban", Subject{seller, RoleSeller}, ActionBanProduct, seller, apperrors.ErrActionNotPermitted},
+		{"seller cannot moderate reviews", Subject{seller, RoleSeller}, ActionModerateReview, seller, apperrors.ErrActionNotPermitted},
+		{"seller cannot manage attributes", Subject{seller, RoleSeller}, ActionManageAttributes, uuid.Nil, apperrors.ErrActionNotPermitted},
+		{"staff cannot create", Subject{staff, RoleSellerStaff}, ActionCreateProduct, seller, apperrors.ErrActionNotPermitted},
+		{"staff cannot delete", Subject{staff, RoleSellerStaff}, ActionDeleteProduct, seller, apperrors.ErrActionNotPermitted},
+		{"service cannot update", Subject{service, RoleService}, ActionUpdateProduct, seller, apperrors.ErrActionNotPermitted},
+
+		// role tidak dikenal
+		{"unknown role", Subject{seller, "buyer"}, ActionUpdateProduct, seller, apperrors.ErrActionNotPermitted},
+		{"empty role", Subject{uuid.Nil, ""}, ActionViewDrafts, seller, apperrors.ErrActionNotPermitted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(context.Background(), tt.subject, tt.action, Resource{SellerID: tt.owner})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeStaffRepoError(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	repo := &fakeStaffRepo{err: repoErr}
+	policy := newTestPolicy(repo)
+
+	err := policy.Authorize(context.Background(), Subject{uuid.New(), RoleSellerStaff}, ActionUpdateProduct, Resource{SellerID: uuid.New()})
+	if !errors.Is(err, repoErr) {
+		t.Fatalf("expected wrapped repo error, got %v", err)
+	}
+	if errors.Is(err, apperrors.ErrProductNotBelongToSeller) || errors.Is(err, apperrors.ErrActionNotPermitted) {
+		t.Fatalf("repo failure must not be reported as a permission denial, got %v", err)
+	}
+}
+
+func TestAuthorizeOnlyEmployerScopeHitsStaffRepo(t *testing.T) {
+	repo := &fakeStaffRepo{}
+	policy := newTestPolicy(repo)
+	seller := uuid.New()
+
+	_ = policy.Authorize(context.Background(), Subject{seller, RoleSeller}, ActionUpdateProduct, Resource{SellerID: seller})
+	_ = policy.Authorize(context.Background(), Subject{uuid.New(), RoleAdmin}, ActionUpdateProduct, Resource{SellerID: seller})
+	_ = policy.Authorize(context.Background(), Subject{uuid.New(), "buyer"}, ActionUpdateProduct, Resource{SellerID: seller})
+	if repo.calls != 0 {
+		t.Fatalf("expected no staff lookups for own/any/unknown scopes, got %d", repo.calls)
+	}
+
+	_ = policy.Authorize(context.Background(), Subject{uuid.New(), RoleSellerStaff}, ActionUpdateProduct, Resource{SellerID: seller})
+	if repo.calls != 1 {
+		t.Fatalf("expected one staff lookup for employer scope, got %d", repo.calls)
+	}
+}
+
+// Setiap action yang diberikan ke role harus punya scope yang dikenali Authorize
+func TestRolePermissionsUseKnownScopes(t *testing.T) {
+	for role, perms := range rolePermissions {
+		for action, sc := range perms {
+			switch sc {
+			case scopeAny, scopeOwn, scopeEmployer:
+			default:
+				t.Errorf("role %s action %s has unknown scope %d", role, action, sc)
+			}
+		}
+	}
+}
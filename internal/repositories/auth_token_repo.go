@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+type AuthTokenRepository interface {
+	IsRevoked(ctx context.Context, keys ...string) (bool, error)
+	GetIdentity(ctx context.Context, tokenHash string) (*entities.Identity, error)
+	SaveIdentity(ctx context.Context, tokenHash string, identity *entities.Identity, ttl time.Duration) error
+}
+
+type authTokenRepositoryRedis struct {
+	redisClient     *customRedis.RedisClient
+	blacklistPrefix string
+	log             *logrus.Logger
+}
+
+// Blacklist diisi oleh account service saat logout/revoke, key-nya berupa prefix + jti atau prefix + token
+func NewAuthTokenRepository(redisClient *customRedis.RedisClient, blacklistPrefix string, log *logrus.Logger) AuthTokenRepository {
+	return &authTokenRepositoryRedis{
+		redisClient:     redisClient,
+		blacklistPrefix: blacklistPrefix,
+		log:             log,
+	}
+}
+
+func (r *authTokenRepositoryRedis) getIdentityKey(tokenHash string) string {
+	return fmt.Sprintf("auth_token:%s", tokenHash)
+}
+
+func (r *authTokenRepositoryRedis) IsRevoked(ctx context.Context, keys ...string) (bool, error) {
+	blacklistKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			blacklistKeys = append(blacklistKeys, r.blacklistPrefix+key)
+		}
+	}
+	if len(blacklistKeys) == 0 {
+		return false, nil
+	}
+
+	count, err := r.redisClient.Client.Exists(ctx, blacklistKeys...).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token blacklist: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *authTokenRepositoryRedis) GetIdentity(ctx context.Context, tokenHash string) (*entities.Identity, error) {
+	identityJSON, err := r.redisClient.Client.Get(ctx, r.getIdentityKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached identity: %w", err)
+	}
+
+	var identity entities.Identity
+	if err := json.Unmarshal([]byte(identityJSON), &identity); err != nil {
+		r.log.WithError(err).Warn("Failed to unmarshal cached identity, ignoring cache")
+		return nil, nil
+	}
+
+	return &identity, nil
+}
+
+func (r *authTokenRepositoryRedis) SaveIdentity(ctx context.Context, tokenHash string, identity *entities.Identity, ttl time.Duration) error {
+	identityJSON, err := json.Marshal(identity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal identity: %w", err)
+	}
+
+	if err := r.redisClient.Client.Set(ctx, r.getIdentityKey(tokenHash), identityJSON, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache identity: %w", err)
+	}
+
+	return nil
+}
@@ -11,16 +11,56 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
 	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
 )
 
+// AnyCartVersion dipakai jika klien tidak mengirim If-Match sehingga mutasi tidak dicek versinya
+const AnyCartVersion int64 = -1
+
+// maxCartUpdateAttempts membatasi percobaan ulang read-modify-write yang bentrok saat klien tidak mengirim versi
+const maxCartUpdateAttempts = 3
+
+// Setiap mutasi cart mengembalikan versi cart yang baru
 type CartRepository interface {
-	AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem) error
-	GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, error)
-	UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string) error
-	RemoveItem(ctx context.Context, userID, productID uuid.UUID) error
+	AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, expectedVersion int64) (int64, error)
+	GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, int64, error)
+	UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error)
+	RemoveItem(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error)
+	GetSellerNotes(ctx context.Context, userID uuid.UUID) (map[string]string, error)
+	// SetSellerNote menghapus catatan jika note kosong
+	SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error)
 }
 
+const (
+	cartMutationApplied = iota
+	cartMutationConflict
+	cartMutationNotFound
+)
+
+// cartMutateScript mengecek versi, mengubah satu field hash dan menaikkan versi cart dalam satu langkah atomik.
+// Value kosong berarti field dihapus; versi hanya naik jika hash benar-benar berubah.
+var cartMutateScript = redis.NewScript(`
+local version = tonumber(redis.call('GET', KEYS[2]) or '0')
+local expected = tonumber(ARGV[1])
+if expected >= 0 and version ~= expected then
+	return {1, version}
+end
+if ARGV[4] == '1' and redis.call('HEXISTS', KEYS[1], ARGV[2]) == 0 then
+	return {2, version}
+end
+local changed = 1
+if ARGV[3] == '' then
+	changed = redis.call('HDEL', KEYS[1], ARGV[2])
+else
+	redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+end
+if changed > 0 then
+	version = redis.call('INCR', KEYS[2])
+end
+return {0, version}
+`)
+
 type cartRepositoryRedis struct {
 	redisClient *customRedis.RedisClient
 	log         *logrus.Logger
@@ -37,92 +77,166 @@ func (r *cartRepositoryRedis) getCartKey(userID uuid.UUID) string {
 	return fmt.Sprintf("cart:%s", userID.String())
 }
 
-func (r *cartRepositoryRedis) AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem) error {
-	cartKey := r.getCartKey(userID)
+func (r *cartRepositoryRedis) getSellerNotesKey(userID uuid.UUID) string {
+	return fmt.Sprintf("cart_seller_notes:%s", userID.String())
+}
 
+func (r *cartRepositoryRedis) getVersionKey(userID uuid.UUID) string {
+	return fmt.Sprintf("cart_version:%s", userID.String())
+}
+
+func (r *cartRepositoryRedis) AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, expectedVersion int64) (int64, error) {
 	itemJSON, err := json.Marshal(item)
 	if err != nil {
 		r.log.WithError(err).Error("Failed to marshal basket items")
-		return fmt.Errorf("failed to process cart items: %w", err)
+		return 0, fmt.Errorf("failed to process cart items: %w", err)
 	}
 
-	if err := r.redisClient.Client.HSet(ctx, cartKey, productID.String(), itemJSON).Err(); err != nil {
+	version, err := r.mutate(ctx, r.getCartKey(userID), userID, productID.String(), string(itemJSON), false, expectedVersion)
+	if err == apperrors.ErrCartVersionConflict {
+		return version, err
+	}
+	if err != nil {
 		r.log.WithError(err).Error("Failed to save item to Redis")
-		return fmt.Errorf("failed to add item to cart: %w", err)
+		return 0, fmt.Errorf("failed to add item to cart: %w", err)
 	}
 
-	return nil
+	return version, nil
 }
 
-func (r *cartRepositoryRedis) GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, error) {
-	cartKey := r.getCartKey(userID)
-
-	itemsMapStr, err := r.redisClient.Client.HGetAll(ctx, cartKey).Result()
-	if err != nil {
+func (r *cartRepositoryRedis) GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, int64, error) {
+	// Isi cart dan versinya dibaca dalam satu MULTI supaya keduanya konsisten
+	pipe := r.redisClient.Client.TxPipeline()
+	itemsCmd := pipe.HGetAll(ctx, r.getCartKey(userID))
+	versionCmd := pipe.Get(ctx, r.getVersionKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
 		r.log.WithError(err).Error("Failed to retrieve basket from Redis")
-		return nil, fmt.Errorf("failed to retrieve cart data: %w", err)
+		return nil, 0, fmt.Errorf("failed to retrieve cart data: %w", err)
 	}
 
-	resultMap := make(map[string]models.RedisCartItem, len(itemsMapStr))
-	for productID, itemJSON := range itemsMapStr {
-		var item models.RedisCartItem
-		if err := json.Unmarshal([]byte(itemJSON), &item); err != nil {
-			r.log.WithField("product_id", productID).WithError(err).Warn("Failed to unmarshal basket item, item skipped")
-			continue
-		}
-		resultMap[productID] = item
+	version, err := versionCmd.Int64()
+	if err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("failed to retrieve cart version: %w", err)
 	}
 
-	return resultMap, nil
+	return decodeCartItems(itemsCmd.Val(), r.log), version, nil
 }
 
-func (r *cartRepositoryRedis) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string) error {
+// UpdateItem membaca item beserta versinya, mengubahnya, lalu menulis ulang dengan syarat versi belum berubah.
+// Tanpa If-Match, bentrok dengan request lain dicoba ulang; dengan If-Match bentrok langsung dikembalikan.
+func (r *cartRepositoryRedis) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error) {
 	cartKey := r.getCartKey(userID)
 	productIDStr := productID.String()
 	logger := r.log.WithFields(logrus.Fields{"cart_key": cartKey, "product_id": productIDStr})
 
-	itemJSON, err := r.redisClient.Client.HGet(ctx, cartKey, productIDStr).Result()
-	if err == redis.Nil {
-		logger.Warn("Trying to update an item that is not in the cart")
-		return fmt.Errorf("item not found in cart")
-	}
-	if err != nil {
-		logger.WithError(err).Error("Failed to retrieve HGET item from Redis")
-		return fmt.Errorf("failed to retrieve item from cart: %w", err)
+	for attempt := 1; ; attempt++ {
+		pipe := r.redisClient.Client.TxPipeline()
+		itemCmd := pipe.HGet(ctx, cartKey, productIDStr)
+		versionCmd := pipe.Get(ctx, r.getVersionKey(userID))
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			logger.WithError(err).Error("Failed to retrieve HGET item from Redis")
+			return 0, fmt.Errorf("failed to retrieve item from cart: %w", err)
+		}
+
+		currentVersion, err := versionCmd.Int64()
+		if err != nil && err != redis.Nil {
+			return 0, fmt.Errorf("failed to retrieve cart version: %w", err)
+		}
+		if expectedVersion != AnyCartVersion && currentVersion != expectedVersion {
+			return currentVersion, apperrors.ErrCartVersionConflict
+		}
+
+		itemJSON, err := itemCmd.Result()
+		if err == redis.Nil {
+			logger.Warn("Trying to update an item that is not in the cart")
+			return currentVersion, apperrors.ErrCartItemNotFound
+		}
+
+		var item models.RedisCartItem
+		if err := json.Unmarshal([]byte(itemJSON), &item); err != nil {
+			logger.WithError(err).Error("Failed to unmarshal basket items from Redis")
+			return 0, fmt.Errorf("corrupt basket data: %w", err)
+		}
+
+		item.Quantity = newQuantity
+		item.Description = newDescription
+		item.CartItemOptions = options
+		item.UpdatedAt = time.Now()
+
+		updatedItemJSON, err := json.Marshal(item)
+		if err != nil {
+			logger.WithError(err).Error("Failed to marshal updated basket items")
+			return 0, fmt.Errorf("failed to process item update: %w", err)
+		}
+
+		version, err := r.mutate(ctx, cartKey, userID, productIDStr, string(updatedItemJSON), true, currentVersion)
+		if err == apperrors.ErrCartVersionConflict && expectedVersion == AnyCartVersion && attempt < maxCartUpdateAttempts {
+			logger.WithField("attempt", attempt).Info("Cart changed during update, retrying")
+			continue
+		}
+		if err != nil {
+			return version, err
+		}
+
+		logger.Info("The quantity of items in Redis has been successfully updated.")
+		return version, nil
 	}
+}
 
-	var item models.RedisCartItem
-	if err := json.Unmarshal([]byte(itemJSON), &item); err != nil {
-		logger.WithError(err).Error("Failed to unmarshal basket items from Redis")
-		return fmt.Errorf("corrupt basket data: %w", err)
+func (r *cartRepositoryRedis) RemoveItem(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error) {
+	version, err := r.mutate(ctx, r.getCartKey(userID), userID, productID.String(), "", false, expectedVersion)
+	if err == apperrors.ErrCartVersionConflict {
+		return version, err
+	}
+	if err != nil {
+		r.log.WithError(err).Error("Failed to delete item from Redis")
+		return 0, fmt.Errorf("failed to remove item from cart: %w", err)
 	}
 
-	item.Quantity = newQuantity
-	item.Description = newDescription
-	item.UpdatedAt = time.Now()
+	return version, nil
+}
 
-	updatedItemJSON, err := json.Marshal(item)
+func (r *cartRepositoryRedis) GetSellerNotes(ctx context.Context, userID uuid.UUID) (map[string]string, error) {
+	notes, err := r.redisClient.Client.HGetAll(ctx, r.getSellerNotesKey(userID)).Result()
 	if err != nil {
-		logger.WithError(err).Error("Failed to marshal updated basket items")
-		return fmt.Errorf("failed to process item update: %w", err)
+		r.log.WithError(err).Error("Failed to retrieve seller notes from Redis")
+		return nil, fmt.Errorf("failed to retrieve seller notes: %w", err)
 	}
 
-	if err := r.redisClient.Client.HSet(ctx, cartKey, productIDStr, updatedItemJSON).Err(); err != nil {
-		logger.WithError(err).Error("Failed to update HSET item to Redis")
-		return fmt.Errorf("failed to save updates to the cart: %w", err)
+	return notes, nil
+}
+
+func (r *cartRepositoryRedis) SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error) {
+	version, err := r.mutate(ctx, r.getSellerNotesKey(userID), userID, sellerID.String(), note, false, expectedVersion)
+	if err == apperrors.ErrCartVersionConflict {
+		return version, err
+	}
+	if err != nil {
+		r.log.WithError(err).Error("Failed to save seller note to Redis")
+		return 0, fmt.Errorf("failed to save seller note: %w", err)
 	}
 
-	logger.Info("The quantity of items in Redis has been successfully updated.")
-	return nil
+	return version, nil
 }
 
-func (r *cartRepositoryRedis) RemoveItem(ctx context.Context, userID, productID uuid.UUID) error {
-	cartKey := r.getCartKey(userID)
+func (r *cartRepositoryRedis) mutate(ctx context.Context, hashKey string, userID uuid.UUID, field, value string, mustExist bool, expectedVersion int64) (int64, error) {
+	exists := "0"
+	if mustExist {
+		exists = "1"
+	}
 
-	if err := r.redisClient.Client.HDel(ctx, cartKey, productID.String()).Err(); err != nil {
-		r.log.WithError(err).Error("Failed to delete item from Redis")
-		return fmt.Errorf("failed to remove item from cart: %w", err)
+	keys := []string{hashKey, r.getVersionKey(userID)}
+	res, err := cartMutateScript.Run(ctx, r.redisClient.Client, keys, expectedVersion, field, value, exists).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+
+	switch res[0] {
+	case cartMutationConflict:
+		return res[1], apperrors.ErrCartVersionConflict
+	case cartMutationNotFound:
+		return res[1], apperrors.ErrCartItemNotFound
 	}
 
-	return nil
+	return res[1], nil
 }
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// cartCompleteField menandai hash Redis yang berisi salinan lengkap cart dari Postgres.
+// Hash tanpa field ini adalah cart lama dari mode redis-only.
+const cartCompleteField = "_complete"
+
+// cartVersionField menyimpan versi cart dari Postgres di dalam hash cache
+const cartVersionField = "_version"
+
+// Perubahan hanya ditulis ke cache jika cache sudah berisi cart lengkap dengan versi tepat sebelum perubahan ini.
+// Jika ada perubahan lain yang terlewat atau datang tidak berurutan, cache dibuang supaya dimuat ulang dari Postgres.
+// Saat cache tidak lengkap atau dibuang, versi baru tetap dicatat di _version sebagai batas bawah untuk cartFillScript,
+// supaya pembaca yang membaca Postgres sebelum perubahan ini tidak mengisi cache dengan cart lama.
+// Field kosong berarti hanya versi yang naik; value kosong berarti field dihapus.
+var cartWriteThroughScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	local fence = tonumber(redis.call('HGET', KEYS[1], ARGV[2]) or '-1')
+	if fence == nil or fence < tonumber(ARGV[3]) then
+		redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+		redis.call('PEXPIRE', KEYS[1], ARGV[6])
+	end
+	return 0
+end
+local cached = tonumber(redis.call('HGET', KEYS[1], ARGV[2]) or '-1')
+if cached ~= tonumber(ARGV[3]) - 1 then
+	redis.call('DEL', KEYS[1])
+	redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], ARGV[6])
+	return -1
+end
+if ARGV[4] ~= '' then
+	if ARGV[5] == '' then
+		redis.call('HDEL', KEYS[1], ARGV[4])
+	else
+		redis.call('HSET', KEYS[1], ARGV[4], ARGV[5])
+	end
+end
+redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+return 1
+`)
+
+// Cache hanya diisi dari Postgres jika key belum ada atau _version di dalamnya lebih lama dari versi yang dibaca,
+// sehingga pembaca lambat tidak menimpa cart yang sudah ditulis versi lebih baru. Batas versi dari writeThrough
+// dengan versi yang sama boleh ditimpa karena pembaca sudah melihat perubahan tersebut. Hash tanpa _version
+// (cart lama mode redis-only atau cache dari sebelum ada versi) selalu ditimpa.
+// ARGV: field complete, field versi, versi hasil baca, TTL dalam ms, lalu pasangan field/value isi hash.
+var cartFillScript = redis.NewScript(`
+local cached = tonumber(redis.call('HGET', KEYS[1], ARGV[2]) or '')
+local version = tonumber(ARGV[3])
+if cached ~= nil and (cached > version or (cached == version and redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1)) then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], unpack(ARGV, 5))
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return 1
+`)
+
+// cartRepositoryLayered menyimpan cart di Postgres sebagai sumber kebenaran dengan Redis sebagai cache write-through
+type cartRepositoryLayered struct {
+	store       *cartRepositoryPostgres
+	redisClient *customRedis.RedisClient
+	ttl         time.Duration
+	log         *logrus.Logger
+}
+
+func NewLayeredCartRepository(conn *sql.DB, q *db.Queries, redisClient *customRedis.RedisClient, ttl time.Duration, log *logrus.Logger) CartRepository {
+	return &cartRepositoryLayered{
+		store:       newCartRepositoryPostgres(conn, q, log),
+		redisClient: redisClient,
+		ttl:         ttl,
+		log:         log,
+	}
+}
+
+func (r *cartRepositoryLayered) getCartKey(userID uuid.UUID) string {
+	return fmt.Sprintf("cart:%s", userID.String())
+}
+
+func (r *cartRepositoryLayered) AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, expectedVersion int64) (int64, error) {
+	stored, version, err := r.store.upsertItem(ctx, userID, productID, item, expectedVersion)
+	if err != nil {
+		return version, err
+	}
+
+	r.writeThroughItem(ctx, userID, productID, stored, version)
+	return version, nil
+}
+
+func (r *cartRepositoryLayered) GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, int64, error) {
+	cartKey := r.getCartKey(userID)
+	logger := r.log.WithField("cart_key", cartKey)
+
+	cached, err := r.redisClient.Client.HGetAll(ctx, cartKey).Result()
+	if err != nil {
+		logger.WithError(err).Warn("Failed to read cart cache, reading from DB")
+		return r.store.GetAllItems(ctx, userID)
+	}
+
+	if _, complete := cached[cartCompleteField]; complete {
+		// Cache lengkap dari sebelum ada versi dianggap tidak lengkap dan dimuat ulang
+		if version, err := strconv.ParseInt(cached[cartVersionField], 10, 64); err == nil {
+			delete(cached, cartCompleteField)
+			delete(cached, cartVersionField)
+			return decodeCartItems(cached, r.log), version, nil
+		}
+	} else {
+		// Hash yang hanya berisi _version adalah batas versi dari writeThrough, bukan cart lama
+		delete(cached, cartVersionField)
+
+		// Cart lama dari mode redis-only dipindahkan ke Postgres sekali sebelum cache diisi ulang
+		if len(cached) > 0 {
+			logger.WithField("items", len(cached)).Info("Migrating legacy Redis cart to Postgres")
+			for productIDStr, item := range decodeCartItems(cached, r.log) {
+				productID, err := uuid.Parse(productIDStr)
+				if err != nil {
+					continue
+				}
+				if _, _, err := r.store.upsertItem(ctx, userID, productID, item, AnyCartVersion); err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+	}
+
+	items, version, err := r.store.GetAllItems(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.fill(ctx, cartKey, items, version)
+	return items, version, nil
+}
+
+func (r *cartRepositoryLayered) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error) {
+	stored, version, err := r.store.updateItem(ctx, userID, productID, newQuantity, newDescription, options, expectedVersion)
+	if err != nil {
+		return version, err
+	}
+
+	r.writeThroughItem(ctx, userID, productID, stored, version)
+	return version, nil
+}
+
+func (r *cartRepositoryLayered) RemoveItem(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error) {
+	version, err := r.store.RemoveItem(ctx, userID, productID, expectedVersion)
+	if err != nil {
+		return version, err
+	}
+
+	r.writeThrough(ctx, userID, productID.String(), "", version)
+	return version, nil
+}
+
+// Catatan seller jarang dibaca tanpa cart dan ukurannya kecil, jadi langsung dibaca dari Postgres tanpa cache
+func (r *cartRepositoryLayered) GetSellerNotes(ctx context.Context, userID uuid.UUID) (map[string]string, error) {
+	return r.store.GetSellerNotes(ctx, userID)
+}
+
+func (r *cartRepositoryLayered) SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error) {
+	version, err := r.store.SetSellerNote(ctx, userID, sellerID, note, expectedVersion)
+	if err != nil {
+		return version, err
+	}
+
+	// Catatan tidak di-cache, tetapi versi cart di cache tetap harus ikut naik
+	r.writeThrough(ctx, userID, "", "", version)
+	return version, nil
+}
+
+func (r *cartRepositoryLayered) writeThroughItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, version int64) {
+	itemJSON, err := json.Marshal(item)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to marshal cart item for cache")
+		r.drop(ctx, userID)
+		return
+	}
+
+	r.writeThrough(ctx, userID, productID.String(), string(itemJSON), version)
+}
+
+// writeThrough gagal secara diam-diam: Postgres sudah tersimpan, jadi cache cukup dibuang agar dimuat ulang
+func (r *cartRepositoryLayered) writeThrough(ctx context.Context, userID uuid.UUID, field, value string, version int64) {
+	keys := []string{r.getCartKey(userID)}
+	args := []interface{}{cartCompleteField, cartVersionField, version, field, value, r.ttl.Milliseconds()}
+	if err := cartWriteThroughScript.Run(ctx, r.redisClient.Client, keys, args...).Err(); err != nil && err != redis.Nil {
+		r.log.WithError(err).Warn("Failed to write cart item to cache, dropping cached cart")
+		r.drop(ctx, userID)
+	}
+}
+
+func (r *cartRepositoryLayered) fill(ctx context.Context, cartKey string, items map[string]models.RedisCartItem, version int64) {
+	args := make([]interface{}, 0, len(items)*2+8)
+	args = append(args, cartCompleteField, cartVersionField, version, r.ttl.Milliseconds(), cartCompleteField, "1", cartVersionField, version)
+	for productID, item := range items {
+		itemJSON, err := json.Marshal(item)
+		if err != nil {
+			r.log.WithError(err).Error("Failed to marshal cart item for cache")
+			return
+		}
+		args = append(args, productID, string(itemJSON))
+	}
+
+	if err := cartFillScript.Run(ctx, r.redisClient.Client, []string{cartKey}, args...).Err(); err != nil && err != redis.Nil {
+		r.log.WithError(err).Warn("Failed to fill cart cache")
+	}
+}
+
+func (r *cartRepositoryLayered) drop(ctx context.Context, userID uuid.UUID) {
+	if err := r.redisClient.Client.Del(ctx, r.getCartKey(userID)).Err(); err != nil {
+		r.log.WithError(err).Error("Failed to drop cart cache")
+	}
+}
+
+func decodeCartItems(raw map[string]string, log *logrus.Logger) map[string]models.RedisCartItem {
+	items := make(map[string]models.RedisCartItem, len(raw))
+	for productID, itemJSON := range raw {
+		var item models.RedisCartItem
+		if err := json.Unmarshal([]byte(itemJSON), &item); err != nil {
+			log.WithField("product_id", productID).WithError(err).Warn("Failed to unmarshal basket item, item skipped")
+			continue
+		}
+		items[productID] = item
+	}
+
+	return items
+}
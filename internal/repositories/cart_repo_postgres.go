@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+type cartRepositoryPostgres struct {
+	db  *sql.DB
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewPostgresCartRepository(conn *sql.DB, q *db.Queries, log *logrus.Logger) CartRepository {
+	return newCartRepositoryPostgres(conn, q, log)
+}
+
+func newCartRepositoryPostgres(conn *sql.DB, q *db.Queries, log *logrus.Logger) *cartRepositoryPostgres {
+	return &cartRepositoryPostgres{
+		db:  conn,
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *cartRepositoryPostgres) AddItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, expectedVersion int64) (int64, error) {
+	_, version, err := r.upsertItem(ctx, userID, productID, item, expectedVersion)
+	return version, err
+}
+
+func (r *cartRepositoryPostgres) GetAllItems(ctx context.Context, userID uuid.UUID) (map[string]models.RedisCartItem, int64, error) {
+	// Item dan versi dibaca dari snapshot yang sama
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve cart data: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	rows, err := q.GetCartItemsByUserID(ctx, userID)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to retrieve cart from DB")
+		return nil, 0, fmt.Errorf("failed to retrieve cart data: %w", err)
+	}
+
+	version, err := q.GetCartVersion(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.log.WithError(err).Error("Failed to retrieve cart version from DB")
+		return nil, 0, fmt.Errorf("failed to retrieve cart version: %w", err)
+	}
+
+	resultMap := make(map[string]models.RedisCartItem, len(rows))
+	for _, row := range rows {
+		resultMap[row.ProductID.String()] = toCartItemModel(row)
+	}
+
+	return resultMap, version, nil
+}
+
+func (r *cartRepositoryPostgres) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error) {
+	_, version, err := r.updateItem(ctx, userID, productID, newQuantity, newDescription, options, expectedVersion)
+	return version, err
+}
+
+func (r *cartRepositoryPostgres) RemoveItem(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error) {
+	return r.mutate(ctx, userID, expectedVersion, func(q *db.Queries) error {
+		err := q.DeleteCartItem(ctx, db.DeleteCartItemParams{
+			UserID:    userID,
+			ProductID: productID,
+		})
+		if err != nil {
+			r.log.WithError(err).Error("Failed to delete cart item from DB")
+			return fmt.Errorf("failed to remove item from cart: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *cartRepositoryPostgres) GetSellerNotes(ctx context.Context, userID uuid.UUID) (map[string]string, error) {
+	rows, err := r.q.GetCartSellerNotes(ctx, userID)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to retrieve seller notes from DB")
+		return nil, fmt.Errorf("failed to retrieve seller notes: %w", err)
+	}
+
+	notes := make(map[string]string, len(rows))
+	for _, row := range rows {
+		notes[row.SellerID.String()] = row.Note
+	}
+
+	return notes, nil
+}
+
+func (r *cartRepositoryPostgres) SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error) {
+	return r.mutate(ctx, userID, expectedVersion, func(q *db.Queries) error {
+		var err error
+		if note == "" {
+			err = q.DeleteCartSellerNote(ctx, db.DeleteCartSellerNoteParams{UserID: userID, SellerID: sellerID})
+		} else {
+			err = q.UpsertCartSellerNote(ctx, db.UpsertCartSellerNoteParams{UserID: userID, SellerID: sellerID, Note: note})
+		}
+		if err != nil {
+			r.log.WithError(err).Error("Failed to save seller note to DB")
+			return fmt.Errorf("failed to save seller note: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// upsertItem dan updateItem mengembalikan item yang tersimpan supaya layer cache bisa menulis nilai yang sama persis
+func (r *cartRepositoryPostgres) upsertItem(ctx context.Context, userID, productID uuid.UUID, item models.RedisCartItem, expectedVersion int64) (models.RedisCartItem, int64, error) {
+	addedAt := item.AddedAt
+	if addedAt.IsZero() {
+		addedAt = time.Now()
+	}
+
+	var stored models.RedisCartItem
+	version, err := r.mutate(ctx, userID, expectedVersion, func(q *db.Queries) error {
+		row, err := q.UpsertCartItem(ctx, db.UpsertCartItemParams{
+			ID:          helpers.GenerateNewID(),
+			UserID:      userID,
+			ProductID:   productID,
+			Quantity:    int32(item.Quantity),
+			Description: helpers.StringToNullString(item.Description),
+			Checked:     item.Checked,
+			GiftWrap:    item.GiftWrap,
+			GiftMessage: helpers.StringToNullString(item.GiftMessage),
+			VariantNote: helpers.StringToNullString(item.VariantNote),
+			CreatedAt:   addedAt,
+		})
+		if err != nil {
+			r.log.WithError(err).Error("Failed to save cart item to DB")
+			return fmt.Errorf("failed to add item to cart: %w", err)
+		}
+
+		stored = toCartItemModel(row)
+		return nil
+	})
+
+	return stored, version, err
+}
+
+func (r *cartRepositoryPostgres) updateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (models.RedisCartItem, int64, error) {
+	var stored models.RedisCartItem
+	version, err := r.mutate(ctx, userID, expectedVersion, func(q *db.Queries) error {
+		row, err := q.UpdateCartItem(ctx, db.UpdateCartItemParams{
+			UserID:      userID,
+			ProductID:   productID,
+			Quantity:    int32(newQuantity),
+			Description: helpers.StringToNullString(newDescription),
+			GiftWrap:    options.GiftWrap,
+			GiftMessage: helpers.StringToNullString(options.GiftMessage),
+			VariantNote: helpers.StringToNullString(options.VariantNote),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrCartItemNotFound
+		}
+		if err != nil {
+			r.log.WithError(err).Error("Failed to update cart item in DB")
+			return fmt.Errorf("failed to save updates to the cart: %w", err)
+		}
+
+		stored = toCartItemModel(row)
+		return nil
+	})
+
+	return stored, version, err
+}
+
+// mutate menjalankan fn dalam transaksi yang mengunci baris versi cart, sehingga mutasi pada cart yang sama berjalan berurutan
+func (r *cartRepositoryPostgres) mutate(ctx context.Context, userID uuid.UUID, expectedVersion int64, fn func(q *db.Queries) error) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin cart transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	if err := q.EnsureCartVersion(ctx, userID); err != nil {
+		return 0, fmt.Errorf("failed to initialize cart version: %w", err)
+	}
+
+	current, err := q.LockCartVersion(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock cart version: %w", err)
+	}
+	if expectedVersion != AnyCartVersion && current != expectedVersion {
+		return current, apperrors.ErrCartVersionConflict
+	}
+
+	if err := fn(q); err != nil {
+		return current, err
+	}
+
+	version, err := q.IncrementCartVersion(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bump cart version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cart transaction: %w", err)
+	}
+
+	return version, nil
+}
+
+func toCartItemModel(row db.Cart) models.RedisCartItem {
+	return models.RedisCartItem{
+		Quantity:    int(row.Quantity),
+		Description: row.Description.String,
+		CartItemOptions: models.CartItemOptions{
+			GiftWrap:    row.GiftWrap,
+			GiftMessage: row.GiftMessage.String,
+			VariantNote: row.VariantNote.String,
+		},
+		Checked:   row.Checked,
+		AddedAt:   row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func newTestPostgresCartRepo(t *testing.T) (*cartRepositoryPostgres, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return newCartRepositoryPostgres(conn, db.New(conn), newTestLogger()), mock
+}
+
+// expectLockedVersion mengharapkan awal transaksi mutate sampai baris versi cart terkunci
+func expectLockedVersion(mock sqlmock.Sqlmock, current int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec("-- name: EnsureCartVersion").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("-- name: LockCartVersion").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(current))
+}
+
+func TestPostgresCartMutate(t *testing.T) {
+	ctx := context.Background()
+	userID, productID := uuid.New(), uuid.New()
+
+	t.Run("matching version commits and bumps", func(t *testing.T) {
+		repo, mock := newTestPostgresCartRepo(t)
+		expectLockedVersion(mock, 3)
+		mock.ExpectExec("-- name: DeleteCartItem").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("-- name: IncrementCartVersion").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+		mock.ExpectCommit()
+
+		version, err := repo.RemoveItem(ctx, userID, productID, 3)
+		if err != nil || version != 4 {
+			t.Fatalf("expected version 4, got %d (err %v)", version, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("any version skips the check", func(t *testing.T) {
+		repo, mock := newTestPostgresCartRepo(t)
+		expectLockedVersion(mock, 10)
+		mock.ExpectExec("-- name: DeleteCartItem").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("-- name: IncrementCartVersion").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(11))
+		mock.ExpectCommit()
+
+		if version, err := repo.RemoveItem(ctx, userID, productID, AnyCartVersion); err != nil || version != 11 {
+			t.Fatalf("expected version 11, got %d (err %v)", version, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("stale version rolls back before writing", func(t *testing.T) {
+		repo, mock := newTestPostgresCartRepo(t)
+		expectLockedVersion(mock, 5)
+		mock.ExpectRollback()
+
+		version, err := repo.RemoveItem(ctx, userID, productID, 4)
+		if !errors.Is(err, apperrors.ErrCartVersionConflict) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartVersionConflict, err)
+		}
+		if version != 5 {
+			t.Fatalf("expected current version 5, got %d", version)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("missing item rolls back without bumping", func(t *testing.T) {
+		repo, mock := newTestPostgresCartRepo(t)
+		expectLockedVersion(mock, 7)
+		mock.ExpectQuery("-- name: UpdateCartItem").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		version, err := repo.UpdateItem(ctx, userID, productID, 2, "", models.CartItemOptions{}, 7)
+		if !errors.Is(err, apperrors.ErrCartItemNotFound) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartItemNotFound, err)
+		}
+		if version != 7 {
+			t.Fatalf("expected unchanged version 7, got %d", version)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("commit failure is reported", func(t *testing.T) {
+		repo, mock := newTestPostgresCartRepo(t)
+		expectLockedVersion(mock, 1)
+		mock.ExpectExec("-- name: DeleteCartItem").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("-- name: IncrementCartVersion").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+		mock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+
+		if _, err := repo.RemoveItem(ctx, userID, productID, 1); err == nil {
+			t.Fatal("expected commit error")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return mr, client
+}
+
+func newTestRedisCartRepo(client *redis.Client) *cartRepositoryRedis {
+	return NewCartRepository(&customRedis.RedisClient{Client: client}, newTestLogger()).(*cartRepositoryRedis)
+}
+
+// concurrentWriteHook mensimulasikan request lain yang mengubah cart tepat sebelum script mutasi dijalankan.
+// Script dimuat lebih dulu oleh addConcurrentWriteHook supaya satu percobaan hanya berupa satu EVALSHA.
+type concurrentWriteHook struct {
+	mr         *miniredis.Miniredis
+	versionKey string
+	remaining  int
+	fired      int
+}
+
+func (h *concurrentWriteHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if (cmd.Name() == "evalsha" || cmd.Name() == "eval") && h.remaining > 0 {
+		h.remaining--
+		h.fired++
+		if _, err := h.mr.Incr(h.versionKey, 1); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+func (h *concurrentWriteHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+func (h *concurrentWriteHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *concurrentWriteHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+func addConcurrentWriteHook(t *testing.T, mr *miniredis.Miniredis, client *redis.Client, userID uuid.UUID, times int) *concurrentWriteHook {
+	t.Helper()
+
+	if err := cartMutateScript.Load(context.Background(), client).Err(); err != nil {
+		t.Fatalf("failed to load script: %v", err)
+	}
+
+	hook := &concurrentWriteHook{mr: mr, versionKey: "cart_version:" + userID.String(), remaining: times}
+	client.AddHook(hook)
+
+	return hook
+}
+
+func runCartMutate(t *testing.T, client *redis.Client, expected int64, field, value string, mustExist bool) []int64 {
+	t.Helper()
+
+	exists := "0"
+	if mustExist {
+		exists = "1"
+	}
+
+	res, err := cartMutateScript.Run(context.Background(), client, []string{"cart:u", "cart_version:u"}, expected, field, value, exists).Int64Slice()
+	if err != nil {
+		t.Fatalf("script failed: %v", err)
+	}
+
+	return res
+}
+
+func TestCartMutateScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int64 // 0 berarti key versi belum ada
+		items       map[string]string
+		expected    int64
+		field       string
+		value       string
+		mustExist   bool
+		wantResult  int64
+		wantVersion int64
+		wantItem    string // "" berarti field tidak ada setelah script
+	}{
+		{"add to new cart without version", 0, nil, AnyCartVersion, "p1", "a", false, cartMutationApplied, 1, "a"},
+		{"add with matching version", 3, nil, 3, "p1", "a", false, cartMutationApplied, 4, "a"},
+		{"stale version conflicts", 3, map[string]string{"p1": "a"}, 2, "p1", "b", false, cartMutationConflict, 3, "a"},
+		{"expected version on empty cart", 0, nil, 0, "p1", "a", false, cartMutationApplied, 1, "a"},
+		{"update existing item", 5, map[string]string{"p1": "a"}, 5, "p1", "b", true, cartMutationApplied, 6, "b"},
+		{"update missing item", 5, map[string]string{"p2": "x"}, 5, "p1", "b", true, cartMutationNotFound, 5, ""},
+		{"conflict is checked before existence", 5, nil, 4, "p1", "b", true, cartMutationConflict, 5, ""},
+		{"remove existing item bumps version", 2, map[string]string{"p1": "a"}, AnyCartVersion, "p1", "", false, cartMutationApplied, 3, ""},
+		{"remove missing item keeps version", 2, nil, AnyCartVersion, "p1", "", false, cartMutationApplied, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			if tt.version > 0 {
+				mr.Set("cart_version:u", strconv.FormatInt(tt.version, 10))
+			}
+			for k, v := range tt.items {
+				mr.HSet("cart:u", k, v)
+			}
+
+			res := runCartMutate(t, client, tt.expected, tt.field, tt.value, tt.mustExist)
+			if res[0] != tt.wantResult || res[1] != tt.wantVersion {
+				t.Fatalf("expected {%d, %d}, got %v", tt.wantResult, tt.wantVersion, res)
+			}
+
+			got := ""
+			if mr.Exists("cart:u") {
+				got = mr.HGet("cart:u", tt.field)
+			}
+			if got != tt.wantItem {
+				t.Fatalf("expected item %q, got %q", tt.wantItem, got)
+			}
+		})
+	}
+}
+
+func seedRedisCartItem(t *testing.T, mr *miniredis.Miniredis, userID, productID uuid.UUID, quantity int, version int64) {
+	t.Helper()
+
+	itemJSON, err := json.Marshal(models.RedisCartItem{Quantity: quantity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.HSet("cart:"+userID.String(), productID.String(), string(itemJSON))
+	mr.Set("cart_version:"+userID.String(), strconv.FormatInt(version, 10))
+}
+
+func redisCartQuantity(t *testing.T, mr *miniredis.Miniredis, userID, productID uuid.UUID) int {
+	t.Helper()
+
+	var item models.RedisCartItem
+	if err := json.Unmarshal([]byte(mr.HGet("cart:"+userID.String(), productID.String())), &item); err != nil {
+		t.Fatalf("failed to decode cart item: %v", err)
+	}
+
+	return item.Quantity
+}
+
+func TestRedisCartUpdateItem(t *testing.T) {
+	ctx := context.Background()
+	userID, productID := uuid.New(), uuid.New()
+
+	t.Run("missing item", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, uuid.New(), 1, 4)
+
+		version, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 2, "", models.CartItemOptions{}, AnyCartVersion)
+		if !errors.Is(err, apperrors.ErrCartItemNotFound) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartItemNotFound, err)
+		}
+		if version != 4 {
+			t.Fatalf("expected current version 4, got %d", version)
+		}
+	})
+
+	t.Run("stale If-Match conflicts without writing", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, productID, 1, 4)
+
+		version, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 2, "", models.CartItemOptions{}, 3)
+		if !errors.Is(err, apperrors.ErrCartVersionConflict) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartVersionConflict, err)
+		}
+		if version != 4 || redisCartQuantity(t, mr, userID, productID) != 1 {
+			t.Fatalf("expected untouched cart at version 4, got version %d", version)
+		}
+	})
+
+	t.Run("matching If-Match updates", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, productID, 1, 4)
+
+		version, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 3, "note", models.CartItemOptions{GiftWrap: true}, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if version != 5 || redisCartQuantity(t, mr, userID, productID) != 3 {
+			t.Fatalf("expected quantity 3 at version 5, got version %d", version)
+		}
+	})
+
+	t.Run("concurrent change without If-Match is retried", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, productID, 1, 4)
+		hook := addConcurrentWriteHook(t, mr, client, userID, maxCartUpdateAttempts-1)
+
+		version, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 7, "", models.CartItemOptions{}, AnyCartVersion)
+		if err != nil {
+			t.Fatalf("expected update to succeed after retries, got %v", err)
+		}
+		// Versi 4 + dua perubahan konkuren + update ini
+		if version != 4+int64(maxCartUpdateAttempts-1)+1 {
+			t.Fatalf("unexpected version %d", version)
+		}
+		if hook.fired != maxCartUpdateAttempts-1 {
+			t.Fatalf("expected %d concurrent changes, got %d", maxCartUpdateAttempts-1, hook.fired)
+		}
+		if redisCartQuantity(t, mr, userID, productID) != 7 {
+			t.Fatal("expected retried update to be written")
+		}
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, productID, 1, 4)
+		hook := addConcurrentWriteHook(t, mr, client, userID, maxCartUpdateAttempts+5)
+
+		_, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 7, "", models.CartItemOptions{}, AnyCartVersion)
+		if !errors.Is(err, apperrors.ErrCartVersionConflict) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartVersionConflict, err)
+		}
+		if hook.fired != maxCartUpdateAttempts {
+			t.Fatalf("expected %d attempts, got %d", maxCartUpdateAttempts, hook.fired)
+		}
+		if redisCartQuantity(t, mr, userID, productID) != 1 {
+			t.Fatal("conflicting update must not be written")
+		}
+	})
+
+	t.Run("concurrent change with If-Match is not retried", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		seedRedisCartItem(t, mr, userID, productID, 1, 4)
+		hook := addConcurrentWriteHook(t, mr, client, userID, 1)
+
+		version, err := newTestRedisCartRepo(client).UpdateItem(ctx, userID, productID, 7, "", models.CartItemOptions{}, 4)
+		if !errors.Is(err, apperrors.ErrCartVersionConflict) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrCartVersionConflict, err)
+		}
+		if version != 5 || hook.fired != 1 {
+			t.Fatalf("expected a single attempt ending at version 5, got version %d after %d attempts", version, hook.fired)
+		}
+	})
+}
+
+func TestLayeredCartFillAndWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	key := "cart:" + userID.String()
+
+	newRepo := func(client *redis.Client) *cartRepositoryLayered {
+		return &cartRepositoryLayered{redisClient: &customRedis.RedisClient{Client: client}, ttl: time.Minute, log: newTestLogger()}
+	}
+	items := func(quantity int) map[string]models.RedisCartItem {
+		return map[string]models.RedisCartItem{"p1": {Quantity: quantity}}
+	}
+	cachedVersion := func(mr *miniredis.Miniredis) string {
+		return mr.HGet(key, cartVersionField)
+	}
+
+	t.Run("fill never replaces a newer cache", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		repo := newRepo(client)
+
+		repo.fill(ctx, key, items(2), 5)
+		repo.fill(ctx, key, items(1), 4)
+		if cachedVersion(mr) != "5" {
+			t.Fatalf("expected version 5 to stay cached, got %s", cachedVersion(mr))
+		}
+
+		repo.fill(ctx, key, items(3), 6)
+		if cachedVersion(mr) != "6" {
+			t.Fatalf("expected newer fill to replace cache, got %s", cachedVersion(mr))
+		}
+	})
+
+	t.Run("write-through on missing cache fences older fills", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		repo := newRepo(client)
+
+		// Pembaca membaca versi 4 dari Postgres, lalu writer commit versi 5 sebelum pembaca sempat mengisi cache
+		repo.writeThrough(ctx, userID, "p1", `{"quantity":9}`, 5)
+		repo.fill(ctx, key, items(1), 4)
+		if mr.HGet(key, cartCompleteField) != "" {
+			t.Fatal("stale fill must not create a complete cache")
+		}
+
+		repo.fill(ctx, key, items(9), 5)
+		if mr.HGet(key, cartCompleteField) == "" || cachedVersion(mr) != "5" {
+			t.Fatal("fill with the fenced version must populate the cache")
+		}
+	})
+
+	t.Run("out of order write-through drops cache and keeps fence", func(t *testing.T) {
+		mr, client := newTestRedis(t)
+		repo := newRepo(client)
+
+		repo.fill(ctx, key, items(1), 3)
+		repo.writeThrough(ctx, userID, "p1", `{"quantity":2}`, 6)
+		if mr.HGet(key, cartCompleteField) != "" || cachedVersion(mr) != "6" {
+			t.Fatalf("expected only a version fence after a gap, got version %s", cachedVersion(mr))
+		}
+
+		repo.fill(ctx, key, items(1), 5)
+		if mr.HGet(key, cartCompleteField) != "" {
+			t.Fatal("fill older than the fence must be skipped")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// Order yang sudah dihitung disimpan cukup lama untuk menolak event yang dikirim ulang oleh broker
+const processedOrderTTL = 7 * 24 * time.Hour
+
+// Penanda order dan kenaikan hitungan ditulis dalam satu script supaya order tidak pernah tercatat
+// sudah diproses tanpa hitungannya (atau sebaliknya) saat Redis gagal di tengah jalan.
+// KEYS: penanda order lalu key co-purchase tiap produk; ARGV: TTL penanda dalam ms, maxItems, lalu ID produk sesuai urutan KEYS[2..].
+var recordOrderScript = redis.NewScript(`
+if not redis.call('SET', KEYS[1], '1', 'NX', 'PX', ARGV[1]) then
+	return 0
+end
+local trim = -tonumber(ARGV[2]) - 1
+for i = 2, #KEYS do
+	for j = 2, #KEYS do
+		if i ~= j then
+			redis.call('ZINCRBY', KEYS[i], 1, ARGV[j + 1])
+		end
+	end
+	redis.call('ZREMRANGEBYRANK', KEYS[i], 0, trim)
+end
+return 1
+`)
+
+type CoPurchaseRepository interface {
+	// RecordOrder menaikkan hitungan co-purchase untuk setiap pasangan produk dalam satu order.
+	// Mengembalikan false tanpa mengubah hitungan jika order yang sama sudah pernah dihitung.
+	RecordOrder(ctx context.Context, orderID string, productIDs []uuid.UUID) (bool, error)
+	GetTop(ctx context.Context, productID uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+type coPurchaseRepositoryRedis struct {
+	redisClient *customRedis.RedisClient
+	maxItems    int
+	log         *logrus.Logger
+}
+
+func NewCoPurchaseRepository(redisClient *customRedis.RedisClient, maxItems int, log *logrus.Logger) CoPurchaseRepository {
+	return &coPurchaseRepositoryRedis{
+		redisClient: redisClient,
+		maxItems:    maxItems,
+		log:         log,
+	}
+}
+
+func (r *coPurchaseRepositoryRedis) getCoPurchaseKey(productID uuid.UUID) string {
+	return fmt.Sprintf("copurchase:%s", productID.String())
+}
+
+func (r *coPurchaseRepositoryRedis) getProcessedOrderKey(orderID string) string {
+	return fmt.Sprintf("copurchase_order:%s", orderID)
+}
+
+// Pasangan dengan hitungan terkecil dibuang supaya set per produk tidak tumbuh tanpa batas
+func (r *coPurchaseRepositoryRedis) RecordOrder(ctx context.Context, orderID string, productIDs []uuid.UUID) (bool, error) {
+	if len(productIDs) < 2 {
+		return false, nil
+	}
+
+	keys := make([]string, 0, len(productIDs)+1)
+	args := make([]interface{}, 0, len(productIDs)+2)
+	keys = append(keys, r.getProcessedOrderKey(orderID))
+	args = append(args, processedOrderTTL.Milliseconds(), r.maxItems)
+	for _, productID := range productIDs {
+		keys = append(keys, r.getCoPurchaseKey(productID))
+		args = append(args, productID.String())
+	}
+
+	recorded, err := recordOrderScript.Run(ctx, r.redisClient.Client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to record co-purchases: %w", err)
+	}
+
+	return recorded == 1, nil
+}
+
+func (r *coPurchaseRepositoryRedis) GetTop(ctx context.Context, productID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	members, err := r.redisClient.Client.ZRevRange(ctx, r.getCoPurchaseKey(productID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get co-purchased products: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			r.log.WithField("member", member).Warn("Invalid product ID in co-purchase set, skipped")
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+)
+
+type ProductAttributeRepository interface {
+	GetDefinitionsByType(ctx context.Context, productType string) ([]db.ProductAttributeDefinition, error)
+	UpsertDefinition(ctx context.Context, params db.UpsertAttributeDefinitionParams) (*db.ProductAttributeDefinition, error)
+	// DeleteDefinition mengembalikan false jika definisi tidak ditemukan
+	DeleteDefinition(ctx context.Context, productType, key string) (bool, error)
+}
+
+type productAttributeRepository struct {
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewProductAttributeRepository(q *db.Queries, log *logrus.Logger) ProductAttributeRepository {
+	return &productAttributeRepository{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *productAttributeRepository) GetDefinitionsByType(ctx context.Context, productType string) ([]db.ProductAttributeDefinition, error) {
+	rows, err := r.q.GetAttributeDefinitionsByType(ctx, productType)
+	if err != nil {
+		r.log.WithField("product_type", productType).WithError(err).Error("Failed to receive attribute definitions from DB")
+		return nil, fmt.Errorf("failed to get attribute definitions: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productAttributeRepository) UpsertDefinition(ctx context.Context, params db.UpsertAttributeDefinitionParams) (*db.ProductAttributeDefinition, error) {
+	row, err := r.q.UpsertAttributeDefinition(ctx, params)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"product_type": params.ProductType, "key": params.Key}).WithError(err).Error("Failed to upsert attribute definition")
+		return nil, fmt.Errorf("failed to upsert attribute definition: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *productAttributeRepository) DeleteDefinition(ctx context.Context, productType, key string) (bool, error) {
+	affected, err := r.q.DeleteAttributeDefinition(ctx, db.DeleteAttributeDefinitionParams{ProductType: productType, Key: key})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"product_type": productType, "key": key}).WithError(err).Error("Failed to delete attribute definition")
+		return false, fmt.Errorf("failed to delete attribute definition: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+)
+
+type ProductChangeRepository interface {
+	ListChangesAfter(ctx context.Context, after entities.ProductChangePosition, filter entities.ProductChangeFilter, limit int32) ([]entities.ProductChange, error)
+	GetWatermark(ctx context.Context) (int64, error)
+	DeleteChangesBefore(ctx context.Context, cutoff time.Time, limit int32) (int64, error)
+}
+
+type productChangeRepository struct {
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewProductChangeRepository(q *db.Queries, log *logrus.Logger) ProductChangeRepository {
+	return &productChangeRepository{
+		q:   q,
+		log: log,
+	}
+}
+
+// productSnapshot mengikuti jsonb_build_object di trigger record_product_change
+type productSnapshot struct {
+	ID          uuid.UUID       `json:"id"`
+	SellerID    uuid.UUID       `json:"seller_id"`
+	Name        string          `json:"name"`
+	Price       int             `json:"price"`
+	Stock       int             `json:"stock"`
+	Discount    int             `json:"discount"`
+	Type        string          `json:"type"`
+	Description string          `json:"description"`
+	ExternalSKU string          `json:"external_sku"`
+	Status      string          `json:"status"`
+	Attributes  json.RawMessage `json:"attributes"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (r *productChangeRepository) ListChangesAfter(ctx context.Context, after entities.ProductChangePosition, filter entities.ProductChangeFilter, limit int32) ([]entities.ProductChange, error) {
+	productIDs := filter.ProductIDs
+	if productIDs == nil {
+		productIDs = []uuid.UUID{}
+	}
+
+	rows, err := r.q.ListProductChangesAfter(ctx, db.ListProductChangesAfterParams{
+		AfterTxID:  after.TxID,
+		AfterID:    after.ID,
+		SellerID:   filter.SellerID,
+		ProductIds: productIDs,
+		MaxRows:    limit,
+	})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"after_tx_id": after.TxID, "after_id": after.ID}).WithError(err).Error("Failed to list product changes")
+		return nil, fmt.Errorf("failed to list product changes: %w", err)
+	}
+
+	changes := make([]entities.ProductChange, 0, len(rows))
+	for _, row := range rows {
+		var snapshot productSnapshot
+		if err := json.Unmarshal(row.Product, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode product change %d: %w", row.ID, err)
+		}
+
+		changes = append(changes, entities.ProductChange{
+			Position: entities.ProductChangePosition{TxID: row.TxID, ID: row.ID},
+			Type:     row.ChangeType,
+			Product: entities.Product{
+				ID:          snapshot.ID,
+				SellerID:    snapshot.SellerID,
+				Name:        snapshot.Name,
+				Price:       snapshot.Price,
+				Stock:       snapshot.Stock,
+				Discount:    snapshot.Discount,
+				Type:        snapshot.Type,
+				Description: snapshot.Description,
+				ExternalSKU: snapshot.ExternalSKU,
+				Status:      entities.ProductStatus(snapshot.Status),
+				Attributes:  helpers.ConvertJSONMap(reflect.ValueOf(snapshot.Attributes)),
+				CreatedAt:   snapshot.CreatedAt,
+				UpdatedAt:   snapshot.UpdatedAt,
+			},
+			OccurredAt: row.CreatedAt,
+		})
+	}
+
+	return changes, nil
+}
+
+// GetWatermark mengembalikan xmin snapshot saat ini; semua perubahan dengan tx_id di bawahnya sudah final
+func (r *productChangeRepository) GetWatermark(ctx context.Context) (int64, error) {
+	xmin, err := r.q.GetProductChangeWatermark(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get product change watermark: %w", err)
+	}
+
+	return xmin, nil
+}
+
+// DeleteChangesBefore menghapus maksimal limit baris yang lebih tua dari cutoff dan mengembalikan jumlah yang terhapus
+func (r *productChangeRepository) DeleteChangesBefore(ctx context.Context, cutoff time.Time, limit int32) (int64, error) {
+	deleted, err := r.q.DeleteExpiredProductChanges(ctx, db.DeleteExpiredProductChangesParams{
+		Cutoff:  cutoff,
+		MaxRows: limit,
+	})
+	if err != nil {
+		r.log.WithField("cutoff", cutoff).WithError(err).Error("Failed to delete expired product changes")
+		return 0, fmt.Errorf("failed to delete expired product changes: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+)
+
+func TestListChangesAfterDecodesSnapshot(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer conn.Close()
+
+	repo := NewProductChangeRepository(db.New(conn), newTestLogger())
+
+	productID, sellerID := uuid.New(), uuid.New()
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Bentuk payload mengikuti jsonb_build_object di trigger record_product_change (migration 000012)
+	withAttributes := `{"id":"` + productID.String() + `","seller_id":"` + sellerID.String() + `","name":"Laptop","price":15000000,` +
+		`"stock":3,"discount":10,"type":"laptop","description":"14 inch","external_sku":"LP-1","status":"active",` +
+		`"attributes":{"brand":"acme","ram_gb":16},"created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T03:04:05Z"}`
+	emptyAttributes := `{"id":"` + productID.String() + `","seller_id":"` + sellerID.String() + `","name":"Laptop",` +
+		`"status":"active","attributes":{},"created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T03:04:05Z"}`
+
+	mock.ExpectQuery("-- name: ListProductChangesAfter").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "product_id", "seller_id", "change_type", "product", "tx_id", "created_at"}).
+			AddRow(7, productID, sellerID, entities.ProductChangeUpdated, []byte(withAttributes), 100, occurredAt).
+			AddRow(8, productID, sellerID, entities.ProductChangeUpdated, []byte(emptyAttributes), 101, occurredAt),
+	)
+
+	changes, err := repo.ListChangesAfter(context.Background(), entities.ProductChangePosition{}, entities.ProductChangeFilter{}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(changes))
+	}
+
+	got := changes[0]
+	if got.Position != (entities.ProductChangePosition{TxID: 100, ID: 7}) || got.Type != entities.ProductChangeUpdated || !got.OccurredAt.Equal(occurredAt) {
+		t.Fatalf("unexpected change metadata: %+v", got)
+	}
+	if got.Product.ID != productID || got.Product.SellerID != sellerID || got.Product.Status != entities.ProductStatusActive || got.Product.Discount != 10 {
+		t.Fatalf("unexpected product: %+v", got.Product)
+	}
+	if got.Product.Attributes["brand"] != "acme" || got.Product.Attributes["ram_gb"] != float64(16) {
+		t.Fatalf("expected attributes from the snapshot, got %v", got.Product.Attributes)
+	}
+
+	// Sama seperti kolom JSONB di baris produk, objek kosong menjadi nil
+	if changes[1].Product.Attributes != nil {
+		t.Fatalf("expected empty attributes to decode as nil, got %v", changes[1].Product.Attributes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// Job import disimpan di Redis karena hanya dibutuhkan selama seller memantau prosesnya.
+const importJobTTL = 24 * time.Hour
+
+type ProductImportRepository interface {
+	SaveJob(ctx context.Context, job *entities.ProductImportJob) error
+	GetJob(ctx context.Context, jobID uuid.UUID) (*entities.ProductImportJob, error)
+}
+
+type productImportRepositoryRedis struct {
+	redisClient *customRedis.RedisClient
+	log         *logrus.Logger
+}
+
+func NewProductImportRepository(redisClient *customRedis.RedisClient, log *logrus.Logger) ProductImportRepository {
+	return &productImportRepositoryRedis{
+		redisClient: redisClient,
+		log:         log,
+	}
+}
+
+func (r *productImportRepositoryRedis) getJobKey(jobID uuid.UUID) string {
+	return fmt.Sprintf("product_import_job:%s", jobID.String())
+}
+
+func (r *productImportRepositoryRedis) SaveJob(ctx context.Context, job *entities.ProductImportJob) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		r.log.WithField("job_id", job.ID).WithError(err).Error("Failed to marshal import job")
+		return fmt.Errorf("failed to process import job: %w", err)
+	}
+
+	if err := r.redisClient.Client.Set(ctx, r.getJobKey(job.ID), jobJSON, importJobTTL).Err(); err != nil {
+		r.log.WithField("job_id", job.ID).WithError(err).Error("Failed to save import job to Redis")
+		return fmt.Errorf("failed to save import job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *productImportRepositoryRedis) GetJob(ctx context.Context, jobID uuid.UUID) (*entities.ProductImportJob, error) {
+	jobJSON, err := r.redisClient.Client.Get(ctx, r.getJobKey(jobID)).Result()
+	if err == redis.Nil {
+		return nil, apperrors.ErrImportJobNotFound
+	}
+	if err != nil {
+		r.log.WithField("job_id", jobID).WithError(err).Error("Failed to retrieve import job from Redis")
+		return nil, fmt.Errorf("failed to retrieve import job: %w", err)
+	}
+
+	var job entities.ProductImportJob
+	if err := json.Unmarshal([]byte(jobJSON), &job); err != nil {
+		r.log.WithField("job_id", jobID).WithError(err).Error("Failed to unmarshal import job")
+		return nil, fmt.Errorf("corrupt import job data: %w", err)
+	}
+
+	return &job, nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,15 +17,30 @@ type ProductRepository interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	CreateProduct(ctx context.Context, product *db.InsertProductParams) (*db.Product, error)
 	GetAllProducts(ctx context.Context) ([]db.GetAllProductsRow, error)
+	ListActiveProductsPage(ctx context.Context, params db.ListActiveProductsPageParams) ([]db.ListActiveProductsPageRow, error)
+	CountActiveProducts(ctx context.Context) (int64, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*db.GetProductByIDRow, error)
 	GetProductByIDs(ctx context.Context, ids []uuid.UUID) ([]db.GetProductByIDsRow, error)
 	GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]db.GetProductsBySellerIDRow, error)
 	GetProductsByName(ctx context.Context, name string) ([]db.GetProductsByNameRow, error)
 	GetProductsByType(ctx context.Context, productType string) ([]db.GetProductsByTypeRow, error)
+	GetRecentlyUpdatedProducts(ctx context.Context, limit int32) ([]db.GetRecentlyUpdatedProductsRow, error)
+	SearchProducts(ctx context.Context, params db.SearchProductsParams) ([]db.SearchProductsRow, error)
+	SearchProductsByRating(ctx context.Context, params db.SearchProductsByRatingParams) ([]db.SearchProductsByRatingRow, error)
 	UpdateProduct(ctx context.Context, updateParams *db.UpdateProductParams) (*db.Product, error)
+	// GetProductAttributesBySKU mengembalikan sql.ErrNoRows jika SKU belum pernah diimpor oleh seller tersebut
+	GetProductAttributesBySKU(ctx context.Context, sellerID uuid.UUID, externalSKU string) (json.RawMessage, error)
+	UpsertProductBySKU(ctx context.Context, params *db.UpsertProductBySKUParams) (*db.Product, error)
+	TransitionProductStatus(ctx context.Context, params db.TransitionProductStatusParams) (*db.Product, error)
 	DeleteProduct(ctx context.Context, id uuid.UUID) (*db.Product, error)
 	DecreaseProductStock(ctx context.Context, tx *sql.Tx, productID uuid.UUID, quantity int32) (*db.Product, error)
 	IncreaseProductStock(ctx context.Context, tx *sql.Tx, params db.IncreaseProductStockParams) (db.Product, error)
+	LockProductsByIDs(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) ([]db.Product, error)
+	LockProductsByFilter(ctx context.Context, tx *sql.Tx, params db.LockProductsByFilterParams) ([]db.Product, error)
+	BulkAdjustProductPrice(ctx context.Context, tx *sql.Tx, params db.BulkAdjustProductPriceParams) ([]db.Product, error)
+	BulkSetProductDiscount(ctx context.Context, tx *sql.Tx, params db.BulkSetProductDiscountParams) ([]db.Product, error)
+	BulkSetProductType(ctx context.Context, tx *sql.Tx, params db.BulkSetProductTypeParams) ([]db.Product, error)
+	BulkDeleteProducts(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) ([]db.Product, error)
 }
 
 type productRepository struct {
@@ -71,6 +87,36 @@ func (r *productRepository) GetAllProducts(ctx context.Context) ([]db.GetAllProd
 	return rows, nil
 }
 
+func (r *productRepository) ListActiveProductsPage(ctx context.Context, params db.ListActiveProductsPageParams) ([]db.ListActiveProductsPageRow, error) {
+	rows, err := r.q.ListActiveProductsPage(ctx, params)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to list products page from DB")
+		return nil, fmt.Errorf("failed to list products page: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) CountActiveProducts(ctx context.Context) (int64, error) {
+	count, err := r.q.CountActiveProducts(ctx)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to count active products")
+		return 0, fmt.Errorf("failed to count active products: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *productRepository) GetRecentlyUpdatedProducts(ctx context.Context, limit int32) ([]db.GetRecentlyUpdatedProductsRow, error) {
+	rows, err := r.q.GetRecentlyUpdatedProducts(ctx, limit)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to get recently updated products from DB")
+		return nil, err
+	}
+
+	return rows, nil
+}
+
 func (r *productRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*db.GetProductByIDRow, error) {
 	var row db.GetProductByIDRow
 
@@ -145,6 +191,26 @@ func (r *productRepository) GetProductsByType(ctx context.Context, productType s
 	return rows, nil
 }
 
+func (r *productRepository) SearchProducts(ctx context.Context, params db.SearchProductsParams) ([]db.SearchProductsRow, error) {
+	rows, err := r.q.SearchProducts(ctx, params)
+	if err != nil {
+		r.log.WithField("attribute_filter", params.AttributeFilter).WithError(err).Error("Failed to search products")
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) SearchProductsByRating(ctx context.Context, params db.SearchProductsByRatingParams) ([]db.SearchProductsByRatingRow, error) {
+	rows, err := r.q.SearchProductsByRating(ctx, params)
+	if err != nil {
+		r.log.WithField("attribute_filter", params.AttributeFilter).WithError(err).Error("Failed to search products by rating")
+		return nil, fmt.Errorf("failed to search products by rating: %w", err)
+	}
+
+	return rows, nil
+}
+
 func (r *productRepository) UpdateProduct(ctx context.Context, updateParams *db.UpdateProductParams) (*db.Product, error) {
 	var row db.Product
 
@@ -158,6 +224,43 @@ func (r *productRepository) UpdateProduct(ctx context.Context, updateParams *db.
 	return &row, nil
 }
 
+func (r *productRepository) GetProductAttributesBySKU(ctx context.Context, sellerID uuid.UUID, externalSKU string) (json.RawMessage, error) {
+	attributes, err := r.q.GetProductAttributesBySKU(ctx, db.GetProductAttributesBySKUParams{
+		SellerID:    sellerID,
+		ExternalSku: sql.NullString{String: externalSKU, Valid: true},
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.log.WithFields(logrus.Fields{"seller_id": sellerID, "external_sku": externalSKU}).WithError(err).Error("Failed to get product attributes by SKU")
+		}
+		return nil, err
+	}
+
+	return attributes, nil
+}
+
+func (r *productRepository) UpsertProductBySKU(ctx context.Context, params *db.UpsertProductBySKUParams) (*db.Product, error) {
+	row, err := r.q.UpsertProductBySKU(ctx, *params)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"seller_id": params.SellerID, "external_sku": params.ExternalSku.String}).WithError(err).Error("Failed to upsert product in the database")
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (r *productRepository) TransitionProductStatus(ctx context.Context, params db.TransitionProductStatusParams) (*db.Product, error) {
+	row, err := r.q.TransitionProductStatus(ctx, params)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.log.WithFields(logrus.Fields{"product_id": params.ID, "status": params.ToStatus}).WithError(err).Error("Failed to update product status in the database")
+		}
+		return nil, err
+	}
+
+	return &row, nil
+}
+
 func (r *productRepository) DeleteProduct(ctx context.Context, id uuid.UUID) (*db.Product, error) {
 	var row db.Product
 
@@ -196,3 +299,59 @@ func (r *productRepository) IncreaseProductStock(ctx context.Context, tx *sql.Tx
 	}
 	return updatedProduct, nil
 }
+
+func (r *productRepository) LockProductsByIDs(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).LockProductsByIDs(ctx, ids)
+	if err != nil {
+		r.log.WithField("product_ids", ids).WithError(err).Error("Failed to lock products by IDs")
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) LockProductsByFilter(ctx context.Context, tx *sql.Tx, params db.LockProductsByFilterParams) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).LockProductsByFilter(ctx, params)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"seller_id": params.SellerID, "type": params.ProductType}).WithError(err).Error("Failed to lock products by filter")
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) BulkAdjustProductPrice(ctx context.Context, tx *sql.Tx, params db.BulkAdjustProductPriceParams) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).BulkAdjustProductPrice(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjust product prices: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) BulkSetProductDiscount(ctx context.Context, tx *sql.Tx, params db.BulkSetProductDiscountParams) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).BulkSetProductDiscount(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set product discounts: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) BulkSetProductType(ctx context.Context, tx *sql.Tx, params db.BulkSetProductTypeParams) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).BulkSetProductType(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set product types: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *productRepository) BulkDeleteProducts(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) ([]db.Product, error) {
+	rows, err := r.q.WithTx(tx).BulkDeleteProducts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete products: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+type QuestionRepository interface {
+	CreateQuestion(ctx context.Context, params db.InsertProductQuestionParams) (*db.ProductQuestion, error)
+	GetQuestion(ctx context.Context, id uuid.UUID) (*db.ProductQuestion, error)
+	GetQuestionsByProductID(ctx context.Context, params db.GetProductQuestionsByProductIDParams) ([]db.ProductQuestion, error)
+	GetUnansweredBySellerID(ctx context.Context, params db.GetUnansweredQuestionsBySellerIDParams) ([]db.ProductQuestion, error)
+	GetFlagged(ctx context.Context, params db.GetFlaggedProductQuestionsParams) ([]db.ProductQuestion, error)
+	Answer(ctx context.Context, id, answeredBy uuid.UUID, answer string) (*db.ProductQuestion, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string) (*db.ProductQuestion, error)
+	// Upvote, RemoveUpvote dan Flag mengembalikan false jika user sudah (atau belum) melakukannya sebelumnya
+	Upvote(ctx context.Context, questionID, userID uuid.UUID) (bool, error)
+	RemoveUpvote(ctx context.Context, questionID, userID uuid.UUID) (bool, error)
+	Flag(ctx context.Context, questionID, userID uuid.UUID, reason string) (bool, error)
+}
+
+type questionRepository struct {
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewQuestionRepository(q *db.Queries, log *logrus.Logger) QuestionRepository {
+	return &questionRepository{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *questionRepository) CreateQuestion(ctx context.Context, params db.InsertProductQuestionParams) (*db.ProductQuestion, error) {
+	row, err := r.q.InsertProductQuestion(ctx, params)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"product_id": params.ProductID, "user_id": params.UserID}).WithError(err).Error("Failed to create product question")
+		return nil, fmt.Errorf("failed to create product question: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *questionRepository) GetQuestion(ctx context.Context, id uuid.UUID) (*db.ProductQuestion, error) {
+	row, err := r.q.GetProductQuestionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrQuestionNotFound
+		}
+		return nil, fmt.Errorf("failed to get product question: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *questionRepository) GetQuestionsByProductID(ctx context.Context, params db.GetProductQuestionsByProductIDParams) ([]db.ProductQuestion, error) {
+	rows, err := r.q.GetProductQuestionsByProductID(ctx, params)
+	if err != nil {
+		r.log.WithField("product_id", params.ProductID).WithError(err).Error("Failed to receive product questions from DB")
+		return nil, fmt.Errorf("failed to get product questions: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *questionRepository) GetUnansweredBySellerID(ctx context.Context, params db.GetUnansweredQuestionsBySellerIDParams) ([]db.ProductQuestion, error) {
+	rows, err := r.q.GetUnansweredQuestionsBySellerID(ctx, params)
+	if err != nil {
+		r.log.WithField("seller_id", params.SellerID).WithError(err).Error("Failed to receive unanswered questions from DB")
+		return nil, fmt.Errorf("failed to get unanswered questions: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *questionRepository) GetFlagged(ctx context.Context, params db.GetFlaggedProductQuestionsParams) ([]db.ProductQuestion, error) {
+	rows, err := r.q.GetFlaggedProductQuestions(ctx, params)
+	if err != nil {
+		r.log.WithError(err).Error("Failed to receive flagged questions from DB")
+		return nil, fmt.Errorf("failed to get flagged questions: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *questionRepository) Answer(ctx context.Context, id, answeredBy uuid.UUID, answer string) (*db.ProductQuestion, error) {
+	row, err := r.q.AnswerProductQuestion(ctx, db.AnswerProductQuestionParams{
+		ID:         id,
+		Answer:     sql.NullString{String: answer, Valid: true},
+		AnsweredBy: uuid.NullUUID{UUID: answeredBy, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrQuestionNotFound
+		}
+		return nil, fmt.Errorf("failed to answer product question: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *questionRepository) SetStatus(ctx context.Context, id uuid.UUID, status string) (*db.ProductQuestion, error) {
+	row, err := r.q.SetProductQuestionStatus(ctx, db.SetProductQuestionStatusParams{ID: id, Status: status})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrQuestionNotFound
+		}
+		return nil, fmt.Errorf("failed to update product question status: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *questionRepository) Upvote(ctx context.Context, questionID, userID uuid.UUID) (bool, error) {
+	affected, err := r.q.UpvoteProductQuestion(ctx, db.UpvoteProductQuestionParams{QuestionID: questionID, UserID: userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to upvote product question: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *questionRepository) RemoveUpvote(ctx context.Context, questionID, userID uuid.UUID) (bool, error) {
+	affected, err := r.q.RemoveProductQuestionUpvote(ctx, db.RemoveProductQuestionUpvoteParams{QuestionID: questionID, UserID: userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove product question upvote: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *questionRepository) Flag(ctx context.Context, questionID, userID uuid.UUID, reason string) (bool, error) {
+	affected, err := r.q.FlagProductQuestion(ctx, db.FlagProductQuestionParams{
+		QuestionID: questionID,
+		UserID:     userID,
+		Reason:     sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to flag product question: %w", err)
+	}
+
+	return affected > 0, nil
+}
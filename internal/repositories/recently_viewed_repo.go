@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// productViewCountKey menyimpan total view per produk sebagai sinyal popularitas
+const productViewCountKey = "product_views"
+
+type RecentlyViewedRepository interface {
+	RecordView(ctx context.Context, viewer entities.Viewer, productID uuid.UUID, viewedAt time.Time) error
+	GetRecent(ctx context.Context, viewer entities.Viewer, limit int) ([]uuid.UUID, error)
+}
+
+type recentlyViewedRepositoryRedis struct {
+	redisClient *customRedis.RedisClient
+	limit       int
+	ttl         time.Duration
+	guestTTL    time.Duration
+	log         *logrus.Logger
+}
+
+func NewRecentlyViewedRepository(redisClient *customRedis.RedisClient, limit int, ttl, guestTTL time.Duration, log *logrus.Logger) RecentlyViewedRepository {
+	return &recentlyViewedRepositoryRedis{
+		redisClient: redisClient,
+		limit:       limit,
+		ttl:         ttl,
+		guestTTL:    guestTTL,
+		log:         log,
+	}
+}
+
+func (r *recentlyViewedRepositoryRedis) getRecentlyViewedKey(viewer entities.Viewer) string {
+	if viewer.IsGuest() {
+		return fmt.Sprintf("recently_viewed:guest:%s", viewer.GuestToken)
+	}
+	return fmt.Sprintf("recently_viewed:user:%s", viewer.UserID)
+}
+
+// RecordView menyimpan produk di sorted set dengan skor waktu lihat; produk yang sama hanya diperbarui waktunya
+// dan entry tertua dipangkas supaya ukuran set tidak melebihi limit
+func (r *recentlyViewedRepositoryRedis) RecordView(ctx context.Context, viewer entities.Viewer, productID uuid.UUID, viewedAt time.Time) error {
+	key := r.getRecentlyViewedKey(viewer)
+	ttl := r.ttl
+	if viewer.IsGuest() {
+		ttl = r.guestTTL
+	}
+
+	pipe := r.redisClient.Client.Pipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(viewedAt.UnixMilli()), Member: productID.String()})
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-r.limit-1))
+	pipe.Expire(ctx, key, ttl)
+	pipe.ZIncrBy(ctx, productViewCountKey, 1, productID.String())
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record product view: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent mengembalikan ID produk dari yang paling baru dilihat
+func (r *recentlyViewedRepositoryRedis) GetRecent(ctx context.Context, viewer entities.Viewer, limit int) ([]uuid.UUID, error) {
+	members, err := r.redisClient.Client.ZRevRange(ctx, r.getRecentlyViewedKey(viewer), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently viewed products: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			r.log.WithField("member", member).Warn("Invalid product ID in recently viewed set, skipped")
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
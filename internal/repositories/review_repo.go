@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, params db.InsertProductReviewParams) (*db.ProductReview, error)
+	GetReview(ctx context.Context, id uuid.UUID) (*db.ProductReview, error)
+	GetApprovedReviews(ctx context.Context, productID uuid.UUID, limit, offset int32) ([]db.ProductReview, error)
+	GetReviewsByStatus(ctx context.Context, status string, limit, offset int32) ([]db.ProductReview, error)
+	// UpdateReview, SetStatus dan DeleteReview menghitung ulang agregat rating produk dalam transaksi yang sama
+	UpdateReview(ctx context.Context, params db.UpdateProductReviewParams) (*db.ProductReview, *db.Product, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string) (*db.ProductReview, *db.Product, error)
+	DeleteReview(ctx context.Context, id uuid.UUID) (*db.Product, error)
+	SetReply(ctx context.Context, id uuid.UUID, reply string) (*db.ProductReview, error)
+	HasPurchased(ctx context.Context, userID, productID uuid.UUID) (bool, error)
+	// RecordPurchases mencatat pembelian dan menandai review yang sudah ada sebagai verified purchase
+	RecordPurchases(ctx context.Context, userID uuid.UUID, orderID string, productIDs []uuid.UUID, purchasedAt time.Time) error
+}
+
+type reviewRepository struct {
+	db  *sql.DB
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewReviewRepository(conn *sql.DB, q *db.Queries, log *logrus.Logger) ReviewRepository {
+	return &reviewRepository{
+		db:  conn,
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, params db.InsertProductReviewParams) (*db.ProductReview, error) {
+	row, err := r.q.InsertProductReview(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrReviewAlreadyExists
+		}
+		r.log.WithFields(logrus.Fields{"product_id": params.ProductID, "user_id": params.UserID}).WithError(err).Error("Failed to create product review")
+		return nil, fmt.Errorf("failed to create product review: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *reviewRepository) GetReview(ctx context.Context, id uuid.UUID) (*db.ProductReview, error) {
+	row, err := r.q.GetProductReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get product review: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *reviewRepository) GetApprovedReviews(ctx context.Context, productID uuid.UUID, limit, offset int32) ([]db.ProductReview, error) {
+	rows, err := r.q.GetProductReviewsByProductID(ctx, db.GetProductReviewsByProductIDParams{ProductID: productID, Limit: limit, Offset: offset})
+	if err != nil {
+		r.log.WithField("product_id", productID).WithError(err).Error("Failed to receive product reviews from DB")
+		return nil, fmt.Errorf("failed to get product reviews: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *reviewRepository) GetReviewsByStatus(ctx context.Context, status string, limit, offset int32) ([]db.ProductReview, error) {
+	rows, err := r.q.GetProductReviewsByStatus(ctx, db.GetProductReviewsByStatusParams{Status: status, Limit: limit, Offset: offset})
+	if err != nil {
+		r.log.WithField("status", status).WithError(err).Error("Failed to receive product reviews from DB")
+		return nil, fmt.Errorf("failed to get product reviews: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *reviewRepository) UpdateReview(ctx context.Context, params db.UpdateProductReviewParams) (*db.ProductReview, *db.Product, error) {
+	var review db.ProductReview
+	product, err := r.withRatingRefresh(ctx, params.ID, func(q *db.Queries) (uuid.UUID, error) {
+		row, err := q.UpdateProductReview(ctx, params)
+		review = row
+		return row.ProductID, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &review, product, nil
+}
+
+func (r *reviewRepository) SetStatus(ctx context.Context, id uuid.UUID, status string) (*db.ProductReview, *db.Product, error) {
+	var review db.ProductReview
+	product, err := r.withRatingRefresh(ctx, id, func(q *db.Queries) (uuid.UUID, error) {
+		row, err := q.SetProductReviewStatus(ctx, db.SetProductReviewStatusParams{ID: id, Status: status})
+		review = row
+		return row.ProductID, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &review, product, nil
+}
+
+func (r *reviewRepository) DeleteReview(ctx context.Context, id uuid.UUID) (*db.Product, error) {
+	return r.withRatingRefresh(ctx, id, func(q *db.Queries) (uuid.UUID, error) {
+		review, err := q.LockProductReviewByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		_, err = q.DeleteProductReview(ctx, id)
+		return review.ProductID, err
+	})
+}
+
+func (r *reviewRepository) SetReply(ctx context.Context, id uuid.UUID, reply string) (*db.ProductReview, error) {
+	row, err := r.q.SetProductReviewReply(ctx, db.SetProductReviewReplyParams{
+		ID:          id,
+		SellerReply: sql.NullString{String: reply, Valid: reply != ""},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to save seller reply: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *reviewRepository) HasPurchased(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
+	purchased, err := r.q.HasUserPurchased(ctx, db.HasUserPurchasedParams{UserID: userID, ProductID: productID})
+	if err != nil {
+		return false, fmt.Errorf("failed to check user purchase: %w", err)
+	}
+
+	return purchased, nil
+}
+
+func (r *reviewRepository) RecordPurchases(ctx context.Context, userID uuid.UUID, orderID string, productIDs []uuid.UUID, purchasedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin purchase transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	for _, productID := range productIDs {
+		params := db.InsertUserPurchaseParams{UserID: userID, ProductID: productID, OrderID: orderID, PurchasedAt: purchasedAt}
+		if err := q.InsertUserPurchase(ctx, params); err != nil {
+			return fmt.Errorf("failed to record purchase of product %s: %w", productID, err)
+		}
+		if _, err := q.MarkProductReviewVerified(ctx, db.MarkProductReviewVerifiedParams{UserID: userID, ProductID: productID}); err != nil {
+			return fmt.Errorf("failed to mark review of product %s as verified: %w", productID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purchase transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withRatingRefresh menjalankan fn lalu menghitung ulang rating_avg dan rating_count produk milik review tersebut
+func (r *reviewRepository) withRatingRefresh(ctx context.Context, reviewID uuid.UUID, fn func(q *db.Queries) (uuid.UUID, error)) (*db.Product, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin review transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	productID, err := fn(q)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrReviewNotFound
+		}
+		r.log.WithField("review_id", reviewID).WithError(err).Error("Failed to modify product review")
+		return nil, fmt.Errorf("failed to modify product review: %w", err)
+	}
+
+	// Baris produk dikunci dulu supaya statement berikutnya melihat review dari transaksi paralel yang sudah commit
+	if _, err := q.LockProductsByIDs(ctx, []uuid.UUID{productID}); err != nil {
+		return nil, fmt.Errorf("failed to lock product for rating refresh: %w", err)
+	}
+
+	// Produk yang sudah dihapus tidak punya agregat untuk diperbarui
+	product, err := q.RefreshProductRating(ctx, productID)
+	productGone := errors.Is(err, sql.ErrNoRows)
+	if err != nil && !productGone {
+		return nil, fmt.Errorf("failed to refresh product rating: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit review transaction: %w", err)
+	}
+
+	if productGone {
+		return nil, nil
+	}
+
+	return &product, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// Nama seller jarang berubah, jadi cukup di-cache lama supaya cart tetap punya nama saat account service down
+const sellerNameTTL = 24 * time.Hour
+
+type SellerNameRepository interface {
+	GetNames(ctx context.Context, sellerIDs []string) (map[string]string, error)
+	SaveNames(ctx context.Context, names map[string]string) error
+}
+
+type sellerNameRepositoryRedis struct {
+	redisClient *customRedis.RedisClient
+	log         *logrus.Logger
+}
+
+func NewSellerNameRepository(redisClient *customRedis.RedisClient, log *logrus.Logger) SellerNameRepository {
+	return &sellerNameRepositoryRedis{
+		redisClient: redisClient,
+		log:         log,
+	}
+}
+
+func (r *sellerNameRepositoryRedis) getSellerNameKey(sellerID string) string {
+	return fmt.Sprintf("seller_name:%s", sellerID)
+}
+
+// GetNames hanya mengembalikan seller yang namanya ada di cache
+func (r *sellerNameRepositoryRedis) GetNames(ctx context.Context, sellerIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(sellerIDs))
+	if len(sellerIDs) == 0 {
+		return names, nil
+	}
+
+	keys := make([]string, len(sellerIDs))
+	for i, id := range sellerIDs {
+		keys[i] = r.getSellerNameKey(id)
+	}
+
+	values, err := r.redisClient.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return names, fmt.Errorf("failed to get cached seller names: %w", err)
+	}
+
+	for i, value := range values {
+		if name, ok := value.(string); ok {
+			names[sellerIDs[i]] = name
+		}
+	}
+
+	return names, nil
+}
+
+func (r *sellerNameRepositoryRedis) SaveNames(ctx context.Context, names map[string]string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	pipe := r.redisClient.Client.Pipeline()
+	for id, name := range names {
+		pipe.Set(ctx, r.getSellerNameKey(id), name, sellerNameTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to cache seller names: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+)
+
+type SellerStaffRepository interface {
+	AddStaff(ctx context.Context, sellerID, userID uuid.UUID) (*db.SellerStaff, error)
+	RemoveStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error)
+	GetStaff(ctx context.Context, sellerID uuid.UUID) ([]db.SellerStaff, error)
+	IsStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error)
+}
+
+type sellerStaffRepository struct {
+	q   *db.Queries
+	log *logrus.Logger
+}
+
+func NewSellerStaffRepository(q *db.Queries, log *logrus.Logger) SellerStaffRepository {
+	return &sellerStaffRepository{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *sellerStaffRepository) AddStaff(ctx context.Context, sellerID, userID uuid.UUID) (*db.SellerStaff, error) {
+	row, err := r.q.AddSellerStaff(ctx, db.AddSellerStaffParams{SellerID: sellerID, UserID: userID})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"seller_id": sellerID, "user_id": userID}).WithError(err).Error("Failed to add seller staff")
+		return nil, fmt.Errorf("failed to add seller staff: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *sellerStaffRepository) RemoveStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error) {
+	affected, err := r.q.RemoveSellerStaff(ctx, db.RemoveSellerStaffParams{SellerID: sellerID, UserID: userID})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"seller_id": sellerID, "user_id": userID}).WithError(err).Error("Failed to remove seller staff")
+		return false, fmt.Errorf("failed to remove seller staff: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *sellerStaffRepository) GetStaff(ctx context.Context, sellerID uuid.UUID) ([]db.SellerStaff, error) {
+	rows, err := r.q.GetSellerStaff(ctx, sellerID)
+	if err != nil {
+		r.log.WithField("seller_id", sellerID).WithError(err).Error("Failed to receive seller staff from DB")
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *sellerStaffRepository) IsStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error) {
+	isStaff, err := r.q.IsSellerStaff(ctx, db.IsSellerStaffParams{SellerID: sellerID, UserID: userID})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"seller_id": sellerID, "user_id": userID}).WithError(err).Error("Failed to check seller staff membership")
+		return false, fmt.Errorf("failed to check seller staff membership: %w", err)
+	}
+
+	return isStaff, nil
+}
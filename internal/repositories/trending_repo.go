@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+)
+
+// TrendingBucketSize adalah lebar bucket sinyal mentah; skor trending dihitung dari gabungan bucket ini
+const TrendingBucketSize = time.Hour
+
+const trendingTypesKey = "trending:types"
+
+type TrendingRepository interface {
+	RecordSignal(ctx context.Context, productID uuid.UUID, productType string, weight float64, at time.Time) error
+	// Compact menggabungkan bucket dengan bobot masing-masing ke skor window; productType kosong berarti global
+	Compact(ctx context.Context, window, productType string, buckets []time.Time, weights []float64) error
+	GetTop(ctx context.Context, window, productType string, limit int) ([]entities.TrendingScore, error)
+	GetTypes(ctx context.Context) ([]string, error)
+	HasType(ctx context.Context, productType string) (bool, error)
+}
+
+type trendingRepositoryRedis struct {
+	redisClient  *customRedis.RedisClient
+	retention    time.Duration
+	aggregateTTL time.Duration
+	maxItems     int
+	log          *logrus.Logger
+}
+
+// retention harus sepanjang window terpanjang; aggregateTTL membuat skor kedaluwarsa jika job compaction berhenti
+func NewTrendingRepository(redisClient *customRedis.RedisClient, retention, aggregateTTL time.Duration, maxItems int, log *logrus.Logger) TrendingRepository {
+	return &trendingRepositoryRedis{
+		redisClient:  redisClient,
+		retention:    retention,
+		aggregateTTL: aggregateTTL,
+		maxItems:     maxItems,
+		log:          log,
+	}
+}
+
+func (r *trendingRepositoryRedis) getBucketKey(bucket time.Time, productType string) string {
+	if productType == "" {
+		return fmt.Sprintf("trending:bucket:%d:all", bucket.Unix())
+	}
+	return fmt.Sprintf("trending:bucket:%d:type:%s", bucket.Unix(), productType)
+}
+
+func (r *trendingRepositoryRedis) getScoreKey(window, productType string) string {
+	if productType == "" {
+		return fmt.Sprintf("trending:%s:all", window)
+	}
+	return fmt.Sprintf("trending:%s:type:%s", window, productType)
+}
+
+func (r *trendingRepositoryRedis) RecordSignal(ctx context.Context, productID uuid.UUID, productType string, weight float64, at time.Time) error {
+	bucket := at.Truncate(TrendingBucketSize)
+	member := productID.String()
+
+	pipe := r.redisClient.Client.Pipeline()
+	globalKey := r.getBucketKey(bucket, "")
+	pipe.ZIncrBy(ctx, globalKey, weight, member)
+	pipe.Expire(ctx, globalKey, r.retention+TrendingBucketSize)
+	if productType != "" {
+		typeKey := r.getBucketKey(bucket, productType)
+		pipe.ZIncrBy(ctx, typeKey, weight, member)
+		pipe.Expire(ctx, typeKey, r.retention+TrendingBucketSize)
+		pipe.SAdd(ctx, trendingTypesKey, productType)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record trending signal: %w", err)
+	}
+
+	return nil
+}
+
+func (r *trendingRepositoryRedis) Compact(ctx context.Context, window, productType string, buckets []time.Time, weights []float64) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(buckets))
+	for i, bucket := range buckets {
+		keys[i] = r.getBucketKey(bucket, productType)
+	}
+
+	// ZUNIONSTORE menimpa skor lama secara atomik; bucket yang tidak ada dianggap kosong
+	scoreKey := r.getScoreKey(window, productType)
+	pipe := r.redisClient.Client.TxPipeline()
+	pipe.ZUnionStore(ctx, scoreKey, &redis.ZStore{Keys: keys, Weights: weights, Aggregate: "SUM"})
+	pipe.ZRemRangeByRank(ctx, scoreKey, 0, int64(-r.maxItems-1))
+	pipe.Expire(ctx, scoreKey, r.aggregateTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to compact trending scores for window %s: %w", window, err)
+	}
+
+	return nil
+}
+
+func (r *trendingRepositoryRedis) GetTop(ctx context.Context, window, productType string, limit int) ([]entities.TrendingScore, error) {
+	members, err := r.redisClient.Client.ZRevRangeWithScores(ctx, r.getScoreKey(window, productType), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending scores: %w", err)
+	}
+
+	scores := make([]entities.TrendingScore, 0, len(members))
+	for _, member := range members {
+		idStr, _ := member.Member.(string)
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			r.log.WithField("member", member.Member).Warn("Invalid product ID in trending scores, skipped")
+			continue
+		}
+		scores = append(scores, entities.TrendingScore{ProductID: id, Score: member.Score})
+	}
+
+	return scores, nil
+}
+
+func (r *trendingRepositoryRedis) GetTypes(ctx context.Context) ([]string, error) {
+	types, err := r.redisClient.Client.SMembers(ctx, trendingTypesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending product types: %w", err)
+	}
+
+	return types, nil
+}
+
+func (r *trendingRepositoryRedis) HasType(ctx context.Context, productType string) (bool, error) {
+	ok, err := r.redisClient.Client.SIsMember(ctx, trendingTypesKey, productType).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check trending product type: %w", err)
+	}
+
+	return ok, nil
+}
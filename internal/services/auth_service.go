@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/grpc/account"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+// Token hasil fallback gRPC yang tidak punya klaim exp hanya di-cache sebentar
+const fallbackIdentityTTL = time.Minute
+
+type AuthService interface {
+	Authenticate(ctx context.Context, token string) (*entities.Identity, error)
+}
+
+type authServiceImpl struct {
+	verifier   *pkg.JWTVerifier
+	tokenRepo  repositories.AuthTokenRepository
+	authClient *account.AuthClient // nil jika fallback gRPC dimatikan
+	log        *logrus.Logger
+}
+
+func NewAuthService(verifier *pkg.JWTVerifier, tokenRepo repositories.AuthTokenRepository, authClient *account.AuthClient, log *logrus.Logger) AuthService {
+	return &authServiceImpl{
+		verifier:   verifier,
+		tokenRepo:  tokenRepo,
+		authClient: authClient,
+		log:        log,
+	}
+}
+
+func (s *authServiceImpl) Authenticate(ctx context.Context, token string) (*entities.Identity, error) {
+	tokenHash := hashToken(token)
+
+	// Klaim dibaca tanpa verifikasi hanya untuk mengambil jti; signature tetap diverifikasi di bawah
+	var jti string
+	if claims, err := pkg.ParseUnverified(token); err == nil {
+		jti = claims.ID
+	}
+
+	// Blacklist dicek sebelum cache supaya token yang baru di-revoke langsung ditolak.
+	// Jika Redis tidak bisa dihubungi, request ditolak karena revocation tidak bisa dipastikan.
+	revoked, err := s.tokenRepo.IsRevoked(ctx, jti, tokenHash)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to check token blacklist")
+		return nil, apperrors.ErrAuthUnavailable
+	}
+	if revoked {
+		return nil, apperrors.ErrTokenRevoked
+	}
+
+	cached, err := s.tokenRepo.GetIdentity(ctx, tokenHash)
+	if err != nil {
+		s.log.WithError(err).Warn("Failed to read cached identity, verifying token again")
+	}
+	if cached != nil && time.Now().Before(cached.ExpiresAt) {
+		return cached, nil
+	}
+
+	identity, err := s.verifyLocally(token)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		identity, err = s.validateRemotely(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ttl := time.Until(identity.ExpiresAt); ttl > 0 {
+		if err := s.tokenRepo.SaveIdentity(ctx, tokenHash, identity, ttl); err != nil {
+			s.log.WithError(err).Warn("Failed to cache identity")
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyLocally mengembalikan identity nil (tanpa error) jika token tidak bisa diverifikasi dengan key lokal
+func (s *authServiceImpl) verifyLocally(token string) (*entities.Identity, error) {
+	if !s.verifier.Enabled() {
+		return nil, nil
+	}
+
+	claims, err := s.verifier.Verify(token)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNoVerificationKey) || errors.Is(err, pkg.ErrUnknownSigningKey) {
+			s.log.WithError(err).Debug("Token cannot be verified locally")
+			return nil, nil
+		}
+
+		s.log.WithError(err).Debug("Token rejected by local verification")
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	return &entities.Identity{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (s *authServiceImpl) validateRemotely(ctx context.Context, token string) (*entities.Identity, error) {
+	if s.authClient == nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	isValid, userID, username, role, errMsg, err := s.authClient.ValidateToken(ctx, token)
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			return nil, apperrors.ErrInvalidToken
+		}
+
+		s.log.WithError(err).Error("Failed to validate token via account service")
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrAuthUnavailable, err)
+	}
+
+	if !isValid {
+		s.log.WithField("reason", errMsg).Debug("Token rejected by account service")
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	expiresAt := time.Now().Add(fallbackIdentityTTL)
+	if claims, err := pkg.ParseUnverified(token); err == nil && claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return &entities.Identity{
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
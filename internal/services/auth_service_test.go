@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/auth"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/grpc/account"
+)
+
+const authTestSecret = "auth-test-secret"
+
+type fakeAuthTokenRepo struct {
+	revoked    map[string]bool
+	revokedErr error
+	identities map[string]*entities.Identity
+	getErr     error
+	saved      map[string]time.Duration
+}
+
+func newFakeAuthTokenRepo() *fakeAuthTokenRepo {
+	return &fakeAuthTokenRepo{
+		revoked:    map[string]bool{},
+		identities: map[string]*entities.Identity{},
+		saved:      map[string]time.Duration{},
+	}
+}
+
+func (f *fakeAuthTokenRepo) IsRevoked(ctx context.Context, keys ...string) (bool, error) {
+	if f.revokedErr != nil {
+		return false, f.revokedErr
+	}
+	for _, key := range keys {
+		if key != "" && f.revoked[key] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (f *fakeAuthTokenRepo) GetIdentity(ctx context.Context, tokenHash string) (*entities.Identity, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+
+	return f.identities[tokenHash], nil
+}
+
+func (f *fakeAuthTokenRepo) SaveIdentity(ctx context.Context, tokenHash string, identity *entities.Identity, ttl time.Duration) error {
+	f.identities[tokenHash] = identity
+	f.saved[tokenHash] = ttl
+	return nil
+}
+
+// fakeAuthServiceClient meng-embed interface supaya tetap compile jika client proto punya RPC lain
+type fakeAuthServiceClient struct {
+	authpb.AuthServiceClient
+	res   *authpb.ValidateTokenResponse
+	err   error
+	calls int
+}
+
+func (f *fakeAuthServiceClient) ValidateToken(ctx context.Context, in *authpb.ValidateTokenRequest, opts ...grpc.CallOption) (*authpb.ValidateTokenResponse, error) {
+	f.calls++
+	return f.res, f.err
+}
+
+func newTestAuthService(secret string, repo *fakeAuthTokenRepo, remote *fakeAuthServiceClient) AuthService {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	var client *account.AuthClient
+	if remote != nil {
+		client = account.NewAuthClientFromService(remote, nil, time.Second)
+	}
+
+	return NewAuthService(pkg.NewJWTVerifier(pkg.JWTVerifierConfig{Secret: secret}), repo, client, log)
+}
+
+func signAuthTestToken(t *testing.T, secret, jti string, ttl time.Duration) string {
+	t.Helper()
+
+	claims := &pkg.Claims{
+		UserID:   "user-1",
+		Username: "alice",
+		Role:     "seller",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return token
+}
+
+func TestAuthenticateLocalTokenIsCached(t *testing.T) {
+	repo := newFakeAuthTokenRepo()
+	svc := newTestAuthService(authTestSecret, repo, nil)
+	token := signAuthTestToken(t, authTestSecret, "jti-1", time.Hour)
+
+	identity, err := svc.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("expected identity, got %v", err)
+	}
+	if identity.UserID != "user-1" || identity.Role != "seller" {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+
+	ttl, ok := repo.saved[hashToken(token)]
+	if !ok {
+		t.Fatal("expected identity to be cached")
+	}
+	if ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("expected cache ttl bounded by token expiry, got %v", ttl)
+	}
+}
+
+func TestAuthenticateBlacklist(t *testing.T) {
+	token := signAuthTestToken(t, authTestSecret, "jti-revoked", time.Hour)
+
+	tests := []struct {
+		name       string
+		revokedKey string
+	}{
+		{"revoked by jti", "jti-revoked"},
+		{"revoked by token hash", hashToken(token)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAuthTokenRepo()
+			repo.revoked[tt.revokedKey] = true
+			// Identity yang sudah di-cache tidak boleh menyelamatkan token yang di-revoke
+			repo.identities[hashToken(token)] = &entities.Identity{UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+			_, err := newTestAuthService(authTestSecret, repo, nil).Authenticate(context.Background(), token)
+			if !errors.Is(err, apperrors.ErrTokenRevoked) {
+				t.Fatalf("expected %v, got %v", apperrors.ErrTokenRevoked, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateFailsClosedWhenBlacklistUnavailable(t *testing.T) {
+	repo := newFakeAuthTokenRepo()
+	repo.revokedErr = errors.New("redis down")
+
+	_, err := newTestAuthService(authTestSecret, repo, nil).Authenticate(context.Background(), signAuthTestToken(t, authTestSecret, "jti-1", time.Hour))
+	if !errors.Is(err, apperrors.ErrAuthUnavailable) {
+		t.Fatalf("expected %v, got %v", apperrors.ErrAuthUnavailable, err)
+	}
+}
+
+func TestAuthenticateCache(t *testing.T) {
+	// Token tidak bisa diverifikasi lokal, jadi hasil sukses hanya mungkin dari cache
+	token := signAuthTestToken(t, "other-secret", "jti-1", time.Hour)
+
+	t.Run("fresh cache entry is used", func(t *testing.T) {
+		repo := newFakeAuthTokenRepo()
+		repo.identities[hashToken(token)] = &entities.Identity{UserID: "cached-user", ExpiresAt: time.Now().Add(time.Minute)}
+
+		identity, err := newTestAuthService(authTestSecret, repo, nil).Authenticate(context.Background(), token)
+		if err != nil {
+			t.Fatalf("expected cached identity, got %v", err)
+		}
+		if identity.UserID != "cached-user" {
+			t.Fatalf("expected cached identity, got %+v", identity)
+		}
+	})
+
+	t.Run("expired cache entry is ignored", func(t *testing.T) {
+		repo := newFakeAuthTokenRepo()
+		repo.identities[hashToken(token)] = &entities.Identity{UserID: "cached-user", ExpiresAt: time.Now().Add(-time.Second)}
+
+		_, err := newTestAuthService(authTestSecret, repo, nil).Authenticate(context.Background(), token)
+		if !errors.Is(err, apperrors.ErrInvalidToken) {
+			t.Fatalf("expected %v, got %v", apperrors.ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("cache read error falls back to verification", func(t *testing.T) {
+		repo := newFakeAuthTokenRepo()
+		repo.getErr = errors.New("redis timeout")
+
+		valid := signAuthTestToken(t, authTestSecret, "jti-2", time.Hour)
+		if _, err := newTestAuthService(authTestSecret, repo, nil).Authenticate(context.Background(), valid); err != nil {
+			t.Fatalf("expected local verification after cache error, got %v", err)
+		}
+	})
+}
+
+func TestAuthenticateRejectsTamperedAndExpiredTokensWithoutFallback(t *testing.T) {
+	valid := signAuthTestToken(t, authTestSecret, "jti-1", time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"tampered payload", tamperAuthTestToken(t, valid)},
+		{"wrong secret", signAuthTestToken(t, "other-secret", "jti-1", time.Hour)},
+		{"expired", signAuthTestToken(t, authTestSecret, "jti-1", -time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := &fakeAuthServiceClient{res: &authpb.ValidateTokenResponse{IsValid: true, UserId: "user-1"}}
+			_, err := newTestAuthService(authTestSecret, newFakeAuthTokenRepo(), remote).Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, apperrors.ErrInvalidToken) {
+				t.Fatalf("expected %v, got %v", apperrors.ErrInvalidToken, err)
+			}
+			// Token yang ditolak secara lokal tidak boleh "diselamatkan" oleh account service
+			if remote.calls != 0 {
+				t.Fatalf("expected no remote validation, got %d calls", remote.calls)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRemoteFallback(t *testing.T) {
+	// Verifier tanpa secret/JWKS: semua token divalidasi lewat gRPC
+	token := signAuthTestToken(t, "account-secret", "jti-1", 2*time.Hour)
+
+	tests := []struct {
+		name    string
+		remote  *fakeAuthServiceClient
+		wantErr error
+	}{
+		{"valid", &fakeAuthServiceClient{res: &authpb.ValidateTokenResponse{IsValid: true, UserId: "user-9", Username: "bob", Role: "admin"}}, nil},
+		{"rejected", &fakeAuthServiceClient{res: &authpb.ValidateTokenResponse{IsValid: false, ErrorMessage: "expired"}}, apperrors.ErrInvalidToken},
+		{"unauthenticated", &fakeAuthServiceClient{err: status.Error(codes.Unauthenticated, "bad token")}, apperrors.ErrInvalidToken},
+		{"unavailable", &fakeAuthServiceClient{err: status.Error(codes.Unavailable, "connection refused")}, apperrors.ErrAuthUnavailable},
+		{"fallback disabled", nil, apperrors.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAuthTokenRepo()
+			identity, err := newTestAuthService("", repo, tt.remote).Authenticate(context.Background(), token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if _, ok := repo.saved[hashToken(token)]; ok {
+					t.Fatal("failed validation must not be cached")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected identity, got %v", err)
+			}
+			if identity.UserID != "user-9" || identity.Role != "admin" {
+				t.Fatalf("unexpected identity: %+v", identity)
+			}
+			// Masa berlaku diambil dari klaim exp, bukan fallbackIdentityTTL
+			if time.Until(identity.ExpiresAt) < time.Hour {
+				t.Fatalf("expected expiry from token claims, got %v", identity.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRemoteFallbackWithoutExpiry(t *testing.T) {
+	remote := &fakeAuthServiceClient{res: &authpb.ValidateTokenResponse{IsValid: true, UserId: "user-9"}}
+	repo := newFakeAuthTokenRepo()
+
+	// Token opaque tanpa klaim exp hanya di-cache selama fallbackIdentityTTL
+	identity, err := newTestAuthService("", repo, remote).Authenticate(context.Background(), "opaque-session-token")
+	if err != nil {
+		t.Fatalf("expected identity, got %v", err)
+	}
+	if until := time.Until(identity.ExpiresAt); until <= 0 || until > fallbackIdentityTTL {
+		t.Fatalf("expected expiry within %v, got %v", fallbackIdentityTTL, until)
+	}
+	if ttl := repo.saved[hashToken("opaque-session-token")]; ttl <= 0 || ttl > fallbackIdentityTTL {
+		t.Fatalf("expected cache ttl within %v, got %v", fallbackIdentityTTL, ttl)
+	}
+}
+
+// tamperAuthTestToken mengganti payload dengan klaim role admin tanpa menandatangani ulang
+func tamperAuthTestToken(t *testing.T, token string) string {
+	t.Helper()
+
+	forged := &pkg.Claims{
+		UserID: "user-1",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	forgedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, forged).SignedString([]byte("attacker"))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + strings.Split(forgedToken, ".")[1] + "." + parts[2]
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -11,48 +12,71 @@ import (
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
 
 	accountpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/account"
 )
 
+// UnknownSellerName ditampilkan jika nama seller tidak tersedia
+const UnknownSellerName = "Unknown seller"
+
+// AnyCartVersion dipakai handler jika klien tidak mengirim If-Match
+const AnyCartVersion = repositories.AnyCartVersion
+
+// Batas panjang (dalam karakter) untuk opsi per baris dan catatan pengiriman per seller
+const (
+	MaxGiftMessageLength = 250
+	MaxVariantNoteLength = 100
+	MaxSellerNoteLength  = 500
+)
+
 type CartSource interface {
 	models.RedisCartItem
 }
 
 type CartService interface {
-	AddItemToCart(ctx context.Context, userID, productID uuid.UUID, req *models.CartRequest) error
+	// Mutasi menerima versi cart yang diharapkan (repositories.AnyCartVersion jika tidak dicek) dan mengembalikan versi baru
+	AddItemToCart(ctx context.Context, userID, productID uuid.UUID, req *models.CartRequest, expectedVersion int64) (int64, error)
 	GetCartItemsByUserID(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
-	UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string) error
-	RemoveItemFromCart(ctx context.Context, userID, productID uuid.UUID) error
+	UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error)
+	RemoveItemFromCart(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error)
+	SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error)
 }
 
 type cartServiceImpl struct {
-	cartRepo      repositories.CartRepository
-	productSvc    ProductService
-	redisClient   *redis.RedisClient
-	accountClient accountpb.AccountServiceClient
-	log           *logrus.Logger
+	cartRepo       repositories.CartRepository
+	sellerNameRepo repositories.SellerNameRepository
+	productSvc     ProductService
+	trendingSvc    TrendingService
+	redisClient    *redis.RedisClient
+	accountClient  accountpb.AccountServiceClient
+	log            *logrus.Logger
 }
 
 func NewCartService(
 	repo repositories.CartRepository,
+	sellerNameRepo repositories.SellerNameRepository,
 	productSvc ProductService,
+	trendingSvc TrendingService,
 	redis *redis.RedisClient,
 	accountClient accountpb.AccountServiceClient,
 	log *logrus.Logger,
 ) CartService {
 	return &cartServiceImpl{
-		cartRepo:      repo,
-		productSvc:    productSvc,
-		redisClient:   redis,
-		accountClient: accountClient,
-		log:           log,
+		cartRepo:       repo,
+		sellerNameRepo: sellerNameRepo,
+		productSvc:     productSvc,
+		trendingSvc:    trendingSvc,
+		redisClient:    redis,
+		accountClient:  accountClient,
+		log:            log,
 	}
 }
 
-func (s *cartServiceImpl) AddItemToCart(ctx context.Context, userID, productID uuid.UUID, req *models.CartRequest) error {
+func (s *cartServiceImpl) AddItemToCart(ctx context.Context, userID, productID uuid.UUID, req *models.CartRequest, expectedVersion int64) (int64, error) {
 	logger := s.log.WithFields(logrus.Fields{
 		"user_id":    userID,
 		"product_id": productID,
@@ -61,40 +85,62 @@ func (s *cartServiceImpl) AddItemToCart(ctx context.Context, userID, productID u
 	logger.Info("Starting the process of adding items to the cart")
 
 	if req.Quantity <= 0 {
-		return fmt.Errorf("the quantity must be greater than 0")
+		return 0, fmt.Errorf("the quantity must be greater than 0")
+	}
+
+	if err := validateCartItemOptions(req.CartItemOptions); err != nil {
+		return 0, err
+	}
+
+	product, err := s.productSvc.GetProductByID(ctx, productID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to retrieve product details from Product Service")
+		return 0, fmt.Errorf("failed to retrieve product details: %w", err)
+	}
+	if !product.IsActive() {
+		logger.WithField("status", product.Status).Warn("Refusing to add non-active product to cart")
+		return 0, apperrors.ErrProductNotActive
 	}
 
 	item := models.RedisCartItem{
-		Quantity:    req.Quantity,
-		Description: req.Description,
-		Checked:     true,
-		AddedAt:     time.Now(),
+		Quantity:        req.Quantity,
+		Description:     req.Description,
+		CartItemOptions: req.CartItemOptions,
+		Checked:         true,
+		AddedAt:         time.Now(),
 	}
 
-	if err := s.cartRepo.AddItem(ctx, userID, productID, item); err != nil {
+	version, err := s.cartRepo.AddItem(ctx, userID, productID, item, expectedVersion)
+	if err != nil {
 		logger.WithError(err).Error("Gagal saat memanggil repository untuk menambah item")
-		return err
+		return version, err
 	}
 
 	logger.Info("Item successfully added to cart")
 
-	return nil
+	// Sinyal trending dicatat per type produk
+	s.trendingSvc.RecordSignal(ctx, productID, product.Type, entities.TrendingSignalAddToCart, req.Quantity)
+
+	return version, nil
 }
 
 func (s *cartServiceImpl) GetCartItemsByUserID(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
 	logger := s.log.WithField("user_id", userID)
 	logger.Info("Retrieving items from the user's cart")
 
-	itemsMap, err := s.cartRepo.GetAllItems(ctx, userID)
+	itemsMap, version, err := s.cartRepo.GetAllItems(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.CartSize.Observe(float64(len(itemsMap)))
+
 	if len(itemsMap) == 0 {
 		return &entities.Cart{
 			UserID:     userID,
 			Items:      []entities.CartItem{},
 			TotalItems: 0,
+			Version:    version,
 		}, nil
 	}
 
@@ -133,11 +179,8 @@ func (s *cartServiceImpl) GetCartItemsByUserID(ctx context.Context, userID uuid.
 		sellerIDs = append(sellerIDs, sellerID)
 	}
 
-	// account
-	accountDetailMap, err := s.fetchAccountDetails(ctx, sellerIDs)
-	if err != nil {
-		return nil, err
-	}
+	// Cart tetap dikembalikan walaupun account service tidak bisa dihubungi; seller tanpa nama ditandai unknown
+	sellerNames, degraded := s.resolveSellerNames(ctx, sellerIDs)
 
 	finalItems := make([]entities.CartItem, 0, len(itemsMap))
 	for productIDStr, redisItem := range itemsMap {
@@ -146,39 +189,62 @@ func (s *cartServiceImpl) GetCartItemsByUserID(ctx context.Context, userID uuid.
 			logger.WithField("product_id", productIDStr).Warn("Detail produk tidak ditemukan, item dilewati.")
 			continue
 		}
-		accountDetail, ok := accountDetailMap[productDetail.SellerID.String()]
-		if !ok {
-			logger.WithField("seller_id", productDetail.SellerID.String()).Warn("Detail penjual tidak ditemukan, item dilewati.")
-			continue
-		}
-
 		productID, _ := uuid.Parse(productIDStr)
-		sellerName := accountDetail.Name
+		assembledItem := toDomainCartItem(productID, redisItem, productDetail, UnknownSellerName)
+
+		if sellerName, ok := sellerNames[productDetail.SellerID.String()]; ok {
+			assembledItem.SellerName = sellerName
+		} else {
+			logger.WithField("seller_id", productDetail.SellerID.String()).Warn("Detail penjual tidak ditemukan, nama seller ditandai unknown.")
+			assembledItem.SellerUnknown = true
+			degraded = true
+		}
 
-		assembledItem := toDomainCartItem(productID, redisItem, productDetail, sellerName)
 		finalItems = append(finalItems, *assembledItem)
 	}
 
 	finalCart := toDomainCart(userID, finalItems)
+	finalCart.Degraded = degraded
+	finalCart.Version = version
+
+	// Catatan untuk seller yang sudah tidak punya item di cart tidak ditampilkan
+	sellerNotes, err := s.cartRepo.GetSellerNotes(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to retrieve seller notes, returning degraded cart")
+		finalCart.Degraded = true
+	}
+	for sellerID, note := range sellerNotes {
+		if !sellerIDMap[sellerID] {
+			continue
+		}
+		if finalCart.SellerNotes == nil {
+			finalCart.SellerNotes = make(map[string]string)
+		}
+		finalCart.SellerNotes[sellerID] = note
+	}
 
 	logger.Info("Successfully retrieved and enriched the basket items")
 	return finalCart, nil
 }
 
-func (s *cartServiceImpl) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string) error {
+func (s *cartServiceImpl) UpdateItem(ctx context.Context, userID, productID uuid.UUID, newQuantity int, newDescription string, options models.CartItemOptions, expectedVersion int64) (int64, error) {
 	logger := s.log.WithFields(logrus.Fields{"user_id": userID, "product_id": productID, "new_quantity": newQuantity})
 
 	if userID == uuid.Nil || productID == uuid.Nil {
-		return fmt.Errorf("invalid user ID or product ID")
+		return 0, fmt.Errorf("invalid user ID or product ID")
 	}
 
 	if newQuantity == 0 {
 		logger.Info("Quantity is 0, removing item from cart")
-		return s.cartRepo.RemoveItem(ctx, userID, productID)
+		return s.cartRepo.RemoveItem(ctx, userID, productID, expectedVersion)
 	}
 
 	if newQuantity < 0 {
-		return fmt.Errorf("kuantitas tidak boleh negatif")
+		return 0, fmt.Errorf("kuantitas tidak boleh negatif")
+	}
+
+	if err := validateCartItemOptions(options); err != nil {
+		return 0, err
 	}
 
 	logger.Info("Call Product Service for stock validation")
@@ -186,20 +252,25 @@ func (s *cartServiceImpl) UpdateItem(ctx context.Context, userID, productID uuid
 	productsSvc, err := s.productSvc.GetProductByID(ctx, productID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to retrieve product details from Product Service")
-		return fmt.Errorf("failed to retrieve product details: %w", err)
+		return 0, fmt.Errorf("failed to retrieve product details: %w", err)
+	}
+
+	if !productsSvc.IsActive() && newQuantity > 0 {
+		return 0, apperrors.ErrProductNotActive
 	}
 
 	if int(productsSvc.Stock) < newQuantity {
 		logger.Warnf("Stock is insufficient. Requested: %d, Available: %d", newQuantity, productsSvc.Stock)
-		return fmt.Errorf("insufficient stock for product '%s'", productsSvc.Name)
+		return 0, fmt.Errorf("insufficient stock for product '%s'", productsSvc.Name)
 	}
 
-	return s.cartRepo.UpdateItem(ctx, userID, productID, newQuantity, newDescription)
+	// Validasi stok di atas hanya berlaku untuk versi cart yang diharapkan; repository menolak penulisan jika cart sudah berubah
+	return s.cartRepo.UpdateItem(ctx, userID, productID, newQuantity, newDescription, options, expectedVersion)
 }
 
-func (s *cartServiceImpl) RemoveItemFromCart(ctx context.Context, userID, productID uuid.UUID) error {
+func (s *cartServiceImpl) RemoveItemFromCart(ctx context.Context, userID, productID uuid.UUID, expectedVersion int64) (int64, error) {
 	if userID == uuid.Nil || productID == uuid.Nil {
-		return fmt.Errorf("invalid user ID or product ID")
+		return 0, fmt.Errorf("invalid user ID or product ID")
 	}
 
 	logger := s.log.WithFields(logrus.Fields{
@@ -208,11 +279,37 @@ func (s *cartServiceImpl) RemoveItemFromCart(ctx context.Context, userID, produc
 	})
 	logger.Info("Remove items from cart")
 
-	return s.cartRepo.RemoveItem(ctx, userID, productID)
+	return s.cartRepo.RemoveItem(ctx, userID, productID, expectedVersion)
+}
+
+func (s *cartServiceImpl) SetSellerNote(ctx context.Context, userID, sellerID uuid.UUID, note string, expectedVersion int64) (int64, error) {
+	if userID == uuid.Nil || sellerID == uuid.Nil {
+		return 0, fmt.Errorf("%w: invalid user ID or seller ID", apperrors.ErrInvalidRequestPayload)
+	}
+
+	if utf8.RuneCountInString(note) > MaxSellerNoteLength {
+		return 0, fmt.Errorf("%w: seller note must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxSellerNoteLength)
+	}
+
+	s.log.WithFields(logrus.Fields{"user_id": userID, "seller_id": sellerID}).Info("Saving seller shipping note")
+
+	return s.cartRepo.SetSellerNote(ctx, userID, sellerID, note, expectedVersion)
 }
 
 // ------- HELPERS -------
 
+func validateCartItemOptions(options models.CartItemOptions) error {
+	if utf8.RuneCountInString(options.GiftMessage) > MaxGiftMessageLength {
+		return fmt.Errorf("%w: gift message must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxGiftMessageLength)
+	}
+
+	if utf8.RuneCountInString(options.VariantNote) > MaxVariantNoteLength {
+		return fmt.Errorf("%w: variant note must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxVariantNoteLength)
+	}
+
+	return nil
+}
+
 func (s *cartServiceImpl) fetchAccountDetail(ctx context.Context, sellerID string) (*accountpb.User, error) {
 	accountResponse, err := s.accountClient.GetUser(ctx, &accountpb.GetUserRequest{Id: sellerID})
 	if err != nil {
@@ -222,18 +319,41 @@ func (s *cartServiceImpl) fetchAccountDetail(ctx context.Context, sellerID strin
 	return accountResponse, nil
 }
 
-func (s *cartServiceImpl) fetchAccountDetails(ctx context.Context, sellerIDs []string) (map[string]*accountpb.User, error) {
-	accountResponse, err := s.accountClient.GetUsers(ctx, &accountpb.GetUsersRequest{Ids: sellerIDs})
+// resolveSellerNames mengambil nama seller dari cache Redis, lalu account service untuk sisanya.
+// degraded bernilai true jika account service gagal dipanggil sehingga sebagian nama mungkin hilang.
+func (s *cartServiceImpl) resolveSellerNames(ctx context.Context, sellerIDs []string) (names map[string]string, degraded bool) {
+	names, err := s.sellerNameRepo.GetNames(ctx, sellerIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve seller details via gRPC: %w", err)
+		s.log.WithError(err).Warn("Failed to read cached seller names")
+	}
+
+	var missing []string
+	for _, id := range sellerIDs {
+		if _, ok := names[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return names, false
+	}
+
+	accountResponse, err := s.accountClient.GetUsers(ctx, &accountpb.GetUsersRequest{Ids: missing})
+	if err != nil {
+		s.log.WithError(err).WithField("seller_ids", missing).Warn("Failed to retrieve seller details via gRPC, returning degraded cart")
+		return names, true
+	}
+
+	fetched := make(map[string]string, len(accountResponse.GetUsers()))
+	for _, user := range accountResponse.GetUsers() {
+		fetched[user.Id] = user.Name
+		names[user.Id] = user.Name
 	}
 
-	accountDetailsMap := make(map[string]*accountpb.User)
-	for _, a := range accountResponse.Users {
-		accountDetailsMap[a.Id] = a
+	if err := s.sellerNameRepo.SaveNames(ctx, fetched); err != nil {
+		s.log.WithError(err).Warn("Failed to cache seller names")
 	}
 
-	return accountDetailsMap, nil
+	return names, false
 }
 
 func toDomainCartItem(
@@ -252,6 +372,9 @@ func toDomainCartItem(
 		SellerName:      sellerName,
 		Quantity:        redisItem.Quantity,
 		Description:     redisItem.Description,
+		GiftWrap:        redisItem.GiftWrap,
+		GiftMessage:     redisItem.GiftMessage,
+		VariantNote:     redisItem.VariantNote,
 		Checked:         redisItem.Checked,
 	}
 }
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+)
+
+const (
+	MaxAttributeConditions     = 10
+	MaxAttributeStringLength   = 500
+	MaxAttributeSearchResults  = 1000
+	maxAttributeConditionValue = 100
+)
+
+// Sama dengan CHECK di tabel product_attribute_definitions; juga menjamin key aman disisipkan ke jsonpath
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+func (s *productServiceImpl) GetAttributeDefinitions(ctx context.Context, productType string) ([]entities.AttributeDefinition, error) {
+	rows, err := s.attributeRepo.GetDefinitionsByType(ctx, productType)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainAttributeDefinitions(rows), nil
+}
+
+func (s *productServiceImpl) UpsertAttributeDefinition(ctx context.Context, subject policies.Subject, productType, key string, req *models.AttributeDefinitionRequest) (*entities.AttributeDefinition, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionManageAttributes, policies.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+	if productType == "" || !attributeKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w: attribute key must match %s", apperrors.ErrInvalidRequestPayload, attributeKeyPattern)
+	}
+
+	dataType := entities.AttributeDataType(req.DataType)
+	allowedValues := make([]string, 0, len(req.AllowedValues))
+	for _, v := range req.AllowedValues {
+		switch dataType {
+		case entities.AttributeTypeBoolean:
+			return nil, fmt.Errorf("%w: allowed_values is not supported for boolean attributes", apperrors.ErrInvalidRequestPayload)
+		case entities.AttributeTypeNumber:
+			// Disimpan dalam bentuk kanonik supaya cocok dengan formatAttributeNumber saat validasi
+			n, err := parseAttributeNumber(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: allowed value '%s' is not a number", apperrors.ErrInvalidRequestPayload, v)
+			}
+			v = formatAttributeNumber(n)
+		}
+		allowedValues = append(allowedValues, v)
+	}
+
+	row, err := s.attributeRepo.UpsertDefinition(ctx, db.UpsertAttributeDefinitionParams{
+		ProductType:   productType,
+		Key:           key,
+		DataType:      string(dataType),
+		Required:      req.Required,
+		AllowedValues: allowedValues,
+		Unit:          helpers.OptionalStringToNullString(req.Unit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"product_type": productType,
+		"key":          key,
+		"user_id":      subject.UserID,
+	}).Info("Attribute definition saved")
+
+	return toDomainAttributeDefinition(row), nil
+}
+
+func (s *productServiceImpl) DeleteAttributeDefinition(ctx context.Context, subject policies.Subject, productType, key string) error {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionManageAttributes, policies.Resource{}); err != nil {
+		return err
+	}
+
+	deleted, err := s.attributeRepo.DeleteDefinition(ctx, productType, key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.ErrAttributeDefinitionNotFound
+	}
+
+	return nil
+}
+
+// SearchProducts tidak memakai cache karena kombinasi filter terlalu beragam; filter atribut, rating, urutan dan
+// halaman dijalankan di DB lewat index GIN dan idx_products_rating.
+// Pemilik, staff-nya dan admin tetap melihat produk non-active saat mencari di list seller.
+func (s *productServiceImpl) SearchProducts(ctx context.Context, viewer policies.Subject, query entities.ProductSearchQuery) ([]entities.Product, error) {
+	limit, offset := normalizePage(query.Limit, query.Offset, MaxAttributeSearchResults, MaxAttributeSearchResults)
+	params := db.SearchProductsParams{
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	}
+
+	if len(query.Conditions) > 0 {
+		filter, err := buildAttributeFilter(query.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		params.AttributeFilter = filter
+	}
+	if query.MinRating != nil {
+		if *query.MinRating < 0 || *query.MinRating > 5 {
+			return nil, fmt.Errorf("%w: min_rating must be between 0 and 5", apperrors.ErrInvalidRequestPayload)
+		}
+		params.MinRating = sql.NullFloat64{Float64: *query.MinRating, Valid: true}
+	}
+	if query.Name != "" {
+		params.NamePattern = helpers.StringToNullString("%" + query.Name + "%")
+	}
+	if query.Type != "" {
+		params.ProductType = helpers.StringToNullString(query.Type)
+	}
+	if query.SellerID != uuid.Nil {
+		params.SellerID = uuid.NullUUID{UUID: query.SellerID, Valid: true}
+		params.IncludeInactive = s.canViewInactive(ctx, viewer, query.SellerID)
+	}
+
+	if query.SortByRating {
+		rows, err := s.productRepo.SearchProductsByRating(ctx, db.SearchProductsByRatingParams(params))
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to search products by rating: %w", err)
+		}
+		return toDomainProducts(rows), nil
+	}
+
+	rows, err := s.productRepo.SearchProducts(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to search products: %w", err)
+	}
+
+	return toDomainProducts(rows), nil
+}
+
+// resolveAttributes memvalidasi atribut terhadap definisi type produk lalu mengubahnya ke JSONB
+func (s *productServiceImpl) resolveAttributes(ctx context.Context, productType string, attrs map[string]interface{}) (json.RawMessage, error) {
+	rows, err := s.attributeRepo.GetDefinitionsByType(ctx, productType)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to load attribute definitions: %w", err)
+	}
+
+	if err := validateAttributes(toDomainAttributeDefinitions(rows), productType, attrs); err != nil {
+		return nil, err
+	}
+
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+
+	raw, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidProductAttributes, err)
+	}
+
+	return raw, nil
+}
+
+func validateAttributes(defs []entities.AttributeDefinition, productType string, attrs map[string]interface{}) error {
+	byKey := make(map[string]entities.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byKey[def.Key] = def
+	}
+
+	var problems []string
+	for key, value := range attrs {
+		def, ok := byKey[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("'%s' is not defined for type '%s'", key, productType))
+			continue
+		}
+		if problem := checkAttributeValue(def, value); problem != "" {
+			problems = append(problems, fmt.Sprintf("'%s' %s", key, problem))
+		}
+	}
+
+	for _, def := range defs {
+		if _, ok := attrs[def.Key]; def.Required && !ok {
+			problems = append(problems, fmt.Sprintf("'%s' is required", def.Key))
+		}
+	}
+
+	if len(problems) > 0 {
+		// Urutan map tidak stabil; pesan diurutkan supaya respons konsisten
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalidProductAttributes, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+func checkAttributeValue(def entities.AttributeDefinition, value interface{}) string {
+	var canonical string
+	switch def.DataType {
+	case entities.AttributeTypeString:
+		str, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(str) > MaxAttributeStringLength {
+			return fmt.Sprintf("must be at most %d characters", MaxAttributeStringLength)
+		}
+		canonical = str
+	case entities.AttributeTypeNumber:
+		// Angka dari JSON body selalu di-decode sebagai float64
+		n, ok := value.(float64)
+		if !ok {
+			return "must be a number"
+		}
+		canonical = formatAttributeNumber(n)
+	case entities.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+		return ""
+	}
+
+	if len(def.AllowedValues) == 0 {
+		return ""
+	}
+	for _, allowed := range def.AllowedValues {
+		if allowed == canonical {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("must be one of [%s]", strings.Join(def.AllowedValues, ", "))
+}
+
+// buildAttributeFilter menyusun predikat jsonpath untuk operator @@.
+// Key sudah dicek dengan attributeKeyPattern dan nilai string di-encode sebagai literal JSON, jadi input user tidak bisa keluar dari literal.
+func buildAttributeFilter(conditions []entities.AttributeCondition) (string, error) {
+	if len(conditions) == 0 {
+		return "", fmt.Errorf("%w: at least one attribute filter is required", apperrors.ErrInvalidRequestPayload)
+	}
+	if len(conditions) > MaxAttributeConditions {
+		return "", fmt.Errorf("%w: at most %d attribute filters are allowed", apperrors.ErrInvalidRequestPayload, MaxAttributeConditions)
+	}
+
+	predicates := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		if !attributeKeyPattern.MatchString(cond.Key) {
+			return "", fmt.Errorf("%w: invalid attribute key '%s'", apperrors.ErrInvalidRequestPayload, cond.Key)
+		}
+		if cond.Value == "" || len(cond.Value) > maxAttributeConditionValue {
+			return "", fmt.Errorf("%w: invalid value for attribute '%s'", apperrors.ErrInvalidRequestPayload, cond.Key)
+		}
+
+		path := fmt.Sprintf(`$."%s"`, cond.Key)
+		switch cond.Operator {
+		case entities.AttributeOpEq, entities.AttributeOpNe:
+			predicate := attributeEqualsPredicate(path, cond.Value)
+			if cond.Operator == entities.AttributeOpNe {
+				predicate = "!" + predicate
+			}
+			predicates = append(predicates, predicate)
+		case entities.AttributeOpGt, entities.AttributeOpGte, entities.AttributeOpLt, entities.AttributeOpLte:
+			n, err := parseAttributeNumber(cond.Value)
+			if err != nil {
+				return "", fmt.Errorf("%w: attribute '%s' %s requires a number", apperrors.ErrInvalidRequestPayload, cond.Key, cond.Operator)
+			}
+			predicates = append(predicates, fmt.Sprintf("%s %s %s", path, cond.Operator, formatAttributeNumber(n)))
+		default:
+			return "", fmt.Errorf("%w: unsupported operator '%s'", apperrors.ErrInvalidRequestPayload, cond.Operator)
+		}
+	}
+
+	return strings.Join(predicates, " && "), nil
+}
+
+// Query string tidak membawa tipe data, jadi nilai yang terlihat seperti angka atau boolean juga dicocokkan dengan bentuk string-nya
+func attributeEqualsPredicate(path, value string) string {
+	literal, _ := json.Marshal(value)
+	predicate := fmt.Sprintf("%s == %s", path, literal)
+
+	if n, err := parseAttributeNumber(value); err == nil {
+		predicate = fmt.Sprintf("(%s || %s == %s)", predicate, path, formatAttributeNumber(n))
+	} else if value == "true" || value == "false" {
+		predicate = fmt.Sprintf("(%s || %s == %s)", predicate, path, value)
+	}
+
+	return "(" + predicate + ")"
+}
+
+func parseAttributeNumber(value string) (float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("'%s' is not a finite number", value)
+	}
+
+	return n, nil
+}
+
+func formatAttributeNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func toDomainAttributeDefinition(row *db.ProductAttributeDefinition) *entities.AttributeDefinition {
+	return &entities.AttributeDefinition{
+		ProductType:   row.ProductType,
+		Key:           row.Key,
+		DataType:      entities.AttributeDataType(row.DataType),
+		Required:      row.Required,
+		AllowedValues: row.AllowedValues,
+		Unit:          row.Unit.String,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
+
+func toDomainAttributeDefinitions(rows []db.ProductAttributeDefinition) []entities.AttributeDefinition {
+	defs := make([]entities.AttributeDefinition, 0, len(rows))
+	for i := range rows {
+		defs = append(defs, *toDomainAttributeDefinition(&rows[i]))
+	}
+
+	return defs
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+func cond(key string, op entities.AttributeOperator, value string) entities.AttributeCondition {
+	return entities.AttributeCondition{Key: key, Operator: op, Value: value}
+}
+
+func TestBuildAttributeFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []entities.AttributeCondition
+		want       string
+	}{
+		// operator
+		{"equals string", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, "acme")}, `($."brand" == "acme")`},
+		{"not equals string", []entities.AttributeCondition{cond("brand", entities.AttributeOpNe, "acme")}, `!($."brand" == "acme")`},
+		{"greater than", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpGt, "8")}, `$."ram_gb" > 8`},
+		{"greater or equal", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpGte, "8")}, `$."ram_gb" >= 8`},
+		{"less than", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpLt, "8")}, `$."ram_gb" < 8`},
+		{"less or equal", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpLte, "8")}, `$."ram_gb" <= 8`},
+		{"conditions are combined with and", []entities.AttributeCondition{
+			cond("brand", entities.AttributeOpEq, "acme"),
+			cond("ram_gb", entities.AttributeOpGte, "8"),
+		}, `($."brand" == "acme") && $."ram_gb" >= 8`},
+
+		// nilai angka dan boolean dari query string juga dicocokkan sebagai string
+		{"equals number matches string or number", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpEq, "16")}, `(($."ram_gb" == "16" || $."ram_gb" == 16))`},
+		{"not equals number", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpNe, "16")}, `!(($."ram_gb" == "16" || $."ram_gb" == 16))`},
+		{"equals boolean", []entities.AttributeCondition{cond("wireless", entities.AttributeOpEq, "true")}, `(($."wireless" == "true" || $."wireless" == true))`},
+		{"equals false", []entities.AttributeCondition{cond("wireless", entities.AttributeOpEq, "false")}, `(($."wireless" == "false" || $."wireless" == false))`},
+		{"capitalized boolean is only a string", []entities.AttributeCondition{cond("wireless", entities.AttributeOpEq, "True")}, `($."wireless" == "True")`},
+
+		// kanonikalisasi angka
+		{"trailing zeros", []entities.AttributeCondition{cond("weight", entities.AttributeOpEq, "1.50")}, `(($."weight" == "1.50" || $."weight" == 1.5))`},
+		{"leading zeros", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpGte, "008")}, `$."ram_gb" >= 8`},
+		{"exponent", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpLt, "1e3")}, `$."ram_gb" < 1000`},
+		{"negative fraction", []entities.AttributeCondition{cond("offset", entities.AttributeOpGt, "-0.250")}, `$."offset" > -0.25`},
+		{"integer float", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpLte, "16.0")}, `$."ram_gb" <= 16`},
+		{"NaN is only a string for equality", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpEq, "NaN")}, `($."ram_gb" == "NaN")`},
+
+		// quoting dan escaping literal string
+		{"double quote", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, `ac"me`)}, `($."brand" == "ac\"me")`},
+		{"backslash", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, `ac\me`)}, `($."brand" == "ac\\me")`},
+		{"jsonpath operators stay inside the literal", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, `x" || $."a" == "b`)}, `($."brand" == "x\" || $.\"a\" == \"b")`},
+		{"html characters are unicode escaped", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, "<a&b>")}, `($."brand" == "\u003ca\u0026b\u003e")`},
+		{"newline", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, "a\nb")}, `($."brand" == "a\nb")`},
+		{"unicode", []entities.AttributeCondition{cond("color", entities.AttributeOpEq, "merah muda é")}, `($."color" == "merah muda é")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildAttributeFilter(tt.conditions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildAttributeFilterRejectsInvalidConditions(t *testing.T) {
+	tooMany := make([]entities.AttributeCondition, MaxAttributeConditions+1)
+	for i := range tooMany {
+		tooMany[i] = cond("brand", entities.AttributeOpEq, "acme")
+	}
+
+	tests := []struct {
+		name       string
+		conditions []entities.AttributeCondition
+	}{
+		{"no conditions", nil},
+		{"too many conditions", tooMany},
+		{"empty key", []entities.AttributeCondition{cond("", entities.AttributeOpEq, "acme")}},
+		{"uppercase key", []entities.AttributeCondition{cond("Brand", entities.AttributeOpEq, "acme")}},
+		{"key starting with digit", []entities.AttributeCondition{cond("1brand", entities.AttributeOpEq, "acme")}},
+		{"key with quote", []entities.AttributeCondition{cond(`brand" || true || "`, entities.AttributeOpEq, "acme")}},
+		{"key with dot", []entities.AttributeCondition{cond("brand.name", entities.AttributeOpEq, "acme")}},
+		{"key with dash", []entities.AttributeCondition{cond("ram-gb", entities.AttributeOpEq, "8")}},
+		{"key too long", []entities.AttributeCondition{cond("a"+strings.Repeat("b", 64), entities.AttributeOpEq, "acme")}},
+		{"empty value", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, "")}},
+		{"value too long", []entities.AttributeCondition{cond("brand", entities.AttributeOpEq, strings.Repeat("a", maxAttributeConditionValue+1))}},
+		{"range on non-number", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpGt, "eight")}},
+		{"range on NaN", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpGte, "NaN")}},
+		{"range on infinity", []entities.AttributeCondition{cond("ram_gb", entities.AttributeOpLt, "Inf")}},
+		{"range on boolean", []entities.AttributeCondition{cond("wireless", entities.AttributeOpLte, "true")}},
+		{"unknown operator", []entities.AttributeCondition{cond("brand", entities.AttributeOperator("~"), "acme")}},
+		{"one invalid condition rejects all", []entities.AttributeCondition{
+			cond("brand", entities.AttributeOpEq, "acme"),
+			cond("Bad Key", entities.AttributeOpEq, "x"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildAttributeFilter(tt.conditions)
+			if !errors.Is(err, apperrors.ErrInvalidRequestPayload) {
+				t.Fatalf("expected %v, got filter %q and error %v", apperrors.ErrInvalidRequestPayload, got, err)
+			}
+		})
+	}
+}
+
+func TestValidateAttributes(t *testing.T) {
+	defs := []entities.AttributeDefinition{
+		{Key: "brand", DataType: entities.AttributeTypeString, Required: true},
+		{Key: "color", DataType: entities.AttributeTypeString, AllowedValues: []string{"black", "white"}},
+		{Key: "ram_gb", DataType: entities.AttributeTypeNumber, AllowedValues: []string{"8", "16", "0.5"}},
+		{Key: "weight", DataType: entities.AttributeTypeNumber},
+		{Key: "wireless", DataType: entities.AttributeTypeBoolean},
+	}
+
+	tests := []struct {
+		name    string
+		attrs   map[string]interface{}
+		wantErr []string // potongan pesan yang harus muncul; nil berarti valid
+	}{
+		{"only required", map[string]interface{}{"brand": "acme"}, nil},
+		{"all attributes", map[string]interface{}{"brand": "acme", "color": "black", "ram_gb": float64(16), "weight": 1.25, "wireless": true}, nil},
+		{"allowed number is canonicalized", map[string]interface{}{"brand": "acme", "ram_gb": 16.0}, nil},
+		{"allowed fraction", map[string]interface{}{"brand": "acme", "ram_gb": 0.5}, nil},
+		{"string at max length", map[string]interface{}{"brand": strings.Repeat("a", MaxAttributeStringLength)}, nil},
+
+		{"missing required", map[string]interface{}{"color": "black"}, []string{"'brand' is required"}},
+		{"nil attributes with required key", nil, []string{"'brand' is required"}},
+		{"unknown key", map[string]interface{}{"brand": "acme", "size": "xl"}, []string{"'size' is not defined for type 'laptop'"}},
+		{"string given a number", map[string]interface{}{"brand": float64(1)}, []string{"'brand' must be a string"}},
+		{"number given a string", map[string]interface{}{"brand": "acme", "weight": "1.5"}, []string{"'weight' must be a number"}},
+		{"number given an int", map[string]interface{}{"brand": "acme", "weight": 2}, []string{"'weight' must be a number"}},
+		{"boolean given a string", map[string]interface{}{"brand": "acme", "wireless": "true"}, []string{"'wireless' must be a boolean"}},
+		{"string too long", map[string]interface{}{"brand": strings.Repeat("a", MaxAttributeStringLength+1)}, []string{"'brand' must be at most"}},
+		{"string not allowed", map[string]interface{}{"brand": "acme", "color": "red"}, []string{"'color' must be one of [black, white]"}},
+		{"allowed values are case sensitive", map[string]interface{}{"brand": "acme", "color": "Black"}, []string{"'color' must be one of"}},
+		{"number not allowed", map[string]interface{}{"brand": "acme", "ram_gb": float64(12)}, []string{"'ram_gb' must be one of [8, 16, 0.5]"}},
+		{"every problem is reported", map[string]interface{}{"color": "red", "size": "xl"}, []string{"'brand' is required", "'color' must be one of", "'size' is not defined"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttributes(defs, "laptop", tt.attrs)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected attributes to be valid, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, apperrors.ErrInvalidProductAttributes) {
+				t.Fatalf("expected %v, got %v", apperrors.ErrInvalidProductAttributes, err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAttributesWithoutDefinitions(t *testing.T) {
+	if err := validateAttributes(nil, "misc", nil); err != nil {
+		t.Fatalf("expected empty attributes to be valid, got %v", err)
+	}
+
+	err := validateAttributes(nil, "misc", map[string]interface{}{"brand": "acme"})
+	if !errors.Is(err, apperrors.ErrInvalidProductAttributes) {
+		t.Fatalf("expected attributes on a type without definitions to be rejected, got %v", err)
+	}
+}
+
+// Pesan diurutkan supaya respons yang sama selalu identik walau urutan map acak
+func TestValidateAttributesMessageIsStable(t *testing.T) {
+	defs := []entities.AttributeDefinition{{Key: "brand", DataType: entities.AttributeTypeString}}
+	attrs := map[string]interface{}{"zeta": 1.0, "alpha": 1.0, "mid": 1.0, "brand": 1.0}
+
+	first := validateAttributes(defs, "laptop", attrs).Error()
+	for i := 0; i < 20; i++ {
+		if got := validateAttributes(defs, "laptop", attrs).Error(); got != first {
+			t.Fatalf("expected stable message, got %q and %q", first, got)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+)
+
+// maxBulkProducts sama dengan batas 'ids' di BulkProductRequest
+const maxBulkProducts = 1000
+
+func (s *productServiceImpl) BulkUpdateProducts(ctx context.Context, req *models.BulkProductRequest, subject policies.Subject) ([]entities.Product, error) {
+	if err := s.validateBulkRequest(req); err != nil {
+		return nil, err
+	}
+
+	logger := s.log.WithFields(logrus.Fields{
+		"user_id":   subject.UserID,
+		"operation": req.Operation,
+	})
+
+	tx, err := s.productRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Kunci semua baris target dulu supaya pengecekan kepemilikan dan update berada di transaksi yang sama
+	var lockedProducts []db.Product
+	if len(req.IDs) > 0 {
+		ids := make([]uuid.UUID, 0, len(req.IDs))
+		for _, idStr := range req.IDs {
+			id, err := helpers.StringToUUID(idStr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, err)
+			}
+			ids = append(ids, id)
+		}
+
+		lockedProducts, err = s.productRepo.LockProductsByIDs(ctx, tx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to find products for bulk operation: %w", err)
+		}
+
+		if len(lockedProducts) != len(uniqueUUIDs(ids)) {
+			return nil, fmt.Errorf("%w: some of the requested products do not exist", apperrors.ErrProductNotFound)
+		}
+	} else {
+		filter, err := bulkFilterParams(req.Filter, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		lockedProducts, err = s.productRepo.LockProductsByFilter(ctx, tx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to find products for bulk operation: %w", err)
+		}
+
+		if len(lockedProducts) > maxBulkProducts {
+			return nil, fmt.Errorf("%w: filter matches more than %d products, narrow it down or use 'ids'", apperrors.ErrInvalidRequestPayload, maxBulkProducts)
+		}
+	}
+
+	if len(lockedProducts) == 0 {
+		return []entities.Product{}, nil
+	}
+
+	action := policies.ActionUpdateProduct
+	if req.Operation == models.BulkOperationDelete {
+		action = policies.ActionDeleteProduct
+	}
+
+	// Cukup satu pengecekan policy per seller, bukan per produk
+	authorizedSellers := make(map[uuid.UUID]struct{})
+	targetIDs := make([]uuid.UUID, 0, len(lockedProducts))
+	for _, p := range lockedProducts {
+		if _, ok := authorizedSellers[p.SellerID]; !ok {
+			if err := s.policy.Authorize(ctx, subject, action, policies.Resource{SellerID: p.SellerID}); err != nil {
+				return nil, fmt.Errorf("%w (product %s)", err, p.ID)
+			}
+			authorizedSellers[p.SellerID] = struct{}{}
+		}
+		targetIDs = append(targetIDs, p.ID)
+	}
+
+	if req.Operation == models.BulkOperationAdjustPrice {
+		if err := checkAdjustedPrices(lockedProducts, req.PricePercent); err != nil {
+			return nil, err
+		}
+	}
+	if req.Operation == models.BulkOperationSetType {
+		if err := s.checkAttributesForType(ctx, lockedProducts, req.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	var updatedProducts []db.Product
+	switch req.Operation {
+	case models.BulkOperationAdjustPrice:
+		updatedProducts, err = s.productRepo.BulkAdjustProductPrice(ctx, tx, db.BulkAdjustProductPriceParams{
+			Percent: req.PricePercent,
+			Ids:     targetIDs,
+		})
+	case models.BulkOperationSetDiscount:
+		updatedProducts, err = s.productRepo.BulkSetProductDiscount(ctx, tx, db.BulkSetProductDiscountParams{
+			Discount: helpers.IntToNullInt32(*req.Discount),
+			Ids:      targetIDs,
+		})
+	case models.BulkOperationSetType:
+		updatedProducts, err = s.productRepo.BulkSetProductType(ctx, tx, db.BulkSetProductTypeParams{
+			ProductType: helpers.StringToNullString(req.Type),
+			Ids:         targetIDs,
+		})
+	case models.BulkOperationDelete:
+		updatedProducts, err = s.productRepo.BulkDeleteProducts(ctx, tx, targetIDs)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Bulk operation failed, rolling back")
+		return nil, fmt.Errorf("service: failed to run bulk %s: %w", req.Operation, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk operation transaction: %w", err)
+	}
+
+	s.invalidateBulkCaches(ctx, lockedProducts, updatedProducts)
+
+	logger.WithField("affected", len(updatedProducts)).Info("Bulk product operation completed")
+	return toDomainProducts(updatedProducts), nil
+}
+
+// ------- HELPERS -------
+
+// checkAttributesForType menolak seluruh operasi jika atribut salah satu produk tidak memenuhi definisi type tujuan
+func (s *productServiceImpl) checkAttributesForType(ctx context.Context, products []db.Product, productType string) error {
+	rows, err := s.attributeRepo.GetDefinitionsByType(ctx, productType)
+	if err != nil {
+		return fmt.Errorf("service: failed to load attribute definitions: %w", err)
+	}
+	defs := toDomainAttributeDefinitions(rows)
+
+	for _, p := range products {
+		if err := validateAttributes(defs, productType, helpers.ConvertJSONMap(reflect.ValueOf(p.Attributes))); err != nil {
+			return fmt.Errorf("%w (product %s)", err, p.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *productServiceImpl) validateBulkRequest(req *models.BulkProductRequest) error {
+	if err := s.validateRequest(req); err != nil {
+		return err
+	}
+
+	hasFilter := req.Filter != nil && (req.Filter.SellerID != "" || req.Filter.Type != "")
+	if len(req.IDs) == 0 && !hasFilter {
+		return fmt.Errorf("%w: either 'ids' or a non-empty 'filter' is required", apperrors.ErrInvalidRequestPayload)
+	}
+	if len(req.IDs) > 0 && hasFilter {
+		return fmt.Errorf("%w: 'ids' and 'filter' cannot be combined", apperrors.ErrInvalidRequestPayload)
+	}
+
+	switch req.Operation {
+	case models.BulkOperationAdjustPrice:
+		if req.PricePercent == 0 {
+			return fmt.Errorf("%w: 'price_percent' is required for %s", apperrors.ErrInvalidRequestPayload, req.Operation)
+		}
+	case models.BulkOperationSetDiscount:
+		if req.Discount == nil {
+			return fmt.Errorf("%w: 'discount' is required for %s", apperrors.ErrInvalidRequestPayload, req.Operation)
+		}
+	case models.BulkOperationSetType:
+		if req.Type == "" {
+			return fmt.Errorf("%w: 'type' is required for %s", apperrors.ErrInvalidRequestPayload, req.Operation)
+		}
+	}
+
+	return nil
+}
+
+// bulkFilterParams default-nya membatasi filter ke katalog milik subject; admin tanpa seller_id berarti semua seller.
+// Seller staff wajib menyebut seller_id, dan policy yang memastikan dia memang staff seller tersebut.
+func bulkFilterParams(filter *models.BulkProductFilter, subject policies.Subject) (db.LockProductsByFilterParams, error) {
+	params := db.LockProductsByFilterParams{
+		ProductType: helpers.OptionalStringToNullString(filter.Type),
+		// satu baris lebih dari batas supaya filter yang terlalu luas bisa ditolak
+		RowLimit: maxBulkProducts + 1,
+	}
+
+	if filter.SellerID != "" {
+		filterSellerID, err := helpers.StringToUUID(filter.SellerID)
+		if err != nil {
+			return params, fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, err)
+		}
+		params.SellerID = uuid.NullUUID{UUID: filterSellerID, Valid: true}
+		return params, nil
+	}
+
+	if subject.Role != policies.RoleAdmin {
+		params.SellerID = uuid.NullUUID{UUID: subject.UserID, Valid: true}
+	}
+
+	return params, nil
+}
+
+// checkAdjustedPrices mengikuti rumus BulkAdjustProductPrice supaya harga yang melewati batas kolom INT
+// ditolak sebagai payload tidak valid, bukan gagal "integer out of range" di Postgres
+func checkAdjustedPrices(products []db.Product, percent float64) error {
+	var overflow []string
+	for _, p := range products {
+		if adjusted := math.Max(math.Round(float64(p.Price)*(1+percent/100)), 1); adjusted > math.MaxInt32 {
+			overflow = append(overflow, p.ID.String())
+		}
+	}
+	if len(overflow) > 0 {
+		return fmt.Errorf("%w: adjusted price exceeds the maximum of %d for products %s", apperrors.ErrInvalidRequestPayload, math.MaxInt32, strings.Join(overflow, ", "))
+	}
+
+	return nil
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+// fakeBulkProductRepo mensimulasikan filter yang cocok dengan `matching` produk, dipotong oleh RowLimit
+type fakeBulkProductRepo struct {
+	repositories.ProductRepository
+
+	conn     *sql.DB
+	matching int
+	params   []db.LockProductsByFilterParams
+	updated  bool
+}
+
+func (f *fakeBulkProductRepo) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return f.conn.BeginTx(ctx, nil)
+}
+
+func (f *fakeBulkProductRepo) LockProductsByFilter(ctx context.Context, tx *sql.Tx, params db.LockProductsByFilterParams) ([]db.Product, error) {
+	f.params = append(f.params, params)
+
+	n := min(f.matching, int(params.RowLimit))
+	products := make([]db.Product, 0, n)
+	for i := 0; i < n; i++ {
+		products = append(products, db.Product{ID: uuid.New(), SellerID: params.SellerID.UUID})
+	}
+	return products, nil
+}
+
+func (f *fakeBulkProductRepo) BulkDeleteProducts(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) ([]db.Product, error) {
+	f.updated = true
+	return nil, nil
+}
+
+func TestBulkUpdateProductsRejectsTooBroadFilter(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	repo := &fakeBulkProductRepo{conn: conn, matching: maxBulkProducts + 50}
+	svc := NewProductService(repo, nil, nil, nil, nil, nil, validator.New(), nil, log)
+
+	seller := uuid.New()
+	_, err = svc.BulkUpdateProducts(context.Background(), &models.BulkProductRequest{
+		Filter:    &models.BulkProductFilter{Type: "electronics"},
+		Operation: models.BulkOperationDelete,
+	}, policies.Subject{UserID: seller, Role: policies.RoleSeller})
+	if !errors.Is(err, apperrors.ErrInvalidRequestPayload) {
+		t.Fatalf("expected %v, got %v", apperrors.ErrInvalidRequestPayload, err)
+	}
+
+	if len(repo.params) != 1 || repo.params[0].RowLimit != maxBulkProducts+1 {
+		t.Fatalf("expected the filter to be capped at %d rows, got %+v", maxBulkProducts+1, repo.params)
+	}
+	if repo.updated {
+		t.Fatal("too broad filter must not reach the bulk update")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected the transaction to be rolled back: %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/cache"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+)
+
+const (
+	allProductsCacheKey = "all_products"
+
+	// Tag list yang bisa berubah isinya saat produk apa pun dibuat atau diubah
+	tagAllProducts   = "products:all"
+	tagProductSearch = "products:search"
+
+	invalidationTimeout = 5 * time.Second
+
+	maxWarmupProducts = 1000
+	// skor trending 7 hari mencakup view, add-to-cart dan pembelian, dan tidak terlalu terpengaruh lonjakan sesaat
+	warmupTrendingWindow = "7d"
+	cacheTagFamily       = "tags"
+)
+
+// productCacheFamilies memetakan family cache ke pola SCAN-nya. Family "tags" adalah indeks tag milik cache.
+var productCacheFamilies = []struct {
+	name    string
+	pattern string
+}{
+	{metrics.CacheFamilyProduct, "product:*"},
+	{metrics.CacheFamilyProductsBySeller, "products_by_seller:*"},
+	{metrics.CacheFamilyProductsByName, "products_by_name:*"},
+	{metrics.CacheFamilyProductsByType, "products_by_type:*"},
+	{metrics.CacheFamilyAllProducts, allProductsCacheKey},
+	{metrics.CacheFamilyRelatedProducts, "related_products:*"},
+	{cacheTagFamily, "cache_tag:*"},
+}
+
+func productCacheKey(id uuid.UUID) string {
+	return fmt.Sprintf("product:%s", id)
+}
+
+func sellerProductsCacheKey(sellerID uuid.UUID) string {
+	return fmt.Sprintf("products_by_seller:%s", sellerID)
+}
+
+func typeProductsCacheKey(productType string) string {
+	return fmt.Sprintf("products_by_type:%s", productType)
+}
+
+func nameProductsCacheKey(name string) string {
+	return fmt.Sprintf("products_by_name:%s", name)
+}
+
+func relatedProductsCacheKey(id uuid.UUID) string {
+	return fmt.Sprintf("related_products:%s", id)
+}
+
+func productTag(id uuid.UUID) string {
+	return fmt.Sprintf("product:%s", id)
+}
+
+func sellerTag(sellerID uuid.UUID) string {
+	return fmt.Sprintf("seller:%s", sellerID)
+}
+
+func typeTag(productType string) string {
+	return fmt.Sprintf("type:%s", productType)
+}
+
+// productListTags menandai list dengan tag list-nya sendiri dan tag setiap produk di dalamnya,
+// sehingga perubahan satu produk menghapus semua list yang memuat produk tersebut
+func productListTags(listTag string) func([]entities.Product) []string {
+	return func(products []entities.Product) []string {
+		return append(productTags(products), listTag)
+	}
+}
+
+// productChangeTags berisi tag yang harus dihapus saat produk dibuat, diubah atau dihapus.
+// Tag seller dan type diperlukan untuk produk baru yang belum tercatat di list mana pun.
+func productChangeTags(id, sellerID uuid.UUID, productType string) []string {
+	tags := []string{productTag(id), sellerTag(sellerID)}
+	if productType != "" {
+		tags = append(tags, typeTag(productType))
+	}
+
+	return tags
+}
+
+func (s *productServiceImpl) invalidateTags(ctx context.Context, tagSet map[string]struct{}) {
+	tags := make([]string, 0, len(tagSet)+2)
+	tags = append(tags, tagAllProducts, tagProductSearch)
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+
+	cacheCtx, cancel := context.WithTimeout(ctx, invalidationTimeout)
+	defer cancel()
+
+	if _, err := s.cache.Invalidate(cacheCtx, tags...); err != nil {
+		s.log.WithError(err).Warn("Failed to invalidate product caches")
+	}
+}
+
+func (s *productServiceImpl) invalidateProductCaches(ctx context.Context, products ...*entities.Product) {
+	tagSet := make(map[string]struct{})
+	for _, p := range products {
+		for _, tag := range productChangeTags(p.ID, p.SellerID, p.Type) {
+			tagSet[tag] = struct{}{}
+		}
+	}
+
+	s.invalidateTags(ctx, tagSet)
+}
+
+func (s *productServiceImpl) InvalidateCachesAfterUpdate(ctx context.Context, updatedProducts []*entities.Product) {
+	s.invalidateProductCaches(ctx, updatedProducts...)
+}
+
+// invalidateBulkCaches memakai kondisi sebelum dan sesudah supaya list type lama juga ikut terhapus
+func (s *productServiceImpl) invalidateBulkCaches(ctx context.Context, before, after []db.Product) {
+	tagSet := make(map[string]struct{})
+	for _, products := range [][]db.Product{before, after} {
+		for _, p := range products {
+			for _, tag := range productChangeTags(p.ID, p.SellerID, p.Type.String) {
+				tagSet[tag] = struct{}{}
+			}
+		}
+	}
+
+	s.invalidateTags(ctx, tagSet)
+}
+
+func (s *productServiceImpl) ResetAllProductCaches(ctx context.Context) error {
+	s.log.Info("Starting to reset ALL product caches...")
+
+	patterns := make([]string, len(productCacheFamilies))
+	for i, family := range productCacheFamilies {
+		patterns[i] = family.pattern
+	}
+
+	deleted, err := s.cache.DeletePattern(ctx, patterns...)
+	if err != nil {
+		s.log.Errorf("Failed to reset product caches after deleting %d keys: %v", deleted, err)
+		return err
+	}
+
+	s.log.Infof("Successfully reset %d product cache keys.", deleted)
+	return nil
+}
+
+func (s *productServiceImpl) GetCacheStats(ctx context.Context) ([]entities.CacheFamilyStats, error) {
+	stats := make([]entities.CacheFamilyStats, 0, len(productCacheFamilies))
+	for _, family := range productCacheFamilies {
+		keys, memoryBytes, err := s.cache.Stats(ctx, family.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to collect stats for cache family %s: %w", family.name, err)
+		}
+
+		stats = append(stats, entities.CacheFamilyStats{
+			Family:      family.name,
+			Keys:        keys,
+			MemoryBytes: memoryBytes,
+		})
+	}
+
+	return stats, nil
+}
+
+// PurgeProductCaches menghapus cache untuk satu family, seller, type atau produk dan mengembalikan jumlah key yang dihapus.
+// Purge seller/type juga menghapus cache setiap produknya beserta semua list yang memuat produk tersebut.
+func (s *productServiceImpl) PurgeProductCaches(ctx context.Context, target entities.CachePurgeTarget) (int, error) {
+	set := 0
+	for _, ok := range []bool{target.Family != "", target.SellerID != uuid.Nil, target.Type != "", target.ProductID != uuid.Nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return 0, apperrors.ErrInvalidCacheTarget
+	}
+
+	logger := s.log.WithFields(logrus.Fields{
+		"family":     target.Family,
+		"seller_id":  target.SellerID,
+		"type":       target.Type,
+		"product_id": target.ProductID,
+	})
+
+	var (
+		deleted int
+		err     error
+	)
+	switch {
+	case target.Family != "":
+		deleted, err = s.purgeCacheFamily(ctx, target.Family)
+
+	case target.SellerID != uuid.Nil:
+		var rows []db.GetProductsBySellerIDRow
+		rows, err = s.productRepo.GetProductsBySellerID(ctx, target.SellerID)
+		if err == nil {
+			deleted, err = s.cache.Invalidate(ctx, append(productTags(toDomainProducts(rows)), sellerTag(target.SellerID))...)
+		}
+
+	case target.Type != "":
+		var rows []db.GetProductsByTypeRow
+		rows, err = s.productRepo.GetProductsByType(ctx, target.Type)
+		if err == nil {
+			deleted, err = s.cache.Invalidate(ctx, append(productTags(toDomainProducts(rows)), typeTag(target.Type))...)
+		}
+
+	default:
+		deleted, err = s.cache.Invalidate(ctx, productTag(target.ProductID))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to purge product caches: %w", err)
+	}
+
+	logger.Infof("Purged %d product cache keys", deleted)
+	return deleted, nil
+}
+
+func (s *productServiceImpl) purgeCacheFamily(ctx context.Context, name string) (int, error) {
+	for _, family := range productCacheFamilies {
+		if family.name == name {
+			return s.cache.DeletePattern(ctx, family.pattern)
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", apperrors.ErrUnknownCacheFamily, name)
+}
+
+// WarmProductCaches mengisi cache untuk katalog seller, atau untuk list semua produk dan TopN produk yang
+// paling banyak dilihat/dibeli menurut skor trending. Mengembalikan jumlah produk yang dimasukkan ke cache.
+func (s *productServiceImpl) WarmProductCaches(ctx context.Context, target entities.CacheWarmTarget) (int, error) {
+	var products []entities.Product
+
+	if target.SellerID != uuid.Nil {
+		sellerProducts, err := s.GetProductsBySellerID(ctx, target.SellerID)
+		if err != nil {
+			return 0, err
+		}
+		products = sellerProducts
+	} else {
+		if target.TopN <= 0 || target.TopN > maxWarmupProducts {
+			return 0, fmt.Errorf("%w: top_n must be between 1 and %d", apperrors.ErrInvalidRequestPayload, maxWarmupProducts)
+		}
+
+		if _, err := s.GetAllProducts(ctx); err != nil {
+			return 0, err
+		}
+
+		topProducts, err := s.getTopProductsForWarmup(ctx, target.TopN)
+		if err != nil {
+			return 0, err
+		}
+		products = topProducts
+	}
+
+	entries := make([]cache.Entry[entities.Product], len(products))
+	for i, product := range products {
+		entries[i] = cache.Entry[entities.Product]{
+			Key:   productCacheKey(product.ID),
+			Value: product,
+			Tags:  []string{productTag(product.ID)},
+		}
+	}
+	cache.SetMany(ctx, s.cache, entries)
+
+	s.log.WithFields(logrus.Fields{
+		"seller_id": target.SellerID,
+		"top_n":     target.TopN,
+	}).Infof("Warmed %d product cache entries", len(products))
+
+	return len(products), nil
+}
+
+// getTopProductsForWarmup mengutamakan produk dengan skor trending tertinggi. Skor disimpan di Redis, jadi setelah
+// flush (kasus utama warm-up saat startup) skor bisa kosong; sisa slot diisi produk yang paling baru diperbarui.
+func (s *productServiceImpl) getTopProductsForWarmup(ctx context.Context, topN int) ([]entities.Product, error) {
+	products := make([]entities.Product, 0, topN)
+	seen := make(map[uuid.UUID]struct{}, topN)
+
+	scores, err := s.trendingRepo.GetTop(ctx, warmupTrendingWindow, "", topN)
+	if err != nil {
+		s.log.WithError(err).Warn("Failed to read trending scores for cache warm-up, using recently updated products")
+	}
+
+	if len(scores) > 0 {
+		ids := make([]uuid.UUID, len(scores))
+		for i, score := range scores {
+			ids[i] = score.ProductID
+		}
+
+		rows, err := s.productRepo.GetProductByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to retrieve trending products for cache warm-up: %w", err)
+		}
+		for _, product := range toDomainProducts(rows) {
+			products = append(products, product)
+			seen[product.ID] = struct{}{}
+		}
+	}
+
+	if len(products) < topN {
+		rows, err := s.productRepo.GetRecentlyUpdatedProducts(ctx, int32(topN))
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to retrieve products for cache warm-up: %w", err)
+		}
+		for _, product := range toDomainProducts(rows) {
+			if len(products) == topN {
+				break
+			}
+			if _, ok := seen[product.ID]; ok {
+				continue
+			}
+			products = append(products, product)
+		}
+	}
+
+	return products, nil
+}
+
+func productTags(products []entities.Product) []string {
+	tags := make([]string, len(products))
+	for i, p := range products {
+		tags[i] = productTag(p.ID)
+	}
+
+	return tags
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+type fakeWarmupProductRepo struct {
+	repositories.ProductRepository
+
+	recent []uuid.UUID
+}
+
+func (f *fakeWarmupProductRepo) GetProductByIDs(ctx context.Context, ids []uuid.UUID) ([]db.GetProductByIDsRow, error) {
+	rows := make([]db.GetProductByIDsRow, len(ids))
+	for i, id := range ids {
+		rows[i] = db.GetProductByIDsRow{ID: id}
+	}
+	return rows, nil
+}
+
+func (f *fakeWarmupProductRepo) GetRecentlyUpdatedProducts(ctx context.Context, limit int32) ([]db.GetRecentlyUpdatedProductsRow, error) {
+	rows := make([]db.GetRecentlyUpdatedProductsRow, 0, limit)
+	for _, id := range f.recent {
+		if len(rows) == int(limit) {
+			break
+		}
+		rows = append(rows, db.GetRecentlyUpdatedProductsRow{ID: id})
+	}
+	return rows, nil
+}
+
+type fakeWarmupTrendingRepo struct {
+	repositories.TrendingRepository
+
+	top    []uuid.UUID
+	err    error
+	window string
+}
+
+func (f *fakeWarmupTrendingRepo) GetTop(ctx context.Context, window, productType string, limit int) ([]entities.TrendingScore, error) {
+	f.window = window
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	scores := make([]entities.TrendingScore, 0, limit)
+	for i, id := range f.top {
+		if i == limit {
+			break
+		}
+		scores = append(scores, entities.TrendingScore{ProductID: id, Score: float64(len(f.top) - i)})
+	}
+	return scores, nil
+}
+
+func TestGetTopProductsForWarmup(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		trending *fakeWarmupTrendingRepo
+		recent   []uuid.UUID
+		topN     int
+		want     []uuid.UUID
+	}{
+		{"trending fills every slot", &fakeWarmupTrendingRepo{top: []uuid.UUID{a, b, c}}, []uuid.UUID{d}, 2, []uuid.UUID{a, b}},
+		{"recently updated tops up without duplicates", &fakeWarmupTrendingRepo{top: []uuid.UUID{a}}, []uuid.UUID{a, b, c, d}, 3, []uuid.UUID{a, b, c}},
+		{"no trending scores after a redis flush", &fakeWarmupTrendingRepo{}, []uuid.UUID{c, d}, 5, []uuid.UUID{c, d}},
+		{"trending read failure falls back", &fakeWarmupTrendingRepo{top: []uuid.UUID{a}, err: errors.New("redis down")}, []uuid.UUID{d}, 1, []uuid.UUID{d}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			svc := NewProductService(&fakeWarmupProductRepo{recent: tt.recent}, nil, nil, tt.trending, nil, nil, nil, nil, log).(*productServiceImpl)
+
+			products, err := svc.getTopProductsForWarmup(context.Background(), tt.topN)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.trending.window != warmupTrendingWindow {
+				t.Fatalf("expected trending window %s, got %q", warmupTrendingWindow, tt.trending.window)
+			}
+
+			if len(products) != len(tt.want) {
+				t.Fatalf("expected %d products, got %d", len(tt.want), len(products))
+			}
+			for i, product := range products {
+				if product.ID != tt.want[i] {
+					t.Fatalf("expected product %d to be %s, got %s", i, tt.want[i], product.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+const (
+	productChangeBatchSize      = 200
+	productChangePruneBatchSize = 5000
+
+	// Baris dihapus setelah retention + grace, sedangkan token ditolak tepat setelah retention. Perubahan dari transaksi
+	// yang lebih lama bisa punya created_at sedikit lebih awal dari perubahan sebelumnya di feed; grace ini menjaga
+	// perubahan tersebut tetap ada selama token sebelum posisinya masih diterima.
+	productChangeRetentionGrace = time.Hour
+)
+
+type ProductChangeService interface {
+	// Watch memanggil send untuk setiap perubahan setelah resumeToken sampai ctx selesai atau send gagal.
+	// resumeToken kosong berarti mulai dari perubahan berikutnya. Feed hanya memuat produk active, lihat toVisibleChange.
+	Watch(ctx context.Context, resumeToken string, filter entities.ProductChangeFilter, send func(change *entities.ProductChange, resumeToken string) error) error
+	// PruneExpired menghapus change log yang lebih tua dari retention dan mengembalikan jumlah baris yang dihapus
+	PruneExpired(ctx context.Context) (int64, error)
+	RunRetention(ctx context.Context, interval time.Duration)
+}
+
+type productChangeServiceImpl struct {
+	changeRepo   repositories.ProductChangeRepository
+	pollInterval time.Duration
+	retention    time.Duration
+	log          *logrus.Logger
+}
+
+func NewProductChangeService(changeRepo repositories.ProductChangeRepository, pollInterval, retention time.Duration, log *logrus.Logger) ProductChangeService {
+	return &productChangeServiceImpl{
+		changeRepo:   changeRepo,
+		pollInterval: pollInterval,
+		retention:    retention,
+		log:          log,
+	}
+}
+
+func (s *productChangeServiceImpl) Watch(ctx context.Context, resumeToken string, filter entities.ProductChangeFilter, send func(change *entities.ProductChange, resumeToken string) error) error {
+	position, err := s.resolvePosition(ctx, resumeToken)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := s.changeRepo.ListChangesAfter(ctx, position, filter, productChangeBatchSize)
+		if err != nil {
+			return fmt.Errorf("service: failed to read product changes: %w", err)
+		}
+
+		for i := range changes {
+			position = changes[i].Position
+
+			change, ok := toVisibleChange(changes[i])
+			if !ok {
+				continue
+			}
+			if err := send(&change, encodeResumeToken(change.Position, change.OccurredAt)); err != nil {
+				return err
+			}
+		}
+
+		// Batch penuh berarti masih ada backlog, langsung baca lagi tanpa menunggu
+		if len(changes) == productChangeBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// toVisibleChange menyesuaikan perubahan untuk consumer feed yang hanya boleh melihat produk active.
+// Produk yang dibuat sebagai non-active tidak dikirim; perubahan lain pada produk non-active (mis. diarsipkan
+// atau dibanned) dikirim sebagai deleted supaya consumer menurunkannya. Produk yang kembali active datang
+// sebagai updated, jadi consumer harus memperlakukan updated sebagai upsert.
+func toVisibleChange(change entities.ProductChange) (entities.ProductChange, bool) {
+	if change.Product.IsActive() || change.Type == entities.ProductChangeDeleted {
+		return change, true
+	}
+	if change.Type == entities.ProductChangeCreated {
+		return entities.ProductChange{}, false
+	}
+
+	change.Type = entities.ProductChangeDeleted
+	return change, true
+}
+
+func (s *productChangeServiceImpl) PruneExpired(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-s.retention - productChangeRetentionGrace)
+
+	var total int64
+	for {
+		deleted, err := s.changeRepo.DeleteChangesBefore(ctx, cutoff, productChangePruneBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("service: failed to prune product changes: %w", err)
+		}
+		total += deleted
+
+		if deleted < productChangePruneBatchSize || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
+
+func (s *productChangeServiceImpl) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.PruneExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.log.WithError(err).Warn("Failed to prune product change log")
+		}
+		if deleted > 0 {
+			s.log.WithField("deleted", deleted).Info("Pruned expired product changes")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *productChangeServiceImpl) resolvePosition(ctx context.Context, resumeToken string) (entities.ProductChangePosition, error) {
+	if resumeToken != "" {
+		position, occurredAt, err := decodeResumeToken(resumeToken)
+		if err != nil {
+			return entities.ProductChangePosition{}, err
+		}
+
+		// Perubahan setelah token ini mungkin sudah dihapus job retensi, jadi stream tidak bisa dijamin lengkap
+		if time.Since(occurredAt) > s.retention {
+			return entities.ProductChangePosition{}, fmt.Errorf("%w (retention %s)", apperrors.ErrResumeTokenExpired, s.retention)
+		}
+
+		return position, nil
+	}
+
+	// Mulai dari transaksi yang belum final; perubahan yang sudah final sebelum watch dimulai dilewati
+	xmin, err := s.changeRepo.GetWatermark(ctx)
+	if err != nil {
+		return entities.ProductChangePosition{}, fmt.Errorf("service: failed to resolve watch start position: %w", err)
+	}
+
+	return entities.ProductChangePosition{TxID: xmin, ID: 0}, nil
+}
+
+// Resume token berisi posisi (tx_id, id) dan waktu perubahan (mikrodetik) untuk memeriksa retensi
+func encodeResumeToken(position entities.ProductChangePosition, occurredAt time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%d", position.TxID, position.ID, occurredAt.UnixMicro())))
+}
+
+func decodeResumeToken(token string) (entities.ProductChangePosition, time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return entities.ProductChangePosition{}, time.Time{}, apperrors.ErrInvalidResumeToken
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return entities.ProductChangePosition{}, time.Time{}, apperrors.ErrInvalidResumeToken
+	}
+
+	txID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return entities.ProductChangePosition{}, time.Time{}, apperrors.ErrInvalidResumeToken
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return entities.ProductChangePosition{}, time.Time{}, apperrors.ErrInvalidResumeToken
+	}
+	micros, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return entities.ProductChangePosition{}, time.Time{}, apperrors.ErrInvalidResumeToken
+	}
+
+	return entities.ProductChangePosition{TxID: txID, ID: id}, time.UnixMicro(micros), nil
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+type fakeProductChangeRepo struct {
+	watermark    int64
+	watermarkErr error
+	changes      []entities.ProductChange
+
+	listedAfter []entities.ProductChangePosition
+
+	pruneBatches []int64
+	cutoffs      []time.Time
+}
+
+func (f *fakeProductChangeRepo) ListChangesAfter(ctx context.Context, after entities.ProductChangePosition, filter entities.ProductChangeFilter, limit int32) ([]entities.ProductChange, error) {
+	f.listedAfter = append(f.listedAfter, after)
+
+	var res []entities.ProductChange
+	for _, c := range f.changes {
+		if c.Position.TxID > after.TxID || (c.Position.TxID == after.TxID && c.Position.ID > after.ID) {
+			res = append(res, c)
+		}
+		if len(res) == int(limit) {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (f *fakeProductChangeRepo) GetWatermark(ctx context.Context) (int64, error) {
+	return f.watermark, f.watermarkErr
+}
+
+func (f *fakeProductChangeRepo) DeleteChangesBefore(ctx context.Context, cutoff time.Time, limit int32) (int64, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	if len(f.pruneBatches) == 0 {
+		return 0, nil
+	}
+
+	deleted := f.pruneBatches[0]
+	f.pruneBatches = f.pruneBatches[1:]
+	return deleted, nil
+}
+
+func newTestChangeService(repo *fakeProductChangeRepo, retention time.Duration) *productChangeServiceImpl {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return NewProductChangeService(repo, time.Millisecond, retention, log).(*productChangeServiceImpl)
+}
+
+var errStopWatch = errors.New("stop watch")
+
+// collectChanges menjalankan Watch sampai n perubahan terkirim
+func collectChanges(t *testing.T, svc ProductChangeService, resumeToken string, n int) ([]entities.ProductChange, []string, error) {
+	t.Helper()
+
+	var changes []entities.ProductChange
+	var tokens []string
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := svc.Watch(ctx, resumeToken, entities.ProductChangeFilter{}, func(change *entities.ProductChange, token string) error {
+		changes = append(changes, *change)
+		tokens = append(tokens, token)
+		if len(changes) == n {
+			return errStopWatch
+		}
+		return nil
+	})
+	if errors.Is(err, errStopWatch) {
+		err = nil
+	}
+
+	return changes, tokens, err
+}
+
+func TestResumeTokenRoundTrip(t *testing.T) {
+	occurredAt := time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.UTC)
+	position := entities.ProductChangePosition{TxID: 987654321, ID: 42}
+
+	gotPosition, gotTime, err := decodeResumeToken(encodeResumeToken(position, occurredAt))
+	if err != nil {
+		t.Fatalf("expected token to decode, got %v", err)
+	}
+	if gotPosition != position {
+		t.Fatalf("expected position %+v, got %+v", position, gotPosition)
+	}
+	if !gotTime.Equal(occurredAt) {
+		t.Fatalf("expected occurred at %v, got %v", occurredAt, gotTime)
+	}
+}
+
+func TestDecodeResumeTokenRejectsMalformedTokens(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1:2:3"))},
+		{"legacy two part token", encode("1:2")},
+		{"too many parts", encode("1:2:3:4")},
+		{"non numeric tx id", encode("a:2:3")},
+		{"non numeric id", encode("1:b:3")},
+		{"non numeric time", encode("1:2:c")},
+		{"empty parts", encode("::")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeResumeToken(tt.token); !errors.Is(err, apperrors.ErrInvalidResumeToken) {
+				t.Fatalf("expected %v, got %v", apperrors.ErrInvalidResumeToken, err)
+			}
+		})
+	}
+}
+
+func TestWatchWithoutTokenStartsAtWatermark(t *testing.T) {
+	now := time.Now()
+	repo := &fakeProductChangeRepo{
+		watermark: 100,
+		changes: []entities.ProductChange{
+			// Transaksi yang sudah final sebelum watch dimulai tidak dikirim
+			{Position: entities.ProductChangePosition{TxID: 99, ID: 1}, OccurredAt: now},
+			{Position: entities.ProductChangePosition{TxID: 100, ID: 2}, OccurredAt: now},
+			{Position: entities.ProductChangePosition{TxID: 101, ID: 3}, OccurredAt: now},
+		},
+	}
+
+	changes, _, err := collectChanges(t, newTestChangeService(repo, time.Hour), "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := repo.listedAfter[0]; got != (entities.ProductChangePosition{TxID: 100, ID: 0}) {
+		t.Fatalf("expected first read after (100, 0), got %+v", got)
+	}
+	if len(changes) != 2 || changes[0].Position.ID != 2 || changes[1].Position.ID != 3 {
+		t.Fatalf("expected changes 2 and 3, got %+v", changes)
+	}
+}
+
+func TestWatchWatermarkError(t *testing.T) {
+	repoErr := errors.New("db down")
+	repo := &fakeProductChangeRepo{watermarkErr: repoErr}
+
+	_, _, err := collectChanges(t, newTestChangeService(repo, time.Hour), "", 1)
+	if !errors.Is(err, repoErr) {
+		t.Fatalf("expected watermark error, got %v", err)
+	}
+}
+
+func TestWatchResumesAfterToken(t *testing.T) {
+	now := time.Now()
+	repo := &fakeProductChangeRepo{
+		watermark: 500,
+		changes: []entities.ProductChange{
+			{Position: entities.ProductChangePosition{TxID: 10, ID: 1}, OccurredAt: now},
+			{Position: entities.ProductChangePosition{TxID: 10, ID: 2}, OccurredAt: now},
+			{Position: entities.ProductChangePosition{TxID: 11, ID: 3}, OccurredAt: now},
+		},
+	}
+	svc := newTestChangeService(repo, time.Hour)
+
+	first, tokens, err := collectChanges(t, svc, encodeResumeToken(entities.ProductChangePosition{TxID: 1, ID: 0}, now), 1)
+	if err != nil || len(first) != 1 || first[0].Position.ID != 1 {
+		t.Fatalf("expected first change, got %+v (err %v)", first, err)
+	}
+
+	// Reconnect dengan token terakhir melanjutkan tepat setelah posisi itu, tanpa melihat watermark
+	rest, _, err := collectChanges(t, svc, tokens[0], 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rest) != 2 || rest[0].Position.ID != 2 || rest[1].Position.ID != 3 {
+		t.Fatalf("expected changes 2 and 3 after resume, got %+v", rest)
+	}
+	if got := repo.listedAfter[len(repo.listedAfter)-1]; got.TxID == 500 {
+		t.Fatalf("resume must not start from watermark, got %+v", got)
+	}
+}
+
+func TestWatchRejectsTokenOlderThanRetention(t *testing.T) {
+	repo := &fakeProductChangeRepo{watermark: 1}
+	svc := newTestChangeService(repo, 24*time.Hour)
+	position := entities.ProductChangePosition{TxID: 5, ID: 5}
+
+	_, _, err := collectChanges(t, svc, encodeResumeToken(position, time.Now().Add(-25*time.Hour)), 1)
+	if !errors.Is(err, apperrors.ErrResumeTokenExpired) {
+		t.Fatalf("expected %v, got %v", apperrors.ErrResumeTokenExpired, err)
+	}
+	if len(repo.listedAfter) != 0 {
+		t.Fatal("expired token must be rejected before reading the change log")
+	}
+
+	if _, err := svc.resolvePosition(context.Background(), encodeResumeToken(position, time.Now().Add(-23*time.Hour))); err != nil {
+		t.Fatalf("expected token within retention to be accepted, got %v", err)
+	}
+}
+
+func TestPruneExpiredDeletesInBatchesWithGrace(t *testing.T) {
+	repo := &fakeProductChangeRepo{pruneBatches: []int64{productChangePruneBatchSize, productChangePruneBatchSize, 7}}
+	svc := newTestChangeService(repo, 24*time.Hour)
+
+	before := time.Now()
+	deleted, err := svc.PruneExpired(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 2*productChangePruneBatchSize+7 {
+		t.Fatalf("expected %d deleted, got %d", 2*productChangePruneBatchSize+7, deleted)
+	}
+	if len(repo.cutoffs) != 3 {
+		t.Fatalf("expected 3 delete batches, got %d", len(repo.cutoffs))
+	}
+
+	// Baris baru dihapus setelah token yang menunjuk ke sana ditolak
+	wantCutoff := before.Add(-24*time.Hour - productChangeRetentionGrace)
+	if diff := repo.cutoffs[0].Sub(wantCutoff); diff < 0 || diff > time.Second {
+		t.Fatalf("expected cutoff near %v, got %v", wantCutoff, repo.cutoffs[0])
+	}
+}
+
+func TestWatchHidesInactiveProducts(t *testing.T) {
+	now := time.Now()
+	change := func(id int64, kind string, status entities.ProductStatus) entities.ProductChange {
+		return entities.ProductChange{
+			Position:   entities.ProductChangePosition{TxID: 10, ID: id},
+			Type:       kind,
+			Product:    entities.Product{Status: status},
+			OccurredAt: now,
+		}
+	}
+
+	repo := &fakeProductChangeRepo{
+		changes: []entities.ProductChange{
+			change(1, entities.ProductChangeCreated, entities.ProductStatusDraft),
+			change(2, entities.ProductChangeUpdated, entities.ProductStatusDraft),
+			change(3, entities.ProductChangeUpdated, entities.ProductStatusActive),
+			change(4, entities.ProductChangeStockChanged, entities.ProductStatusBanned),
+			change(5, entities.ProductChangeUpdated, entities.ProductStatusArchived),
+			change(6, entities.ProductChangeDeleted, entities.ProductStatusDraft),
+			change(7, entities.ProductChangeCreated, ""),
+		},
+	}
+	svc := newTestChangeService(repo, time.Hour)
+
+	got, tokens, err := collectChanges(t, svc, encodeResumeToken(entities.ProductChangePosition{TxID: 1}, now), 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		id   int64
+		kind string
+	}{
+		{2, entities.ProductChangeDeleted},
+		{3, entities.ProductChangeUpdated},
+		{4, entities.ProductChangeDeleted},
+		{5, entities.ProductChangeDeleted},
+		{6, entities.ProductChangeDeleted},
+		{7, entities.ProductChangeCreated},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Position.ID != w.id || got[i].Type != w.kind {
+			t.Errorf("change %d: expected id %d as %s, got id %d as %s", i, w.id, w.kind, got[i].Position.ID, got[i].Type)
+		}
+	}
+
+	// Token perubahan pertama yang dikirim tetap menunjuk ke posisinya sendiri, bukan ke draft yang dilewati
+	position, _, err := decodeResumeToken(tokens[0])
+	if err != nil || position.ID != 2 {
+		t.Fatalf("expected first token at id 2, got %+v (err %v)", position, err)
+	}
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+)
+
+const (
+	maxImportSize = 10 << 20 // 10 MB
+
+	// progress job disimpan ke Redis setiap sekian baris agar seller bisa memantau import yang besar
+	importProgressInterval = 100
+)
+
+var productCSVHeader = []string{"external_sku", "name", "price", "stock", "discount", "type", "description"}
+
+type importRow struct {
+	line int
+	req  models.ProductRequest
+	err  error
+}
+
+type productExportRecord struct {
+	ID          string `json:"id"`
+	ExternalSKU string `json:"external_sku"`
+	Name        string `json:"name"`
+	Price       int    `json:"price"`
+	Stock       int    `json:"stock"`
+	Discount    int    `json:"discount"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+func (s *productServiceImpl) ImportProducts(ctx context.Context, subject policies.Subject, format string, r io.Reader) (*entities.ProductImportJob, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionCreateProduct, policies.Resource{SellerID: subject.UserID}); err != nil {
+		return nil, err
+	}
+
+	if format != entities.ImportFormatCSV && format != entities.ImportFormatNDJSON {
+		return nil, apperrors.ErrUnsupportedImportFormat
+	}
+
+	// Body request sudah ditutup saat handler selesai, jadi isinya dibaca penuh sebelum diproses di background
+	data, err := io.ReadAll(io.LimitReader(r, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to read import payload: %w", err)
+	}
+	if len(data) > maxImportSize {
+		return nil, apperrors.ErrImportTooLarge
+	}
+
+	job := &entities.ProductImportJob{
+		ID:        helpers.GenerateNewID(),
+		SellerID:  subject.UserID,
+		Format:    format,
+		Status:    entities.ImportStatusPending,
+		Errors:    []entities.ProductImportRowError{},
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.importRepo.SaveJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("service: failed to create import job: %w", err)
+	}
+
+	s.tasks.Go(func(ctx context.Context) {
+		s.runImport(ctx, job, data)
+	})
+
+	return job, nil
+}
+
+func (s *productServiceImpl) GetImportJob(ctx context.Context, jobID uuid.UUID, subject policies.Subject) (*entities.ProductImportJob, error) {
+	job, err := s.importRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// job milik seller lain diperlakukan seperti tidak ada
+	if subject.Role != policies.RoleAdmin && job.SellerID != subject.UserID {
+		return nil, apperrors.ErrImportJobNotFound
+	}
+
+	return job, nil
+}
+
+func (s *productServiceImpl) ExportProducts(ctx context.Context, sellerID uuid.UUID, format string, w io.Writer) error {
+	if format != entities.ImportFormatCSV && format != entities.ImportFormatNDJSON {
+		return apperrors.ErrUnsupportedImportFormat
+	}
+
+	// export dibaca langsung dari database agar tidak tertinggal dari cache
+	dbProducts, err := s.productRepo.GetProductsBySellerID(ctx, sellerID)
+	if err != nil {
+		return fmt.Errorf("service: failed to retrieve products for export: %w", err)
+	}
+
+	products := toDomainProducts(dbProducts)
+
+	if format == entities.ImportFormatNDJSON {
+		encoder := json.NewEncoder(w)
+		for _, p := range products {
+			if err := encoder.Encode(toProductExportRecord(&p)); err != nil {
+				return fmt.Errorf("service: failed to write export row: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"id"}, productCSVHeader...)); err != nil {
+		return fmt.Errorf("service: failed to write export header: %w", err)
+	}
+
+	for _, p := range products {
+		record := toProductExportRecord(&p)
+		row := []string{
+			record.ID,
+			record.ExternalSKU,
+			record.Name,
+			strconv.Itoa(record.Price),
+			strconv.Itoa(record.Stock),
+			strconv.Itoa(record.Discount),
+			record.Type,
+			record.Description,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("service: failed to write export row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *productServiceImpl) runImport(ctx context.Context, job *entities.ProductImportJob, data []byte) {
+	logger := s.log.WithFields(logrus.Fields{
+		"job_id":    job.ID,
+		"seller_id": job.SellerID,
+		"format":    job.Format,
+	})
+	logger.Info("Starting product import job")
+
+	job.Status = entities.ImportStatusRunning
+	s.saveImportJob(ctx, job)
+
+	rows, err := parseImportRows(job.Format, data)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to parse import file")
+		job.Errors = append(job.Errors, entities.ProductImportRowError{Message: err.Error()})
+		s.finishImportJob(ctx, job, entities.ImportStatusFailed)
+		return
+	}
+
+	job.TotalRows = len(rows)
+	importedProducts := make([]*entities.Product, 0, len(rows))
+
+	for i, row := range rows {
+		product, err := s.importRow(ctx, job.SellerID, row)
+		if err != nil {
+			job.Failed++
+			job.Errors = append(job.Errors, entities.ProductImportRowError{
+				Row:         row.line,
+				ExternalSKU: row.req.ExternalSKU,
+				Message:     err.Error(),
+			})
+		} else {
+			job.Succeeded++
+			importedProducts = append(importedProducts, product)
+		}
+
+		if (i+1)%importProgressInterval == 0 {
+			s.saveImportJob(ctx, job)
+		}
+	}
+
+	if len(importedProducts) > 0 {
+		s.InvalidateCachesAfterUpdate(ctx, importedProducts)
+	}
+
+	s.finishImportJob(ctx, job, entities.ImportStatusCompleted)
+	logger.WithFields(logrus.Fields{
+		"total_rows": job.TotalRows,
+		"succeeded":  job.Succeeded,
+		"failed":     job.Failed,
+	}).Info("Product import job finished")
+}
+
+func (s *productServiceImpl) importRow(ctx context.Context, sellerID uuid.UUID, row importRow) (*entities.Product, error) {
+	if row.err != nil {
+		return nil, row.err
+	}
+
+	if strings.TrimSpace(row.req.ExternalSKU) == "" {
+		return nil, fmt.Errorf("%w: Field 'ExternalSKU' failed on the 'required' tag", apperrors.ErrInvalidRequestPayload)
+	}
+
+	if err := s.validateRequest(&row.req); err != nil {
+		return nil, err
+	}
+
+	// Sama seperti UpdateProduct: baris tanpa atribut mempertahankan atribut lama, yang tetap harus cocok dengan type baris ini
+	attrs := row.req.Attributes
+	if attrs == nil {
+		existing, err := s.productRepo.GetProductAttributesBySKU(ctx, sellerID, row.req.ExternalSKU)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to load existing product: %w", err)
+		}
+		attrs = helpers.ConvertJSONMap(reflect.ValueOf(existing))
+	}
+	attributes, err := s.resolveAttributes(ctx, row.req.Type, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	dbProduct, err := s.productRepo.UpsertProductBySKU(ctx, &db.UpsertProductBySKUParams{
+		ID:          helpers.GenerateNewID(),
+		SellerID:    sellerID,
+		ExternalSku: helpers.OptionalStringToNullString(row.req.ExternalSKU),
+		Name:        row.req.Name,
+		Price:       int32(row.req.Price),
+		Stock:       int32(row.req.Stock),
+		Discount:    helpers.IntToNullInt32(row.req.Discount),
+		Type:        helpers.StringToNullString(row.req.Type),
+		Description: helpers.StringToNullString(row.req.Description),
+		Attributes:  attributes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save product: %w", err)
+	}
+
+	return toDomainProduct(dbProduct), nil
+}
+
+func (s *productServiceImpl) saveImportJob(ctx context.Context, job *entities.ProductImportJob) {
+	if err := s.importRepo.SaveJob(ctx, job); err != nil {
+		s.log.WithField("job_id", job.ID).WithError(err).Warn("Failed to save import job progress")
+	}
+}
+
+func (s *productServiceImpl) finishImportJob(ctx context.Context, job *entities.ProductImportJob, status string) {
+	finishedAt := time.Now()
+	job.Status = status
+	job.FinishedAt = &finishedAt
+	s.saveImportJob(ctx, job)
+}
+
+// ------- HELPERS -------
+
+func parseImportRows(format string, data []byte) ([]importRow, error) {
+	if format == entities.ImportFormatNDJSON {
+		return parseNDJSONRows(data)
+	}
+
+	return parseCSVRows(data)
+}
+
+func parseNDJSONRows(data []byte) ([]importRow, error) {
+	var rows []importRow
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := importRow{line: line}
+		if err := json.Unmarshal([]byte(text), &row.req); err != nil {
+			row.err = fmt.Errorf("%w: malformed JSON: %s", apperrors.ErrInvalidRequestPayload, err)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+
+	return rows, nil
+}
+
+func parseCSVRows(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"external_sku", "name", "price", "stock", "type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing required column '%s'", required)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			// FieldPos hanya valid setelah Read berhasil, jadi baris diambil dari ParseError
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+
+			line := parseErr.StartLine
+			if line == 0 {
+				line = parseErr.Line
+			}
+			rows = append(rows, importRow{
+				line: line,
+				err:  fmt.Errorf("%w: malformed CSV row: %s", apperrors.ErrInvalidRequestPayload, err),
+			})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line}
+		row.req, row.err = csvRecordToProductRequest(columns, record)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func csvRecordToProductRequest(columns map[string]int, record []string) (models.ProductRequest, error) {
+	field := func(name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	number := func(name string) (int, error) {
+		val := field(name)
+		if val == "" {
+			return 0, nil
+		}
+
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return 0, fmt.Errorf("%w: column '%s' must be an integer", apperrors.ErrInvalidRequestPayload, name)
+		}
+		return n, nil
+	}
+
+	req := models.ProductRequest{
+		ExternalSKU: field("external_sku"),
+		Name:        field("name"),
+		Type:        field("type"),
+		Description: field("description"),
+	}
+
+	var err error
+	if req.Price, err = number("price"); err != nil {
+		return req, err
+	}
+	if req.Stock, err = number("stock"); err != nil {
+		return req, err
+	}
+	if req.Discount, err = number("discount"); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func toProductExportRecord(p *entities.Product) productExportRecord {
+	return productExportRecord{
+		ID:          p.ID.String(),
+		ExternalSKU: p.ExternalSKU,
+		Name:        p.Name,
+		Price:       p.Price,
+		Stock:       p.Stock,
+		Discount:    p.Discount,
+		Type:        p.Type,
+		Description: p.Description,
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+)
+
+// Baris CSV rusak harus dilaporkan per baris tanpa menghentikan parsing baris berikutnya
+func TestParseCSVRowsMalformedRow(t *testing.T) {
+	data := []byte("external_sku,name,price,stock,type\n" +
+		"SKU-1,Keyboard,100000,5,electronics\n" +
+		"SKU-2,Mou\"se,50000,3,electronics\n" +
+		"SKU-3,Monitor,2000000,1,electronics\n")
+
+	rows, err := parseCSVRows(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+
+	if rows[0].err != nil || rows[0].line != 2 || rows[0].req.ExternalSKU != "SKU-1" {
+		t.Fatalf("unexpected first row: %+v", rows[0])
+	}
+	if !errors.Is(rows[1].err, apperrors.ErrInvalidRequestPayload) {
+		t.Fatalf("expected %v for malformed row, got %v", apperrors.ErrInvalidRequestPayload, rows[1].err)
+	}
+	if rows[1].line != 3 {
+		t.Fatalf("expected malformed row on line 3, got %d", rows[1].line)
+	}
+	if rows[2].err != nil || rows[2].line != 4 || rows[2].req.ExternalSKU != "SKU-3" {
+		t.Fatalf("unexpected last row: %+v", rows[2])
+	}
+}
+
+func TestParseCSVRowsUnterminatedQuote(t *testing.T) {
+	data := []byte("external_sku,name,price,stock,type\n" +
+		"SKU-1,Keyboard,100000,5,electronics\n" +
+		"SKU-2,\"Mouse,50000,3,electronics\n")
+
+	rows, err := parseCSVRows(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if !errors.Is(rows[1].err, apperrors.ErrInvalidRequestPayload) || rows[1].line != 3 {
+		t.Fatalf("expected malformed row on line 3, got line %d: %v", rows[1].line, rows[1].err)
+	}
+}
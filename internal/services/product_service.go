@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,8 +20,11 @@ import (
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/background"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/cache"
 	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
-	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
 	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
 
 	productpb "github.com/RehanAthallahAzhar/shopeezy-protos/pb/product"
@@ -26,70 +33,115 @@ import (
 type ProductSource interface {
 	db.Product |
 		db.GetAllProductsRow |
+		db.ListActiveProductsPageRow |
 		db.GetProductsBySellerIDRow |
 		db.GetProductsByNameRow |
 		db.GetProductByIDRow |
 		db.GetProductByIDsRow |
-		db.GetProductsByTypeRow
+		db.GetProductsByTypeRow |
+		db.GetRecentlyUpdatedProductsRow |
+		db.SearchProductsRow |
+		db.SearchProductsByRatingRow
 }
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, userID uuid.UUID, req *models.ProductRequest) (*entities.Product, error)
+	CreateProduct(ctx context.Context, subject policies.Subject, req *models.ProductRequest) (*entities.Product, error)
 	GetAllProducts(ctx context.Context) ([]entities.Product, error)
+	ListProducts(ctx context.Context, pageToken string, pageSize int) (*entities.ProductPage, error)
 	GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]entities.Product, error)
 	GetProductsByName(ctx context.Context, name string) ([]entities.Product, error)
 	GetProductsByType(ctx context.Context, productType string) ([]entities.Product, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
 	GetProductByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.Product, error)
-	UpdateProduct(ctx context.Context, req *models.ProductRequest, productID, sellerID uuid.UUID, role string) (*entities.Product, error)
-	DeleteProduct(ctx context.Context, productID, sellerID uuid.UUID, role string) (*entities.Product, error)
+	UpdateProduct(ctx context.Context, req *models.ProductRequest, productID uuid.UUID, subject policies.Subject) (*entities.Product, error)
+	DeleteProduct(ctx context.Context, productID uuid.UUID, subject policies.Subject) (*entities.Product, error)
+	ChangeProductStatus(ctx context.Context, subject policies.Subject, productID uuid.UUID, req *models.ProductStatusRequest) (*entities.Product, error)
+	GetVisibleProductByID(ctx context.Context, viewer policies.Subject, id uuid.UUID) (*entities.Product, error)
+	GetVisibleProductsBySellerID(ctx context.Context, viewer policies.Subject, sellerID uuid.UUID) ([]entities.Product, error)
+	SearchProducts(ctx context.Context, viewer policies.Subject, query entities.ProductSearchQuery) ([]entities.Product, error)
+	GetAttributeDefinitions(ctx context.Context, productType string) ([]entities.AttributeDefinition, error)
+	UpsertAttributeDefinition(ctx context.Context, subject policies.Subject, productType, key string, req *models.AttributeDefinitionRequest) (*entities.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, subject policies.Subject, productType, key string) error
 	ResetAllProductCaches(ctx context.Context) error
-	DecreaseStock(ctx context.Context, items []*productpb.StockItem) ([]*entities.Product, error)
-	IncreaseStock(ctx context.Context, items []*productpb.StockItem) ([]*entities.Product, error)
+	InvalidateCachesAfterUpdate(ctx context.Context, updatedProducts []*entities.Product)
+	GetCacheStats(ctx context.Context) ([]entities.CacheFamilyStats, error)
+	PurgeProductCaches(ctx context.Context, target entities.CachePurgeTarget) (int, error)
+	WarmProductCaches(ctx context.Context, target entities.CacheWarmTarget) (int, error)
+	DecreaseStock(ctx context.Context, subject policies.Subject, items []*productpb.StockItem) ([]*entities.Product, error)
+	IncreaseStock(ctx context.Context, subject policies.Subject, items []*productpb.StockItem) ([]*entities.Product, error)
+	ImportProducts(ctx context.Context, subject policies.Subject, format string, r io.Reader) (*entities.ProductImportJob, error)
+	GetImportJob(ctx context.Context, jobID uuid.UUID, subject policies.Subject) (*entities.ProductImportJob, error)
+	ExportProducts(ctx context.Context, sellerID uuid.UUID, format string, w io.Writer) error
+	BulkUpdateProducts(ctx context.Context, req *models.BulkProductRequest, subject policies.Subject) ([]entities.Product, error)
 }
 
 type productServiceImpl struct {
-	productRepo repositories.ProductRepository
-	redisClient *redis.RedisClient
-	validator   *validator.Validate
-	log         *logrus.Logger
+	productRepo   repositories.ProductRepository
+	importRepo    repositories.ProductImportRepository
+	attributeRepo repositories.ProductAttributeRepository
+	trendingRepo  repositories.TrendingRepository
+	policy        policies.ProductPolicy
+	cache         *cache.Cache
+	validator     *validator.Validate
+	tasks         *background.Tracker
+	log           *logrus.Logger
 }
 
 func NewProductService(
 	productRepo repositories.ProductRepository,
-	redisClient *redis.RedisClient,
+	importRepo repositories.ProductImportRepository,
+	attributeRepo repositories.ProductAttributeRepository,
+	trendingRepo repositories.TrendingRepository,
+	policy policies.ProductPolicy,
+	cache *cache.Cache,
 	validator *validator.Validate,
+	tasks *background.Tracker,
 	log *logrus.Logger,
 ) ProductService {
 	return &productServiceImpl{
-		productRepo: productRepo,
-		redisClient: redisClient,
-		validator:   validator,
-		log:         log,
+		productRepo:   productRepo,
+		importRepo:    importRepo,
+		attributeRepo: attributeRepo,
+		trendingRepo:  trendingRepo,
+		policy:        policy,
+		cache:         cache,
+		validator:     validator,
+		tasks:         tasks,
+		log:           log,
 	}
 }
 
-func (s *productServiceImpl) CreateProduct(ctx context.Context, userID uuid.UUID, req *models.ProductRequest) (*entities.Product, error) {
-	if err := s.validator.Struct(req); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
+func (s *productServiceImpl) CreateProduct(ctx context.Context, subject policies.Subject, req *models.ProductRequest) (*entities.Product, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionCreateProduct, policies.Resource{SellerID: subject.UserID}); err != nil {
+		return nil, err
+	}
 
-		var errorMessages []string
-		for _, fieldErr := range validationErrors {
-			errorMessages = append(errorMessages, fmt.Sprintf("Field '%s' failed on the '%s' tag", fieldErr.Field(), fieldErr.Tag()))
-		}
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	status := entities.ProductStatusActive
+	if req.Status != "" {
+		status = entities.ProductStatus(req.Status)
+	}
 
-		return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, strings.Join(errorMessages, ", "))
+	attributes, err := s.resolveAttributes(ctx, req.Type, req.Attributes)
+	if err != nil {
+		return nil, err
 	}
 
 	product := &db.InsertProductParams{
 		ID:          helpers.GenerateNewID(),
-		SellerID:    userID,
+		SellerID:    subject.UserID,
 		Name:        req.Name,
 		Price:       int32(req.Price),
 		Stock:       int32(req.Stock),
 		Discount:    helpers.IntToNullInt32(req.Discount),
 		Type:        helpers.StringToNullString(req.Type),
 		Description: helpers.StringToNullString(req.Description),
+		ExternalSku: helpers.OptionalStringToNullString(req.ExternalSKU),
+		Status:      string(status),
+		Attributes:  attributes,
 	}
 
 	dbProduct, err := s.productRepo.CreateProduct(ctx, product)
@@ -97,280 +149,199 @@ func (s *productServiceImpl) CreateProduct(ctx context.Context, userID uuid.UUID
 		return nil, fmt.Errorf("service: failed to add product: %w", err)
 	}
 
-	if err := s.InvalidateProductCache(ctx, dbProduct.ID); err != nil {
-		s.log.Errorf("Failed to clear product cache: %v", err)
-	}
+	domainProduct := toDomainProduct(dbProduct)
+	s.invalidateProductCaches(ctx, domainProduct)
 
-	return toDomainProduct(dbProduct), nil
+	return domainProduct, nil
 }
 
 func (s *productServiceImpl) GetAllProducts(ctx context.Context) ([]entities.Product, error) {
-	var products []entities.Product
-	cacheKey := "all_products"
-
-	if val, err := s.redisClient.Client.Get(ctx, cacheKey).Result(); err == nil {
-		if err := json.Unmarshal([]byte(val), &products); err == nil {
-			s.log.Info("Hit Cache untuk GetAllProducts")
-			return products, nil
-		}
-	}
-
-	dbProducts, err := s.productRepo.GetAllProducts(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to retrieve all products: %w", err)
-	}
-
-	domainProduct := toDomainProducts(dbProducts)
+	opts := cache.Options[[]entities.Product]{Tags: productListTags(tagAllProducts)}
 
-	jsonBytes, err := json.Marshal(domainProduct)
-	if err == nil {
-		if err := s.redisClient.Client.Set(ctx, cacheKey, jsonBytes, 5*time.Minute).Err(); err != nil {
-			s.log.WithField("key", cacheKey).Warn("Failed to set cache")
+	return cache.GetOrLoad(ctx, s.cache, allProductsCacheKey, opts, func(ctx context.Context) ([]entities.Product, error) {
+		dbProducts, err := s.productRepo.GetAllProducts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to retrieve all products: %w", err)
 		}
-	}
 
-	return domainProduct, nil
+		return toDomainProducts(dbProducts), nil
+	})
 }
 
-func (s *productServiceImpl) GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]entities.Product, error) {
-	var products []entities.Product
-	cacheKey := fmt.Sprintf("products_by_seller:%s", sellerID)
-
-	if val, err := s.redisClient.Client.Get(ctx, cacheKey).Result(); err == nil {
-		if err := json.Unmarshal([]byte(val), &products); err == nil {
-			s.log.WithField("seller_id", sellerID).Info("Hit Cache untuk GetProductsBySellerID")
-			return products, nil
+// ListProducts membaca satu halaman produk active langsung dari DB dengan keyset (created_at, id),
+// jadi biayanya tidak bergantung pada posisi halaman dan tidak memuat seluruh katalog
+func (s *productServiceImpl) ListProducts(ctx context.Context, pageToken string, pageSize int) (*entities.ProductPage, error) {
+	params := db.ListActiveProductsPageParams{RowLimit: int32(pageSize) + 1}
+	if pageToken != "" {
+		createdAt, id, err := decodeProductPageToken(pageToken)
+		if err != nil {
+			return nil, err
 		}
+		params.AfterCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+		params.AfterID = uuid.NullUUID{UUID: id, Valid: true}
 	}
 
-	dbProducts, err := s.productRepo.GetProductsBySellerID(ctx, sellerID)
+	rows, err := s.productRepo.ListActiveProductsPage(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("service: failed to retrieve products by seller ID %s: %w", sellerID, err)
+		return nil, fmt.Errorf("service: failed to list products page: %w", err)
 	}
 
-	domainProduct := toDomainProducts(dbProducts)
+	total, err := s.productRepo.CountActiveProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to count products: %w", err)
+	}
 
-	jsonBytes, err := json.Marshal(domainProduct)
-	if err == nil {
-		if err := s.redisClient.Client.Set(ctx, cacheKey, jsonBytes, 5*time.Minute).Err(); err != nil {
-			s.log.WithField("key", cacheKey).Warn("Failed to set cache")
-		}
+	// Satu baris ekstra hanya dipakai untuk mengetahui apakah masih ada halaman berikutnya
+	page := &entities.ProductPage{TotalSize: int(total)}
+	if len(rows) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		page.NextPageToken = encodeProductPageToken(last.CreatedAt, last.ID)
 	}
+	page.Products = toDomainProducts(rows)
 
-	return domainProduct, nil
+	return page, nil
 }
 
-func (s *productServiceImpl) GetProductsByName(ctx context.Context, name string) ([]entities.Product, error) {
-	var products []entities.Product
-	cacheKey := fmt.Sprintf("products_by_name:%s", name)
+func (s *productServiceImpl) GetProductsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]entities.Product, error) {
+	opts := cache.Options[[]entities.Product]{Tags: productListTags(sellerTag(sellerID))}
 
-	if val, err := s.redisClient.Client.Get(ctx, cacheKey).Result(); err == nil {
-		if err := json.Unmarshal([]byte(val), &products); err == nil {
-			s.log.WithField("name", name).Info("Hit Cache untuk GetProductsByName")
-			return products, nil
+	return cache.GetOrLoad(ctx, s.cache, sellerProductsCacheKey(sellerID), opts, func(ctx context.Context) ([]entities.Product, error) {
+		dbProducts, err := s.productRepo.GetProductsBySellerID(ctx, sellerID)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to retrieve products by seller ID %s: %w", sellerID, err)
 		}
-	}
 
-	dbProducts, err := s.productRepo.GetProductsByName(ctx, name)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to retrieve products by name %s: %w", name, err)
-	}
-
-	if len(dbProducts) == 0 {
-		return []entities.Product{}, nil
-	}
+		return toDomainProducts(dbProducts), nil
+	})
+}
 
-	domainProducts := toDomainProducts(dbProducts)
+func (s *productServiceImpl) GetProductsByName(ctx context.Context, name string) ([]entities.Product, error) {
+	opts := cache.Options[[]entities.Product]{Tags: productListTags(tagProductSearch)}
 
-	go func() {
-		jsonBytes, err := json.Marshal(domainProducts)
+	return cache.GetOrLoad(ctx, s.cache, nameProductsCacheKey(name), opts, func(ctx context.Context) ([]entities.Product, error) {
+		dbProducts, err := s.productRepo.GetProductsByName(ctx, name)
 		if err != nil {
-			s.log.Errorf("Failed to marshal products for caching: %v", err)
-			return
+			return nil, fmt.Errorf("service: failed to retrieve products by name %s: %w", name, err)
 		}
 
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		if err := s.redisClient.Client.Set(cacheCtx, cacheKey, jsonBytes, 5*time.Minute).Err(); err != nil {
-			s.log.Warnf("Failed to set cache in background for key '%s': %v", cacheKey, err)
-		}
-	}()
-
-	return domainProducts, nil
+		return toDomainProducts(dbProducts), nil
+	})
 }
 
 func (s *productServiceImpl) GetProductsByType(ctx context.Context, productType string) ([]entities.Product, error) {
-	var products []entities.Product
-	cacheKey := fmt.Sprintf("products_by_type:%s", productType)
-
-	if val, err := s.redisClient.Client.Get(ctx, cacheKey).Result(); err == nil {
-		if err := json.Unmarshal([]byte(val), &products); err == nil {
-			s.log.WithField("name", productType).Info("Hit Cache untuk GetProductsByName")
-			return products, nil
-		}
-	}
-
-	dbProducts, err := s.productRepo.GetProductsByType(ctx, productType)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to retrieve products by name %s: %w", productType, err)
-	}
-
-	if len(dbProducts) == 0 {
-		return []entities.Product{}, nil
-	}
-
-	domainProducts := toDomainProducts(dbProducts)
+	opts := cache.Options[[]entities.Product]{Tags: productListTags(typeTag(productType))}
 
-	go func() {
-		jsonBytes, err := json.Marshal(domainProducts)
+	return cache.GetOrLoad(ctx, s.cache, typeProductsCacheKey(productType), opts, func(ctx context.Context) ([]entities.Product, error) {
+		dbProducts, err := s.productRepo.GetProductsByType(ctx, productType)
 		if err != nil {
-			s.log.Errorf("Failed to marshal products for caching: %v", err)
-			return
-		}
-
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		if err := s.redisClient.Client.Set(cacheCtx, cacheKey, jsonBytes, 5*time.Minute).Err(); err != nil {
-			s.log.Warnf("Failed to set cache in background for key '%s': %v", cacheKey, err)
+			return nil, fmt.Errorf("service: failed to retrieve products by type %s: %w", productType, err)
 		}
-	}()
 
-	return domainProducts, nil
+		return toDomainProducts(dbProducts), nil
+	})
 }
 
 func (s *productServiceImpl) GetProductByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
-	var products *entities.Product
-	cacheKey := fmt.Sprintf("product:%s", id)
-
-	if val, err := s.redisClient.Client.Get(ctx, cacheKey).Result(); err == nil {
-		if err = json.Unmarshal([]byte(val), &products); err == nil {
-			s.log.WithField("product_id", id).Info("Hit Cache untuk GetProductByID")
-			return products, nil
-		}
-	}
-
-	dbProduct, err := s.productRepo.GetProductByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to retrieve product by ID: %w", err)
-	}
-
-	if dbProduct == nil {
-		return &entities.Product{}, nil
+	opts := cache.Options[*entities.Product]{
+		Tags: func(p *entities.Product) []string {
+			return []string{productTag(p.ID)}
+		},
+		NotFound: apperrors.ErrProductNotFound,
+		Local:    true,
 	}
 
-	domainProduct := toDomainProduct(dbProduct)
-
-	jsonBytes, err := json.Marshal(domainProduct)
-	if err == nil {
-		if err = s.redisClient.Client.Set(ctx, cacheKey, jsonBytes, 5*time.Minute).Err(); err != nil {
-			s.log.WithField("key", cacheKey).Warn("Failed to set cache")
+	return cache.GetOrLoad(ctx, s.cache, productCacheKey(id), opts, func(ctx context.Context) (*entities.Product, error) {
+		dbProduct, err := s.productRepo.GetProductByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apperrors.ErrProductNotFound
+			}
+			return nil, fmt.Errorf("service: failed to retrieve product by ID: %w", err)
 		}
 
-	}
-
-	return domainProduct, nil
+		return toDomainProduct(dbProduct), nil
+	})
 }
 
 func (s *productServiceImpl) GetProductByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.Product, error) {
+	ids = uniqueUUIDs(ids)
 	if len(ids) == 0 {
 		return []entities.Product{}, nil
 	}
 
-	finalProducts := make([]entities.Product, 0, len(ids))
 	cacheKeys := make([]string, len(ids))
-	missedIDs := make(map[uuid.UUID]bool)
-
 	for i, id := range ids {
-		cacheKeys[i] = fmt.Sprintf("product:%s", id.String())
-		missedIDs[id] = true
+		cacheKeys[i] = productCacheKey(id)
 	}
 
-	s.log.WithField("keys", cacheKeys).Info("Attempting to retrieve a product from the Redis cache with MGET")
-
-	cachedResults, err := s.redisClient.Client.MGet(ctx, cacheKeys...).Result()
-	if err != nil {
-		s.log.WithError(err).Warn("Failed to run MGET Redis. Will retrieve everything from the database..")
-	} else {
-		for i, result := range cachedResults {
-			if result != nil {
-				if val, ok := result.(string); ok {
-					var product entities.Product
-					if json.Unmarshal([]byte(val), &product) == nil {
-						finalProducts = append(finalProducts, product)
-						delete(missedIDs, ids[i])
-						s.log.WithField("product_id", ids[i]).Debug("Cache HIT untuk produk")
-					}
-				}
-			}
-		}
+	finalProducts, missed := cache.GetMany(ctx, s.cache, cacheKeys, cache.Options[entities.Product]{Local: true})
+	if len(missed) == 0 {
+		return finalProducts, nil
 	}
 
-	if len(missedIDs) > 0 {
-		missedIDSlice := make([]uuid.UUID, 0, len(missedIDs))
-		for id := range missedIDs {
-			missedIDSlice = append(missedIDSlice, id)
-		}
-
-		s.log.WithField("missed_ids", missedIDSlice).Info("Cache MISS. Mengambil produk yang hilang dari database.")
-
-		dbProducts, err := s.productRepo.GetProductByIDs(ctx, missedIDSlice)
-		if err != nil {
-			return nil, fmt.Errorf("failed to retrieve product from database: %w", err)
-		}
-
-		domainProducts := toDomainProducts(dbProducts)
+	missedIDs := make([]uuid.UUID, len(missed))
+	for i, idx := range missed {
+		missedIDs[i] = ids[idx]
+	}
 
-		finalProducts = append(finalProducts, domainProducts...)
+	s.log.WithField("missed_ids", missedIDs).Debug("Cache MISS. Mengambil produk yang hilang dari database.")
 
-		if len(domainProducts) > 0 {
-			pairs := make([]interface{}, 0, len(domainProducts)*2)
-			for _, product := range domainProducts {
-				cacheKey := fmt.Sprintf("product:%s", product.ID.String())
-				jsonBytes, err := json.Marshal(product)
-				if err == nil {
-					pairs = append(pairs, cacheKey, string(jsonBytes))
-				}
-			}
+	dbProducts, err := s.productRepo.GetProductByIDs(ctx, missedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product from database: %w", err)
+	}
 
-			if len(pairs) > 0 {
-				if err := s.redisClient.Client.MSet(ctx, pairs...).Err(); err != nil {
-					s.log.WithError(err).Error("Failed to run MSET to save new products to the Redis cache")
-				} else {
-					pipe := s.redisClient.Client.Pipeline()
-					ttl := 10 * time.Minute
-					for i := 0; i < len(pairs); i += 2 {
-						key := pairs[i].(string)
-						pipe.Expire(ctx, key, ttl)
-					}
-					if _, err := pipe.Exec(ctx); err != nil {
-						s.log.WithError(err).Error("Failed to set TTL for new product keys in the Redis cache")
-					}
-				}
-			}
+	domainProducts := toDomainProducts(dbProducts)
+	finalProducts = append(finalProducts, domainProducts...)
+
+	found := make(map[uuid.UUID]struct{}, len(domainProducts))
+	entries := make([]cache.Entry[entities.Product], 0, len(domainProducts))
+	for _, product := range domainProducts {
+		found[product.ID] = struct{}{}
+		entries = append(entries, cache.Entry[entities.Product]{
+			Key:   productCacheKey(product.ID),
+			Value: product,
+			Tags:  []string{productTag(product.ID)},
+		})
+	}
+	cache.SetMany(ctx, s.cache, entries)
+
+	// ID yang tidak ada di database di-cache sebagai negative entry agar tidak di-query berulang kali
+	for _, id := range missedIDs {
+		if _, ok := found[id]; !ok {
+			s.cache.SetNegative(ctx, productCacheKey(id), []string{productTag(id)})
 		}
 	}
 
 	return finalProducts, nil
 }
-func (s *productServiceImpl) UpdateProduct(ctx context.Context, req *models.ProductRequest, productID, sellerID uuid.UUID, role string) (*entities.Product, error) {
-	if err := s.validator.Struct(req); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var errorMessages []string
-		for _, fieldErr := range validationErrors {
-			errorMessages = append(errorMessages, fmt.Sprintf("Field '%s' failed on the '%s' tag", fieldErr.Field(), fieldErr.Tag()))
-		}
-		return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, strings.Join(errorMessages, ", "))
+
+func (s *productServiceImpl) UpdateProduct(ctx context.Context, req *models.ProductRequest, productID uuid.UUID, subject policies.Subject) (*entities.Product, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
 	}
 
 	existingProduct, err := s.productRepo.GetProductByID(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.ErrProductNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to find product for update: %w", err)
 	}
 
-	if role != "admin" && existingProduct.SellerID != sellerID {
-		return nil, fmt.Errorf("service: product does not belong to this seller")
+	if err := s.policy.Authorize(ctx, subject, policies.ActionUpdateProduct, policies.Resource{SellerID: existingProduct.SellerID}); err != nil {
+		return nil, err
+	}
+
+	// Atribut lama tetap divalidasi ulang karena type produk bisa saja ikut berubah
+	attrs := req.Attributes
+	if attrs == nil {
+		attrs = helpers.ConvertJSONMap(reflect.ValueOf(existingProduct.Attributes))
+	}
+	attributes, err := s.resolveAttributes(ctx, req.Type, attrs)
+	if err != nil {
+		return nil, err
 	}
 
 	productParam := &db.UpdateProductParams{
@@ -382,6 +353,7 @@ func (s *productServiceImpl) UpdateProduct(ctx context.Context, req *models.Prod
 		Discount:    helpers.IntToNullInt32(req.Discount),
 		Type:        helpers.StringToNullString(req.Type),
 		Description: helpers.StringToNullString(req.Description),
+		Attributes:  attributes,
 	}
 
 	dbProduct, err := s.productRepo.UpdateProduct(ctx, productParam)
@@ -389,21 +361,23 @@ func (s *productServiceImpl) UpdateProduct(ctx context.Context, req *models.Prod
 		return nil, fmt.Errorf("service: failed to update product: %w", err)
 	}
 
-	if err := s.InvalidateProductCache(ctx, productID); err != nil {
-		s.log.Errorf("Failed to clear product cache: %v", err)
-	}
+	domainProduct := toDomainProduct(dbProduct)
+	s.invalidateProductCaches(ctx, domainProduct)
 
-	return toDomainProduct(dbProduct), nil
+	return domainProduct, nil
 }
 
-func (s *productServiceImpl) DeleteProduct(ctx context.Context, productID, sellerID uuid.UUID, role string) (*entities.Product, error) {
+func (s *productServiceImpl) DeleteProduct(ctx context.Context, productID uuid.UUID, subject policies.Subject) (*entities.Product, error) {
 	existingProduct, err := s.productRepo.GetProductByID(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.ErrProductNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to find product for deletion: %w", err)
 	}
 
-	if role != "admin" && existingProduct.SellerID != sellerID {
-		return nil, fmt.Errorf("service: product does not belong to this seller")
+	if err := s.policy.Authorize(ctx, subject, policies.ActionDeleteProduct, policies.Resource{SellerID: existingProduct.SellerID}); err != nil {
+		return nil, err
 	}
 
 	dbPproduct, err := s.productRepo.DeleteProduct(ctx, productID)
@@ -411,28 +385,57 @@ func (s *productServiceImpl) DeleteProduct(ctx context.Context, productID, selle
 		return nil, fmt.Errorf("service: failed to delete product: %w", err)
 	}
 
-	if err := s.InvalidateProductCache(ctx, productID); err != nil {
-		s.log.Errorf("Failed to clear product cache: %v", err)
-	}
+	domainProduct := toDomainProduct(dbPproduct)
+	s.invalidateProductCaches(ctx, domainProduct)
 
-	return toDomainProduct(dbPproduct), nil
+	return domainProduct, nil
 }
 
-func (s *productServiceImpl) DecreaseStock(ctx context.Context, items []*productpb.StockItem) ([]*entities.Product, error) {
+func (s *productServiceImpl) DecreaseStock(ctx context.Context, subject policies.Subject, items []*productpb.StockItem) ([]*entities.Product, error) {
 	tx, err := s.productRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback() // Rollback
 
-	updatedProducts := make([]*entities.Product, 0, len(items))
+	order, quantities, locked, err := s.lockStockItems(ctx, tx, subject, items)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, item := range items {
-		productID, _ := uuid.Parse(item.ProductId)
+	// Produk draft, archived atau banned tidak boleh dipesan walaupun masih ada di cart pembeli
+	var inactive []apperrors.InactiveProduct
+	for _, productID := range order {
+		if status := locked[productID].Status; status != string(entities.ProductStatusActive) {
+			inactive = append(inactive, apperrors.InactiveProduct{ProductID: productID.String(), Status: status})
+		}
+	}
+	if len(inactive) > 0 {
+		metrics.StockDecrementFailuresTotal.WithLabelValues("not_active").Add(float64(len(inactive)))
+		return nil, &apperrors.ProductsNotActiveError{Products: inactive}
+	}
+
+	// Semua kekurangan stok dikumpulkan dulu supaya pemanggil tahu produk mana saja yang gagal
+	var shortages []apperrors.StockShortage
+	for _, productID := range order {
+		if available := locked[productID].Stock; available < quantities[productID] {
+			shortages = append(shortages, apperrors.StockShortage{
+				ProductID: productID.String(),
+				Requested: quantities[productID],
+				Available: available,
+			})
+		}
+	}
+	if len(shortages) > 0 {
+		metrics.StockDecrementFailuresTotal.WithLabelValues("out_of_stock").Add(float64(len(shortages)))
+		return nil, &apperrors.StockShortageError{Shortages: shortages}
+	}
 
-		dbProduct, err := s.productRepo.DecreaseProductStock(ctx, tx, productID, item.QuantityToDecrease)
+	updatedProducts := make([]*entities.Product, 0, len(order))
+	for _, productID := range order {
+		dbProduct, err := s.productRepo.DecreaseProductStock(ctx, tx, productID, quantities[productID])
 		if err != nil {
-			return nil, fmt.Errorf("failed to process stock for product %s: %w", item.ProductId, err) // Rollback
+			return nil, fmt.Errorf("failed to process stock for product %s: %w", productID, err) // Rollback
 		}
 
 		updatedProducts = append(updatedProducts, toDomainProduct(dbProduct))
@@ -442,30 +445,37 @@ func (s *productServiceImpl) DecreaseStock(ctx context.Context, items []*product
 		return nil, fmt.Errorf("failed to commit stock update transaction: %w", err)
 	}
 
-	go s.InvalidateCachesAfterUpdate(ctx, updatedProducts)
+	s.tasks.Go(func(ctx context.Context) {
+		s.InvalidateCachesAfterUpdate(ctx, updatedProducts)
+	})
 
 	return updatedProducts, nil
 }
 
-func (s *productServiceImpl) IncreaseStock(ctx context.Context, items []*productpb.StockItem) ([]*entities.Product, error) {
+func (s *productServiceImpl) IncreaseStock(ctx context.Context, subject policies.Subject, items []*productpb.StockItem) ([]*entities.Product, error) {
 	tx, err := s.productRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	updatedProducts := make([]*entities.Product, 0, len(items))
-	for _, item := range items {
-		productID, _ := uuid.Parse(item.ProductId)
+	order, quantities, _, err := s.lockStockItems(ctx, tx, subject, items)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedProducts := make([]*entities.Product, 0, len(order))
+	for _, productID := range order {
 		params := db.IncreaseProductStockParams{
 			ProductID:          productID,
-			QuantityToIncrease: item.QuantityToDecrease,
+			QuantityToIncrease: quantities[productID],
 		}
 
 		dbProduct, err := s.productRepo.IncreaseProductStock(ctx, tx, params)
 		if err != nil {
-			return nil, fmt.Errorf("failed to process stock increase for %s: %w", item.ProductId, err)
+			return nil, fmt.Errorf("failed to process stock increase for %s: %w", productID, err)
 		}
+
 		updatedProducts = append(updatedProducts, toDomainProduct(&dbProduct))
 	}
 
@@ -473,11 +483,90 @@ func (s *productServiceImpl) IncreaseStock(ctx context.Context, items []*product
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	go s.InvalidateCachesAfterUpdate(ctx, updatedProducts)
+	s.tasks.Go(func(ctx context.Context) {
+		s.InvalidateCachesAfterUpdate(ctx, updatedProducts)
+	})
 	return updatedProducts, nil
 }
 
+// lockStockItems memvalidasi item, menjumlahkan kuantitas per produk, mengunci barisnya, lalu mengecek policy.
+// order mempertahankan urutan kemunculan produk di request.
+func (s *productServiceImpl) lockStockItems(ctx context.Context, tx *sql.Tx, subject policies.Subject, items []*productpb.StockItem) ([]uuid.UUID, map[uuid.UUID]int32, map[uuid.UUID]db.Product, error) {
+	if len(items) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: at least one stock item is required", apperrors.ErrInvalidRequestPayload)
+	}
+
+	order := make([]uuid.UUID, 0, len(items))
+	quantities := make(map[uuid.UUID]int32, len(items))
+	for _, item := range items {
+		productID, err := uuid.Parse(item.GetProductId())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: invalid product ID '%s'", apperrors.ErrInvalidRequestPayload, item.GetProductId())
+		}
+		if item.GetQuantityToDecrease() <= 0 {
+			return nil, nil, nil, fmt.Errorf("%w: quantity for product %s must be greater than zero", apperrors.ErrInvalidRequestPayload, productID)
+		}
+
+		if _, ok := quantities[productID]; !ok {
+			order = append(order, productID)
+		}
+		quantities[productID] += item.GetQuantityToDecrease()
+	}
+
+	rows, err := s.productRepo.LockProductsByIDs(ctx, tx, order)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("service: failed to lock products for stock update: %w", err)
+	}
+
+	locked := make(map[uuid.UUID]db.Product, len(rows))
+	for _, row := range rows {
+		locked[row.ID] = row
+	}
+
+	var missing []string
+	for _, productID := range order {
+		if _, ok := locked[productID]; !ok {
+			missing = append(missing, productID.String())
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, nil, &apperrors.ProductsNotFoundError{ProductIDs: missing}
+	}
+
+	authorizedSellers := make(map[uuid.UUID]struct{})
+	for _, productID := range order {
+		sellerID := locked[productID].SellerID
+		if _, ok := authorizedSellers[sellerID]; ok {
+			continue
+		}
+		if err := s.policy.Authorize(ctx, subject, policies.ActionAdjustStock, policies.Resource{SellerID: sellerID}); err != nil {
+			return nil, nil, nil, err
+		}
+		authorizedSellers[sellerID] = struct{}{}
+	}
+
+	return order, quantities, locked, nil
+}
+
 // ------- HELPERS -------
+func (s *productServiceImpl) validateRequest(req interface{}) error {
+	if err := s.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, err)
+		}
+
+		var errorMessages []string
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fmt.Sprintf("Field '%s' failed on the '%s' tag", fieldErr.Field(), fieldErr.Tag()))
+		}
+
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalidRequestPayload, strings.Join(errorMessages, ", "))
+	}
+
+	return nil
+}
+
 func toDomainProduct[T ProductSource](dbProduct *T) *entities.Product {
 	v := reflect.ValueOf(dbProduct)
 	if v.Kind() == reflect.Ptr {
@@ -486,16 +575,22 @@ func toDomainProduct[T ProductSource](dbProduct *T) *entities.Product {
 	id := v.FieldByName("ID").Interface().(uuid.UUID)
 
 	return &entities.Product{
-		ID:          id,
-		SellerID:    v.FieldByName("SellerID").Interface().(uuid.UUID),
-		Name:        v.FieldByName("Name").Interface().(string),
-		Price:       helpers.ConvertNullInt32(v.FieldByName("Price")),
-		Stock:       helpers.ConvertNullInt32(v.FieldByName("Stock")),
-		Discount:    helpers.ConvertNullInt32(v.FieldByName("Discount")),
-		Type:        helpers.ConvertNullString(v.FieldByName("Type")),
-		Description: helpers.ConvertNullString(v.FieldByName("Description")),
-		CreatedAt:   v.FieldByName("CreatedAt").Interface().(time.Time),
-		UpdatedAt:   v.FieldByName("UpdatedAt").Interface().(time.Time),
+		ID:           id,
+		SellerID:     v.FieldByName("SellerID").Interface().(uuid.UUID),
+		Name:         v.FieldByName("Name").Interface().(string),
+		Price:        helpers.ConvertNullInt32(v.FieldByName("Price")),
+		Stock:        helpers.ConvertNullInt32(v.FieldByName("Stock")),
+		Discount:     helpers.ConvertNullInt32(v.FieldByName("Discount")),
+		Type:         helpers.ConvertNullString(v.FieldByName("Type")),
+		Description:  helpers.ConvertNullString(v.FieldByName("Description")),
+		ExternalSKU:  helpers.ConvertNullString(v.FieldByName("ExternalSku")),
+		RatingAvg:    v.FieldByName("RatingAvg").Interface().(float64),
+		RatingCount:  helpers.ConvertNullInt32(v.FieldByName("RatingCount")),
+		Status:       entities.ProductStatus(helpers.ConvertNullString(v.FieldByName("Status"))),
+		StatusReason: helpers.ConvertNullString(v.FieldByName("StatusReason")),
+		Attributes:   helpers.ConvertJSONMap(v.FieldByName("Attributes")),
+		CreatedAt:    v.FieldByName("CreatedAt").Interface().(time.Time),
+		UpdatedAt:    v.FieldByName("UpdatedAt").Interface().(time.Time),
 	}
 }
 
@@ -509,79 +604,30 @@ func toDomainProducts[T ProductSource](dbProducts []T) []entities.Product {
 	return products
 }
 
-func (s *productServiceImpl) InvalidateProductCache(ctx context.Context, productID uuid.UUID) error {
-	s.log.Infof("Invalidating caches for product %s and product list...", productID)
-
-	keysToDelete := []string{
-		fmt.Sprintf("product:%s", productID),
-		"all_products",
-	}
-
-	err := s.redisClient.Client.Del(ctx, keysToDelete...).Err()
-	if err != nil {
-		s.log.Errorf("Failed to invalidate product cache keys (%v): %v", keysToDelete, err)
-		return err
-	}
-
-	s.log.Infof("Cache keys %v successfully invalidated.", keysToDelete)
-	return nil
+// Page token berisi created_at (mikrodetik, sesuai presisi kolom TIMESTAMP) dan id produk terakhir di halaman sebelumnya
+func encodeProductPageToken(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", createdAt.UnixMicro(), id)))
 }
 
-func (s *productServiceImpl) ResetAllProductCaches(ctx context.Context) error {
-	s.log.Info("Starting to reset ALL product caches...")
-
-	if err := s.redisClient.Client.Del(ctx, "all_products").Err(); err != nil {
-		s.log.Errorf("Failed to delete main product list cache: %v", err)
+func decodeProductPageToken(token string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, uuid.Nil, apperrors.ErrInvalidPageToken
 	}
 
-	var cursor uint64
-	pattern := "product:*"
-
-	pipe := s.redisClient.Client.Pipeline()
-	keysFound := 0
-
-	for {
-		keys, nextCursor, err := s.redisClient.Client.Scan(ctx, cursor, pattern, 100).Result()
-		if err != nil {
-			s.log.Errorf("Error during Redis SCAN with pattern '%s': %v", pattern, err)
-			return err
-		}
-
-		if len(keys) > 0 {
-			pipe.Del(ctx, keys...)
-			keysFound += len(keys)
-		}
-
-		cursor = nextCursor
-		if cursor == 0 {
-			break
-		}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, apperrors.ErrInvalidPageToken
 	}
 
-	if _, err := pipe.Exec(ctx); err != nil {
-		s.log.Errorf("Failed to execute pipeline for deleting %d keys: %v", keysFound, err)
-		return err
+	micros, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, apperrors.ErrInvalidPageToken
 	}
-
-	s.log.Infof("Successfully reset %d individual product caches and the main list cache.", keysFound)
-	return nil
-}
-
-func (s *productServiceImpl) InvalidateCachesAfterUpdate(ctx context.Context, updatedProducts []*entities.Product) {
-	s.log.Info("Invalidating product caches after stock update...")
-
-	keysToDel := []string{"all_products"}
-
-	for _, p := range updatedProducts {
-		keysToDel = append(keysToDel, fmt.Sprintf("product:%s", p.ID.String()))
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, apperrors.ErrInvalidPageToken
 	}
 
-	cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := s.redisClient.Client.Del(cacheCtx, keysToDel...).Err(); err != nil {
-		s.log.Warnf("Failed to invalidate product caches: %v", err)
-	} else {
-		s.log.Infof("Successfully invalidated %d cache keys.", len(keysToDel))
-	}
+	return time.UnixMicro(micros).UTC(), id, nil
 }
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+)
+
+// productStatusTransitions adalah state machine status produk: status asal -> status tujuan yang diizinkan.
+// Produk banned hanya bisa dikembalikan ke draft supaya seller meninjau ulang sebelum tayang lagi.
+var productStatusTransitions = map[entities.ProductStatus][]entities.ProductStatus{
+	entities.ProductStatusDraft:    {entities.ProductStatusActive, entities.ProductStatusArchived, entities.ProductStatusBanned},
+	entities.ProductStatusActive:   {entities.ProductStatusDraft, entities.ProductStatusArchived, entities.ProductStatusBanned},
+	entities.ProductStatusArchived: {entities.ProductStatusActive, entities.ProductStatusDraft, entities.ProductStatusBanned},
+	entities.ProductStatusBanned:   {entities.ProductStatusDraft},
+}
+
+func isAllowedProductTransition(from, to entities.ProductStatus) bool {
+	for _, allowed := range productStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *productServiceImpl) ChangeProductStatus(ctx context.Context, subject policies.Subject, productID uuid.UUID, req *models.ProductStatusRequest) (*entities.Product, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	existingProduct, err := s.productRepo.GetProductByID(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to find product for status change: %w", err)
+	}
+
+	from := entities.ProductStatus(existingProduct.Status)
+	to := entities.ProductStatus(req.Status)
+
+	// Ban dan pencabutan ban hanya untuk admin; transisi lain mengikuti hak update produk
+	action := policies.ActionUpdateProduct
+	if from == entities.ProductStatusBanned || to == entities.ProductStatusBanned {
+		action = policies.ActionBanProduct
+	}
+	if err := s.policy.Authorize(ctx, subject, action, policies.Resource{SellerID: existingProduct.SellerID}); err != nil {
+		return nil, err
+	}
+
+	if !isAllowedProductTransition(from, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", apperrors.ErrInvalidProductTransition, from, to)
+	}
+	if to == entities.ProductStatusBanned && req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required when banning a product", apperrors.ErrInvalidRequestPayload)
+	}
+
+	dbProduct, err := s.productRepo.TransitionProductStatus(ctx, db.TransitionProductStatusParams{
+		ID:         productID,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		Reason:     helpers.OptionalStringToNullString(req.Reason),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Status sudah diubah request lain (atau produk dihapus) sejak dibaca
+		return nil, fmt.Errorf("%w: product status was changed by another request", apperrors.ErrInvalidProductTransition)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to change product status: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"product_id": productID,
+		"user_id":    subject.UserID,
+		"from":       from,
+		"to":         to,
+	}).Info("Product status changed")
+
+	domainProduct := toDomainProduct(dbProduct)
+	s.invalidateProductCaches(ctx, domainProduct)
+
+	return domainProduct, nil
+}
+
+// GetVisibleProductByID menyembunyikan produk non-active dari viewer yang tidak berhak melihat draft milik seller-nya
+func (s *productServiceImpl) GetVisibleProductByID(ctx context.Context, viewer policies.Subject, id uuid.UUID) (*entities.Product, error) {
+	product, err := s.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.IsActive() && !s.canViewInactive(ctx, viewer, product.SellerID) {
+		return nil, apperrors.ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+// GetVisibleProductsBySellerID mengembalikan semua produk seller untuk pemilik, staff-nya dan admin; selain itu hanya yang active
+func (s *productServiceImpl) GetVisibleProductsBySellerID(ctx context.Context, viewer policies.Subject, sellerID uuid.UUID) ([]entities.Product, error) {
+	products, err := s.GetProductsBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.canViewInactive(ctx, viewer, sellerID) {
+		return products, nil
+	}
+
+	return FilterActiveProducts(products), nil
+}
+
+func (s *productServiceImpl) canViewInactive(ctx context.Context, viewer policies.Subject, sellerID uuid.UUID) bool {
+	if viewer.UserID == uuid.Nil {
+		return false
+	}
+
+	return s.policy.Authorize(ctx, viewer, policies.ActionViewDrafts, policies.Resource{SellerID: sellerID}) == nil
+}
+
+// FilterActiveProducts membuang produk non-active tanpa mengubah slice asal (yang bisa berasal dari cache)
+func FilterActiveProducts(products []entities.Product) []entities.Product {
+	active := make([]entities.Product, 0, len(products))
+	for _, p := range products {
+		if p.IsActive() {
+			active = append(active, p)
+		}
+	}
+
+	return active
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/configs"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/cache"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	customRedis "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/redis"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+// fakeStatusProductRepo hanya mengimplementasikan method yang dipakai ChangeProductStatus
+type fakeStatusProductRepo struct {
+	repositories.ProductRepository
+
+	product     *db.GetProductByIDRow
+	transitions []db.TransitionProductStatusParams
+	// concurrentStatus mensimulasikan request lain yang mengubah status setelah produk dibaca
+	concurrentStatus string
+}
+
+func (f *fakeStatusProductRepo) GetProductByID(ctx context.Context, id uuid.UUID) (*db.GetProductByIDRow, error) {
+	if f.product == nil || f.product.ID != id {
+		return nil, sql.ErrNoRows
+	}
+
+	row := *f.product
+	return &row, nil
+}
+
+func (f *fakeStatusProductRepo) TransitionProductStatus(ctx context.Context, params db.TransitionProductStatusParams) (*db.Product, error) {
+	f.transitions = append(f.transitions, params)
+	if f.concurrentStatus != "" {
+		f.product.Status = f.concurrentStatus
+	}
+	if f.product.Status != params.FromStatus {
+		return nil, sql.ErrNoRows
+	}
+
+	f.product.Status = params.ToStatus
+	return &db.Product{
+		ID:           f.product.ID,
+		SellerID:     f.product.SellerID,
+		Name:         f.product.Name,
+		Status:       params.ToStatus,
+		StatusReason: params.Reason,
+	}, nil
+}
+
+type fakeServiceStaffRepo struct {
+	repositories.SellerStaffRepository
+
+	staff map[uuid.UUID]uuid.UUID // staff -> seller
+}
+
+func (f *fakeServiceStaffRepo) IsStaff(ctx context.Context, sellerID, userID uuid.UUID) (bool, error) {
+	return f.staff[userID] == sellerID, nil
+}
+
+func newTestStatusService(t *testing.T, repo *fakeStatusProductRepo, staffRepo *fakeServiceStaffRepo) ProductService {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	productCache := cache.New(&customRedis.RedisClient{Client: client}, &configs.CacheConfig{InvalidationChannel: "test:cache_invalidation"}, log)
+	policy := policies.NewProductPolicy(staffRepo, log)
+
+	return NewProductService(repo, nil, nil, nil, policy, productCache, validator.New(), nil, log)
+}
+
+func TestChangeProductStatus(t *testing.T) {
+	seller := uuid.New()
+	otherSeller := uuid.New()
+	staff := uuid.New()
+	admin := uuid.New()
+
+	asSeller := policies.Subject{UserID: seller, Role: policies.RoleSeller}
+	asOtherSeller := policies.Subject{UserID: otherSeller, Role: policies.RoleSeller}
+	asStaff := policies.Subject{UserID: staff, Role: policies.RoleSellerStaff}
+	asAdmin := policies.Subject{UserID: admin, Role: policies.RoleAdmin}
+	asService := policies.Subject{Role: policies.RoleService}
+
+	tests := []struct {
+		name    string
+		subject policies.Subject
+		from    entities.ProductStatus
+		to      string
+		reason  string
+		wantErr error
+	}{
+		// transisi yang diizinkan untuk pemilik
+		{"seller publishes draft", asSeller, entities.ProductStatusDraft, "active", "", nil},
+		{"seller archives active", asSeller, entities.ProductStatusActive, "archived", "", nil},
+		{"seller unpublishes active", asSeller, entities.ProductStatusActive, "draft", "", nil},
+		{"seller restores archived", asSeller, entities.ProductStatusArchived, "active", "", nil},
+		{"seller moves archived to draft", asSeller, entities.ProductStatusArchived, "draft", "", nil},
+		{"seller archives draft", asSeller, entities.ProductStatusDraft, "archived", "", nil},
+		{"staff publishes employer draft", asStaff, entities.ProductStatusDraft, "active", "", nil},
+
+		// ban dan pencabutan ban hanya untuk admin
+		{"admin bans active", asAdmin, entities.ProductStatusActive, "banned", "counterfeit", nil},
+		{"admin bans draft", asAdmin, entities.ProductStatusDraft, "banned", "counterfeit", nil},
+		{"admin lifts ban to draft", asAdmin, entities.ProductStatusBanned, "draft", "", nil},
+		{"seller cannot ban", asSeller, entities.ProductStatusActive, "banned", "counterfeit", apperrors.ErrActionNotPermitted},
+		{"staff cannot ban", asStaff, entities.ProductStatusActive, "banned", "counterfeit", apperrors.ErrActionNotPermitted},
+		{"seller cannot lift ban", asSeller, entities.ProductStatusBanned, "draft", "", apperrors.ErrActionNotPermitted},
+		{"ban requires reason", asAdmin, entities.ProductStatusActive, "banned", "", apperrors.ErrInvalidRequestPayload},
+
+		// transisi di luar state machine
+		{"banned cannot go straight to active", asAdmin, entities.ProductStatusBanned, "active", "", apperrors.ErrInvalidProductTransition},
+		{"banned cannot be archived", asAdmin, entities.ProductStatusBanned, "archived", "", apperrors.ErrInvalidProductTransition},
+		{"same status is not a transition", asSeller, entities.ProductStatusActive, "active", "", apperrors.ErrInvalidProductTransition},
+		{"draft to draft", asSeller, entities.ProductStatusDraft, "draft", "", apperrors.ErrInvalidProductTransition},
+
+		// subject tanpa hak atas produk
+		{"other seller", asOtherSeller, entities.ProductStatusDraft, "active", "", apperrors.ErrProductNotBelongToSeller},
+		{"service cannot change status", asService, entities.ProductStatusDraft, "active", "", apperrors.ErrActionNotPermitted},
+
+		// payload tidak valid
+		{"unknown target status", asSeller, entities.ProductStatusDraft, "deleted", "", apperrors.ErrInvalidRequestPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productID := uuid.New()
+			repo := &fakeStatusProductRepo{product: &db.GetProductByIDRow{ID: productID, SellerID: seller, Name: "Keyboard", Status: string(tt.from)}}
+			svc := newTestStatusService(t, repo, &fakeServiceStaffRepo{staff: map[uuid.UUID]uuid.UUID{staff: seller}})
+
+			product, err := svc.ChangeProductStatus(context.Background(), tt.subject, productID, &models.ProductStatusRequest{Status: tt.to, Reason: tt.reason})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if len(repo.transitions) != 0 {
+					t.Fatalf("rejected change must not reach the repository, got %+v", repo.transitions)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if product.Status != entities.ProductStatus(tt.to) {
+				t.Fatalf("expected status %s, got %s", tt.to, product.Status)
+			}
+			if len(repo.transitions) != 1 || repo.transitions[0].FromStatus != string(tt.from) || repo.transitions[0].ToStatus != tt.to {
+				t.Fatalf("expected one %s -> %s transition, got %+v", tt.from, tt.to, repo.transitions)
+			}
+			if tt.reason != "" && product.StatusReason != tt.reason {
+				t.Fatalf("expected reason %q, got %q", tt.reason, product.StatusReason)
+			}
+		})
+	}
+}
+
+// Transisi ditulis dengan syarat status masih sama dengan yang dibaca, jadi perubahan konkuren tidak tertimpa
+func TestChangeProductStatusConcurrentChange(t *testing.T) {
+	seller := uuid.New()
+	productID := uuid.New()
+
+	repo := &fakeStatusProductRepo{
+		product:          &db.GetProductByIDRow{ID: productID, SellerID: seller, Status: string(entities.ProductStatusActive)},
+		concurrentStatus: string(entities.ProductStatusBanned),
+	}
+	svc := newTestStatusService(t, repo, &fakeServiceStaffRepo{})
+
+	_, err := svc.ChangeProductStatus(context.Background(), policies.Subject{UserID: seller, Role: policies.RoleSeller}, productID, &models.ProductStatusRequest{Status: "archived"})
+	if !errors.Is(err, apperrors.ErrInvalidProductTransition) {
+		t.Fatalf("expected %v, got %v", apperrors.ErrInvalidProductTransition, err)
+	}
+	if len(repo.transitions) != 1 || repo.transitions[0].FromStatus != string(entities.ProductStatusActive) {
+		t.Fatalf("expected transition guarded by the status that was read, got %+v", repo.transitions)
+	}
+	if repo.product.Status != string(entities.ProductStatusBanned) {
+		t.Fatalf("concurrent ban must not be overwritten, got %s", repo.product.Status)
+	}
+}
+
+func TestChangeProductStatusNotFound(t *testing.T) {
+	repo := &fakeStatusProductRepo{}
+	svc := newTestStatusService(t, repo, &fakeServiceStaffRepo{})
+
+	_, err := svc.ChangeProductStatus(context.Background(), policies.Subject{UserID: uuid.New(), Role: policies.RoleAdmin}, uuid.New(), &models.ProductStatusRequest{Status: "active"})
+	if !errors.Is(err, apperrors.ErrProductNotFound) {
+		t.Fatalf("expected %v, got %v", apperrors.ErrProductNotFound, err)
+	}
+}
+
+// Setiap status tujuan di state machine harus status yang dikenal dan banned hanya bisa kembali ke draft
+func TestProductStatusTransitionsTable(t *testing.T) {
+	known := map[entities.ProductStatus]bool{
+		entities.ProductStatusDraft:    true,
+		entities.ProductStatusActive:   true,
+		entities.ProductStatusArchived: true,
+		entities.ProductStatusBanned:   true,
+	}
+
+	for from, targets := range productStatusTransitions {
+		if !known[from] {
+			t.Errorf("unknown source status %q", from)
+		}
+		for _, to := range targets {
+			if !known[to] {
+				t.Errorf("%s -> unknown status %q", from, to)
+			}
+			if to == from {
+				t.Errorf("%s -> %s is not a transition", from, to)
+			}
+		}
+	}
+
+	if got := productStatusTransitions[entities.ProductStatusBanned]; len(got) != 1 || got[0] != entities.ProductStatusDraft {
+		t.Errorf("banned products must only return to draft, got %v", got)
+	}
+}
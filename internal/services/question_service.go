@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+const (
+	MaxQuestionBodyLength   = 1000
+	MaxQuestionAnswerLength = 2000
+	MaxQuestionFlagLength   = 500
+
+	DefaultQuestionLimit = 20
+	MaxQuestionLimit     = 100
+)
+
+type QuestionService interface {
+	AskQuestion(ctx context.Context, userID, productID uuid.UUID, req *models.QuestionRequest) (*entities.ProductQuestion, error)
+	GetProductQuestions(ctx context.Context, productID uuid.UUID, sortBy entities.QuestionSort, limit, offset int) ([]entities.ProductQuestion, error)
+	AnswerQuestion(ctx context.Context, subject policies.Subject, questionID uuid.UUID, req *models.QuestionAnswerRequest) (*entities.ProductQuestion, error)
+	UpvoteQuestion(ctx context.Context, userID, questionID uuid.UUID) (*entities.ProductQuestion, error)
+	RemoveUpvote(ctx context.Context, userID, questionID uuid.UUID) (*entities.ProductQuestion, error)
+	FlagQuestion(ctx context.Context, userID, questionID uuid.UUID, req *models.QuestionFlagRequest) error
+	// GetSellerInbox berisi pertanyaan yang belum dijawab di semua produk milik seller, yang terlama lebih dulu
+	GetSellerInbox(ctx context.Context, subject policies.Subject, sellerID uuid.UUID, limit, offset int) ([]entities.ProductQuestion, error)
+	GetFlaggedQuestions(ctx context.Context, subject policies.Subject, status entities.QuestionStatus, limit, offset int) ([]entities.ProductQuestion, error)
+	ModerateQuestion(ctx context.Context, subject policies.Subject, questionID uuid.UUID, status entities.QuestionStatus) (*entities.ProductQuestion, error)
+}
+
+type questionServiceImpl struct {
+	repo       repositories.QuestionRepository
+	productSvc ProductService
+	policy     policies.ProductPolicy
+	log        *logrus.Logger
+}
+
+func NewQuestionService(repo repositories.QuestionRepository, productSvc ProductService, policy policies.ProductPolicy, log *logrus.Logger) QuestionService {
+	return &questionServiceImpl{
+		repo:       repo,
+		productSvc: productSvc,
+		policy:     policy,
+		log:        log,
+	}
+}
+
+func (s *questionServiceImpl) AskQuestion(ctx context.Context, userID, productID uuid.UUID, req *models.QuestionRequest) (*entities.ProductQuestion, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > MaxQuestionBodyLength {
+		return nil, fmt.Errorf("%w: question is required and must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxQuestionBodyLength)
+	}
+
+	if _, err := s.productSvc.GetProductByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.repo.CreateQuestion(ctx, db.InsertProductQuestionParams{
+		ID:        helpers.GenerateNewID(),
+		ProductID: productID,
+		UserID:    userID,
+		Body:      body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainQuestion(row), nil
+}
+
+func (s *questionServiceImpl) GetProductQuestions(ctx context.Context, productID uuid.UUID, sortBy entities.QuestionSort, limit, offset int) ([]entities.ProductQuestion, error) {
+	if sortBy == "" {
+		sortBy = entities.QuestionSortRecent
+	}
+	if sortBy != entities.QuestionSortRecent && sortBy != entities.QuestionSortTop {
+		return nil, fmt.Errorf("%w: sort must be recent or top", apperrors.ErrInvalidUserInput)
+	}
+
+	limit, offset = normalizePage(limit, offset, DefaultQuestionLimit, MaxQuestionLimit)
+
+	rows, err := s.repo.GetQuestionsByProductID(ctx, db.GetProductQuestionsByProductIDParams{
+		ProductID:     productID,
+		SortByUpvotes: sortBy == entities.QuestionSortTop,
+		RowLimit:      int32(limit),
+		RowOffset:     int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve product questions: %w", err)
+	}
+
+	return toDomainQuestions(rows), nil
+}
+
+// Jawaban hanya boleh dari seller pemilik produk (products.seller_id), staff-nya, atau admin.
+// Menjawab ulang menimpa jawaban sebelumnya.
+func (s *questionServiceImpl) AnswerQuestion(ctx context.Context, subject policies.Subject, questionID uuid.UUID, req *models.QuestionAnswerRequest) (*entities.ProductQuestion, error) {
+	answer := strings.TrimSpace(req.Answer)
+	if answer == "" || utf8.RuneCountInString(answer) > MaxQuestionAnswerLength {
+		return nil, fmt.Errorf("%w: answer is required and must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxQuestionAnswerLength)
+	}
+
+	existing, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productSvc.GetProductByID(ctx, existing.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.Authorize(ctx, subject, policies.ActionAnswerQuestion, policies.Resource{SellerID: product.SellerID}); err != nil {
+		return nil, err
+	}
+
+	row, err := s.repo.Answer(ctx, questionID, subject.UserID, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainQuestion(row), nil
+}
+
+// Upvote bersifat idempoten; penanya tidak bisa meng-upvote pertanyaannya sendiri
+func (s *questionServiceImpl) UpvoteQuestion(ctx context.Context, userID, questionID uuid.UUID) (*entities.ProductQuestion, error) {
+	existing, err := s.getVisibleQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID == userID {
+		return nil, fmt.Errorf("%w: cannot upvote your own question", apperrors.ErrActionNotPermitted)
+	}
+
+	if _, err := s.repo.Upvote(ctx, questionID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.reload(ctx, questionID)
+}
+
+func (s *questionServiceImpl) RemoveUpvote(ctx context.Context, userID, questionID uuid.UUID) (*entities.ProductQuestion, error) {
+	if _, err := s.getVisibleQuestion(ctx, questionID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.RemoveUpvote(ctx, questionID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.reload(ctx, questionID)
+}
+
+// Flag dari user yang sama hanya dihitung sekali; admin meninjau pertanyaan dengan flag terbanyak
+func (s *questionServiceImpl) FlagQuestion(ctx context.Context, userID, questionID uuid.UUID, req *models.QuestionFlagRequest) error {
+	reason := strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(reason) > MaxQuestionFlagLength {
+		return fmt.Errorf("%w: reason must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxQuestionFlagLength)
+	}
+
+	if _, err := s.getVisibleQuestion(ctx, questionID); err != nil {
+		return err
+	}
+
+	flagged, err := s.repo.Flag(ctx, questionID, userID, reason)
+	if err != nil {
+		return err
+	}
+
+	if flagged {
+		s.log.WithFields(logrus.Fields{"question_id": questionID, "user_id": userID}).Info("Product question flagged")
+	}
+
+	return nil
+}
+
+// sellerID kosong berarti inbox milik subject sendiri; admin dan staff perlu menyebutkan seller-nya
+func (s *questionServiceImpl) GetSellerInbox(ctx context.Context, subject policies.Subject, sellerID uuid.UUID, limit, offset int) ([]entities.ProductQuestion, error) {
+	if sellerID == uuid.Nil {
+		sellerID = subject.UserID
+	}
+
+	if err := s.policy.Authorize(ctx, subject, policies.ActionAnswerQuestion, policies.Resource{SellerID: sellerID}); err != nil {
+		return nil, err
+	}
+
+	limit, offset = normalizePage(limit, offset, DefaultQuestionLimit, MaxQuestionLimit)
+
+	rows, err := s.repo.GetUnansweredBySellerID(ctx, db.GetUnansweredQuestionsBySellerIDParams{
+		SellerID:  sellerID,
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve seller inbox: %w", err)
+	}
+
+	return toDomainQuestions(rows), nil
+}
+
+func (s *questionServiceImpl) GetFlaggedQuestions(ctx context.Context, subject policies.Subject, status entities.QuestionStatus, limit, offset int) ([]entities.ProductQuestion, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionModerateQuestion, policies.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = entities.QuestionStatusVisible
+	}
+	if status != entities.QuestionStatusVisible && status != entities.QuestionStatusHidden {
+		return nil, fmt.Errorf("%w: unknown question status '%s'", apperrors.ErrInvalidRequestPayload, status)
+	}
+
+	limit, offset = normalizePage(limit, offset, DefaultQuestionLimit, MaxQuestionLimit)
+
+	rows, err := s.repo.GetFlagged(ctx, db.GetFlaggedProductQuestionsParams{
+		Status:    string(status),
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve flagged questions: %w", err)
+	}
+
+	return toDomainQuestions(rows), nil
+}
+
+func (s *questionServiceImpl) ModerateQuestion(ctx context.Context, subject policies.Subject, questionID uuid.UUID, status entities.QuestionStatus) (*entities.ProductQuestion, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionModerateQuestion, policies.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if status != entities.QuestionStatusVisible && status != entities.QuestionStatusHidden {
+		return nil, fmt.Errorf("%w: status must be visible or hidden", apperrors.ErrInvalidRequestPayload)
+	}
+
+	row, err := s.repo.SetStatus(ctx, questionID, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logrus.Fields{"question_id": questionID, "status": status, "moderator_id": subject.UserID}).Info("Product question moderated")
+	return toDomainQuestion(row), nil
+}
+
+// ------- HELPERS -------
+
+// getVisibleQuestion memperlakukan pertanyaan hidden sebagai tidak ada bagi pembeli
+func (s *questionServiceImpl) getVisibleQuestion(ctx context.Context, questionID uuid.UUID) (*db.ProductQuestion, error) {
+	row, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if row.Status != string(entities.QuestionStatusVisible) {
+		return nil, apperrors.ErrQuestionNotFound
+	}
+
+	return row, nil
+}
+
+func (s *questionServiceImpl) reload(ctx context.Context, questionID uuid.UUID) (*entities.ProductQuestion, error) {
+	row, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainQuestion(row), nil
+}
+
+func toDomainQuestion(row *db.ProductQuestion) *entities.ProductQuestion {
+	question := &entities.ProductQuestion{
+		ID:         row.ID,
+		ProductID:  row.ProductID,
+		UserID:     row.UserID,
+		Body:       row.Body,
+		Answer:     row.Answer.String,
+		AnsweredBy: row.AnsweredBy.UUID,
+		Upvotes:    int(row.Upvotes),
+		FlagCount:  int(row.FlagCount),
+		Status:     entities.QuestionStatus(row.Status),
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+	if row.AnsweredAt.Valid {
+		answeredAt := row.AnsweredAt.Time
+		question.AnsweredAt = &answeredAt
+	}
+
+	return question
+}
+
+func toDomainQuestions(rows []db.ProductQuestion) []entities.ProductQuestion {
+	questions := make([]entities.ProductQuestion, 0, len(rows))
+	for i := range rows {
+		questions = append(questions, *toDomainQuestion(&rows[i]))
+	}
+
+	return questions
+}
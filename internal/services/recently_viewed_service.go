@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/metrics"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+type RecentlyViewedService interface {
+	RecordView(ctx context.Context, viewer entities.Viewer, productID uuid.UUID)
+	GetRecentlyViewed(ctx context.Context, viewer entities.Viewer, limit int) ([]entities.Product, error)
+}
+
+type recentlyViewedServiceImpl struct {
+	repo       repositories.RecentlyViewedRepository
+	productSvc ProductService
+	maxLimit   int
+	log        *logrus.Logger
+}
+
+func NewRecentlyViewedService(repo repositories.RecentlyViewedRepository, productSvc ProductService, maxLimit int, log *logrus.Logger) RecentlyViewedService {
+	return &recentlyViewedServiceImpl{
+		repo:       repo,
+		productSvc: productSvc,
+		maxLimit:   maxLimit,
+		log:        log,
+	}
+}
+
+// RecordView tidak mengembalikan error: gagal mencatat riwayat tidak boleh menggagalkan halaman produk.
+// Guest token yang baru dibuat di request ini tidak dicatat: klien tanpa header (crawler, curl) akan mendapat
+// token baru di setiap request dan hanya menumpuk key yang tidak pernah dibaca lagi.
+func (s *recentlyViewedServiceImpl) RecordView(ctx context.Context, viewer entities.Viewer, productID uuid.UUID) {
+	if viewer.IsZero() || viewer.NewGuest {
+		return
+	}
+
+	viewerKind := "user"
+	if viewer.IsGuest() {
+		viewerKind = "guest"
+	}
+	metrics.ProductViewsTotal.WithLabelValues(viewerKind).Inc()
+
+	if err := s.repo.RecordView(ctx, viewer, productID, time.Now()); err != nil {
+		s.log.WithError(err).WithField("product_id", productID).Warn("Failed to record product view")
+	}
+}
+
+// GetRecentlyViewed mengembalikan produk sesuai urutan terakhir dilihat; produk yang sudah dihapus dilewati
+func (s *recentlyViewedServiceImpl) GetRecentlyViewed(ctx context.Context, viewer entities.Viewer, limit int) ([]entities.Product, error) {
+	if viewer.IsZero() {
+		return []entities.Product{}, nil
+	}
+
+	if limit <= 0 || limit > s.maxLimit {
+		limit = s.maxLimit
+	}
+
+	ids, err := s.repo.GetRecent(ctx, viewer, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []entities.Product{}, nil
+	}
+
+	products, err := s.productSvc.GetProductByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]entities.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	// Produk yang sudah dijadikan draft, diarsipkan atau dibanned tidak ditampilkan lagi
+	ordered := make([]entities.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok && p.IsActive() {
+			ordered = append(ordered, p)
+		}
+	}
+
+	return ordered, nil
+}
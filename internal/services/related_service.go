@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/cache"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+const (
+	DefaultRelatedLimit = 10
+	MaxRelatedLimit     = 50
+
+	// maxOrderItemsForCoPurchase membatasi jumlah pasangan (n^2) dari order yang sangat besar
+	maxOrderItemsForCoPurchase = 50
+)
+
+type RelatedService interface {
+	GetRelatedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]entities.RelatedProduct, error)
+	// HandleOrderCreated adalah satu-satunya sumber co-purchase; event yang sama hanya dihitung sekali
+	HandleOrderCreated(ctx context.Context, event models.OrderCreatedEvent) error
+}
+
+type relatedServiceImpl struct {
+	coPurchaseRepo repositories.CoPurchaseRepository
+	productSvc     ProductService
+	cache          *cache.Cache
+	cacheTTL       time.Duration
+	log            *logrus.Logger
+}
+
+func NewRelatedService(coPurchaseRepo repositories.CoPurchaseRepository, productSvc ProductService, cache *cache.Cache, cacheTTL time.Duration, log *logrus.Logger) RelatedService {
+	return &relatedServiceImpl{
+		coPurchaseRepo: coPurchaseRepo,
+		productSvc:     productSvc,
+		cache:          cache,
+		cacheTTL:       cacheTTL,
+		log:            log,
+	}
+}
+
+// GetRelatedProducts menggabungkan produk yang sering dibeli bersama, lalu produk dengan type yang sama,
+// lalu produk dari seller yang sama. Produk yang habis stoknya atau sudah dihapus tidak ikut.
+func (s *relatedServiceImpl) GetRelatedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]entities.RelatedProduct, error) {
+	if limit <= 0 {
+		limit = DefaultRelatedLimit
+	}
+	if limit > MaxRelatedLimit {
+		limit = MaxRelatedLimit
+	}
+
+	// Cache selalu menyimpan MaxRelatedLimit item supaya satu entry bisa melayani semua limit.
+	// Tag setiap produk di dalamnya membuat list dimuat ulang saat salah satunya berubah (mis. stok habis).
+	opts := cache.Options[[]entities.RelatedProduct]{
+		TTL: s.cacheTTL,
+		Tags: func(related []entities.RelatedProduct) []string {
+			tags := make([]string, 0, len(related)+1)
+			tags = append(tags, productTag(productID))
+			for _, r := range related {
+				tags = append(tags, productTag(r.Product.ID))
+			}
+			return tags
+		},
+	}
+
+	related, err := cache.GetOrLoad(ctx, s.cache, relatedProductsCacheKey(productID), opts, func(ctx context.Context) ([]entities.RelatedProduct, error) {
+		return s.loadRelatedProducts(ctx, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(related) > limit {
+		related = related[:limit]
+	}
+
+	return related, nil
+}
+
+func (s *relatedServiceImpl) HandleOrderCreated(ctx context.Context, event models.OrderCreatedEvent) error {
+	logger := s.log.WithField("order_id", event.OrderID)
+
+	productIDs := make([]uuid.UUID, 0, len(event.ProductIDs))
+	seen := make(map[uuid.UUID]struct{}, len(event.ProductIDs))
+	for _, idStr := range event.ProductIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.WithField("product_id", idStr).Warn("Invalid product ID in order event, skipped")
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		productIDs = append(productIDs, id)
+	}
+
+	if len(productIDs) < 2 {
+		return nil
+	}
+
+	if len(productIDs) > maxOrderItemsForCoPurchase {
+		productIDs = productIDs[:maxOrderItemsForCoPurchase]
+	}
+
+	recorded, err := s.coPurchaseRepo.RecordOrder(ctx, event.OrderID, productIDs)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		logger.Info("Order already counted for co-purchases, skipped")
+	}
+
+	return nil
+}
+
+func (s *relatedServiceImpl) loadRelatedProducts(ctx context.Context, productID uuid.UUID) ([]entities.RelatedProduct, error) {
+	base, err := s.productSvc.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsActive() {
+		return nil, apperrors.ErrProductNotFound
+	}
+
+	related := make([]entities.RelatedProduct, 0, MaxRelatedLimit)
+	seen := map[uuid.UUID]struct{}{productID: {}}
+	add := func(products []entities.Product, reason entities.RelatedReason) {
+		for _, p := range products {
+			if len(related) >= MaxRelatedLimit {
+				return
+			}
+			if _, dup := seen[p.ID]; dup || p.Stock <= 0 || !p.IsActive() {
+				continue
+			}
+			seen[p.ID] = struct{}{}
+			related = append(related, entities.RelatedProduct{Product: p, Reason: reason})
+		}
+	}
+
+	coPurchasedIDs, err := s.coPurchaseRepo.GetTop(ctx, productID, MaxRelatedLimit)
+	if err != nil {
+		s.log.WithError(err).WithField("product_id", productID).Warn("Failed to read co-purchases, using fallbacks only")
+	}
+	if len(coPurchasedIDs) > 0 {
+		coPurchased, err := s.productSvc.GetProductByIDs(ctx, coPurchasedIDs)
+		if err != nil {
+			return nil, err
+		}
+		// GetProductByIDs tidak menjamin urutan; urutan hitungan co-purchase dipertahankan
+		byID := make(map[uuid.UUID]entities.Product, len(coPurchased))
+		for _, p := range coPurchased {
+			byID[p.ID] = p
+		}
+		ordered := make([]entities.Product, 0, len(coPurchased))
+		for _, id := range coPurchasedIDs {
+			if p, ok := byID[id]; ok {
+				ordered = append(ordered, p)
+			}
+		}
+		add(ordered, entities.RelatedReasonCoPurchase)
+	}
+
+	if len(related) < MaxRelatedLimit && base.Type != "" {
+		sameType, err := s.productSvc.GetProductsByType(ctx, base.Type)
+		if err != nil {
+			return nil, err
+		}
+		add(sameType, entities.RelatedReasonSameType)
+	}
+
+	if len(related) < MaxRelatedLimit {
+		sameSeller, err := s.productSvc.GetProductsBySellerID(ctx, base.SellerID)
+		if err != nil {
+			return nil, err
+		}
+		add(sameSeller, entities.RelatedReasonSameSeller)
+	}
+
+	return related, nil
+}
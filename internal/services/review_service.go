@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/helpers"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/models"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+const (
+	MaxReviewTitleLength = 150
+	MaxReviewBodyLength  = 5000
+	MaxReviewReplyLength = 2000
+
+	DefaultReviewLimit = 20
+	MaxReviewLimit     = 100
+)
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, userID, productID uuid.UUID, req *models.ReviewRequest) (*entities.ProductReview, error)
+	UpdateReview(ctx context.Context, userID, reviewID uuid.UUID, req *models.ReviewRequest) (*entities.ProductReview, error)
+	DeleteReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID) error
+	ReplyToReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID, req *models.ReviewReplyRequest) (*entities.ProductReview, error)
+	ModerateReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID, status entities.ReviewStatus) (*entities.ProductReview, error)
+	GetProductReviews(ctx context.Context, productID uuid.UUID, limit, offset int) ([]entities.ProductReview, error)
+	GetReviewsByStatus(ctx context.Context, subject policies.Subject, status entities.ReviewStatus, limit, offset int) ([]entities.ProductReview, error)
+	// HandleOrderCreated mencatat produk yang dibeli user sebagai dasar flag verified purchase
+	HandleOrderCreated(ctx context.Context, event models.OrderCreatedEvent) error
+}
+
+type reviewServiceImpl struct {
+	repo       repositories.ReviewRepository
+	productSvc ProductService
+	policy     policies.ProductPolicy
+	log        *logrus.Logger
+}
+
+func NewReviewService(repo repositories.ReviewRepository, productSvc ProductService, policy policies.ProductPolicy, log *logrus.Logger) ReviewService {
+	return &reviewServiceImpl{
+		repo:       repo,
+		productSvc: productSvc,
+		policy:     policy,
+		log:        log,
+	}
+}
+
+// Review baru selalu menunggu moderasi dan belum dihitung ke rating produk
+func (s *reviewServiceImpl) CreateReview(ctx context.Context, userID, productID uuid.UUID, req *models.ReviewRequest) (*entities.ProductReview, error) {
+	if err := validateReviewRequest(req); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productSvc.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID == userID {
+		return nil, fmt.Errorf("%w: sellers cannot review their own products", apperrors.ErrActionNotPermitted)
+	}
+
+	purchased, err := s.repo.HasPurchased(ctx, userID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to resolve verified purchase: %w", err)
+	}
+
+	row, err := s.repo.CreateReview(ctx, db.InsertProductReviewParams{
+		ID:               helpers.GenerateNewID(),
+		ProductID:        productID,
+		UserID:           userID,
+		Rating:           int16(req.Rating),
+		Title:            strings.TrimSpace(req.Title),
+		Body:             strings.TrimSpace(req.Body),
+		VerifiedPurchase: purchased,
+		Status:           string(entities.ReviewStatusPending),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainReview(row), nil
+}
+
+// Review yang diubah kembali ke status pending karena isinya perlu dimoderasi ulang
+func (s *reviewServiceImpl) UpdateReview(ctx context.Context, userID, reviewID uuid.UUID, req *models.ReviewRequest) (*entities.ProductReview, error) {
+	if err := validateReviewRequest(req); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != userID {
+		return nil, fmt.Errorf("%w: only the author can edit a review", apperrors.ErrActionNotPermitted)
+	}
+
+	row, product, err := s.repo.UpdateReview(ctx, db.UpdateProductReviewParams{
+		ID:     reviewID,
+		Rating: int16(req.Rating),
+		Title:  strings.TrimSpace(req.Title),
+		Body:   strings.TrimSpace(req.Body),
+		Status: string(entities.ReviewStatusPending),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Status == string(entities.ReviewStatusApproved) {
+		s.invalidateProduct(ctx, product)
+	}
+
+	return toDomainReview(row), nil
+}
+
+// Penulis review boleh menghapus review-nya sendiri; admin boleh menghapus review apa pun
+func (s *reviewServiceImpl) DeleteReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID) error {
+	existing, err := s.repo.GetReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if existing.UserID != subject.UserID {
+		if err := s.policy.Authorize(ctx, subject, policies.ActionModerateReview, policies.Resource{}); err != nil {
+			return err
+		}
+	}
+
+	product, err := s.repo.DeleteReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status == string(entities.ReviewStatusApproved) {
+		s.invalidateProduct(ctx, product)
+	}
+
+	return nil
+}
+
+// Balasan hanya boleh dari pemilik produk (atau staff-nya); balasan kosong menghapus balasan lama
+func (s *reviewServiceImpl) ReplyToReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID, req *models.ReviewReplyRequest) (*entities.ProductReview, error) {
+	reply := strings.TrimSpace(req.Reply)
+	if utf8.RuneCountInString(reply) > MaxReviewReplyLength {
+		return nil, fmt.Errorf("%w: reply must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxReviewReplyLength)
+	}
+
+	existing, err := s.repo.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productSvc.GetProductByID(ctx, existing.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.Authorize(ctx, subject, policies.ActionReplyReview, policies.Resource{SellerID: product.SellerID}); err != nil {
+		return nil, err
+	}
+
+	row, err := s.repo.SetReply(ctx, reviewID, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainReview(row), nil
+}
+
+func (s *reviewServiceImpl) ModerateReview(ctx context.Context, subject policies.Subject, reviewID uuid.UUID, status entities.ReviewStatus) (*entities.ProductReview, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionModerateReview, policies.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if status != entities.ReviewStatusApproved && status != entities.ReviewStatusRejected {
+		return nil, fmt.Errorf("%w: status must be approved or rejected", apperrors.ErrInvalidRequestPayload)
+	}
+
+	existing, err := s.repo.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, product, err := s.repo.SetStatus(ctx, reviewID, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	// Agregat rating hanya berubah jika review masuk atau keluar dari status approved
+	if (existing.Status == string(entities.ReviewStatusApproved)) != (status == entities.ReviewStatusApproved) {
+		s.invalidateProduct(ctx, product)
+	}
+
+	s.log.WithFields(logrus.Fields{"review_id": reviewID, "status": status, "moderator_id": subject.UserID}).Info("Product review moderated")
+	return toDomainReview(row), nil
+}
+
+func (s *reviewServiceImpl) GetProductReviews(ctx context.Context, productID uuid.UUID, limit, offset int) ([]entities.ProductReview, error) {
+	limit, offset = normalizePage(limit, offset, DefaultReviewLimit, MaxReviewLimit)
+
+	rows, err := s.repo.GetApprovedReviews(ctx, productID, int32(limit), int32(offset))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve product reviews: %w", err)
+	}
+
+	return toDomainReviews(rows), nil
+}
+
+func (s *reviewServiceImpl) GetReviewsByStatus(ctx context.Context, subject policies.Subject, status entities.ReviewStatus, limit, offset int) ([]entities.ProductReview, error) {
+	if err := s.policy.Authorize(ctx, subject, policies.ActionModerateReview, policies.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = entities.ReviewStatusPending
+	}
+	switch status {
+	case entities.ReviewStatusPending, entities.ReviewStatusApproved, entities.ReviewStatusRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown review status '%s'", apperrors.ErrInvalidRequestPayload, status)
+	}
+
+	limit, offset = normalizePage(limit, offset, DefaultReviewLimit, MaxReviewLimit)
+
+	rows, err := s.repo.GetReviewsByStatus(ctx, string(status), int32(limit), int32(offset))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve reviews by status: %w", err)
+	}
+
+	return toDomainReviews(rows), nil
+}
+
+func (s *reviewServiceImpl) HandleOrderCreated(ctx context.Context, event models.OrderCreatedEvent) error {
+	logger := s.log.WithField("order_id", event.OrderID)
+
+	userID, err := uuid.Parse(event.UserID)
+	if err != nil {
+		logger.WithField("user_id", event.UserID).Warn("Invalid user ID in order event, purchases not recorded")
+		return nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(event.ProductIDs))
+	for _, idStr := range event.ProductIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.WithField("product_id", idStr).Warn("Invalid product ID in order event, skipped")
+			continue
+		}
+		productIDs = append(productIDs, id)
+	}
+	productIDs = uniqueUUIDs(productIDs)
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	purchasedAt := event.OrderDate
+	if purchasedAt.IsZero() {
+		purchasedAt = time.Now()
+	}
+
+	// Insert bersifat idempoten sehingga event yang dikirim ulang aman diproses lagi
+	return s.repo.RecordPurchases(ctx, userID, event.OrderID, productIDs, purchasedAt)
+}
+
+// ------- HELPERS -------
+
+func (s *reviewServiceImpl) invalidateProduct(ctx context.Context, product *db.Product) {
+	if product == nil {
+		return
+	}
+
+	s.productSvc.InvalidateCachesAfterUpdate(ctx, []*entities.Product{toDomainProduct(product)})
+}
+
+func validateReviewRequest(req *models.ReviewRequest) error {
+	if req.Rating < 1 || req.Rating > 5 {
+		return fmt.Errorf("%w: rating must be between 1 and 5", apperrors.ErrInvalidRequestPayload)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" || utf8.RuneCountInString(title) > MaxReviewTitleLength {
+		return fmt.Errorf("%w: title is required and must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxReviewTitleLength)
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > MaxReviewBodyLength {
+		return fmt.Errorf("%w: body is required and must be at most %d characters", apperrors.ErrInvalidRequestPayload, MaxReviewBodyLength)
+	}
+
+	return nil
+}
+
+// normalizePage mengisi limit default dan membatasi limit serta offset dari query string
+func normalizePage(limit, offset, defaultLimit, maxLimit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+func toDomainReview(row *db.ProductReview) *entities.ProductReview {
+	review := &entities.ProductReview{
+		ID:               row.ID,
+		ProductID:        row.ProductID,
+		UserID:           row.UserID,
+		Rating:           int(row.Rating),
+		Title:            row.Title,
+		Body:             row.Body,
+		VerifiedPurchase: row.VerifiedPurchase,
+		Status:           entities.ReviewStatus(row.Status),
+		SellerReply:      row.SellerReply.String,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+	if row.SellerRepliedAt.Valid {
+		repliedAt := row.SellerRepliedAt.Time
+		review.SellerRepliedAt = &repliedAt
+	}
+
+	return review
+}
+
+func toDomainReviews(rows []db.ProductReview) []entities.ProductReview {
+	reviews := make([]entities.ProductReview, 0, len(rows))
+	for i := range rows {
+		reviews = append(reviews, *toDomainReview(&rows[i]))
+	}
+
+	return reviews
+}
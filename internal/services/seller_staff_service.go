@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/db"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/policies"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+type SellerStaffService interface {
+	AddStaff(ctx context.Context, subject policies.Subject, userID uuid.UUID) (*entities.SellerStaff, error)
+	RemoveStaff(ctx context.Context, subject policies.Subject, userID uuid.UUID) error
+	GetStaff(ctx context.Context, subject policies.Subject) ([]entities.SellerStaff, error)
+}
+
+type sellerStaffServiceImpl struct {
+	staffRepo repositories.SellerStaffRepository
+	log       *logrus.Logger
+}
+
+func NewSellerStaffService(staffRepo repositories.SellerStaffRepository, log *logrus.Logger) SellerStaffService {
+	return &sellerStaffServiceImpl{
+		staffRepo: staffRepo,
+		log:       log,
+	}
+}
+
+// Staff selalu didaftarkan ke toko milik seller yang sedang login
+func (s *sellerStaffServiceImpl) AddStaff(ctx context.Context, subject policies.Subject, userID uuid.UUID) (*entities.SellerStaff, error) {
+	if subject.Role != policies.RoleSeller {
+		return nil, apperrors.ErrActionNotPermitted
+	}
+
+	if userID == uuid.Nil || userID == subject.UserID {
+		return nil, fmt.Errorf("%w: a seller cannot be registered as their own staff", apperrors.ErrInvalidRequestPayload)
+	}
+
+	row, err := s.staffRepo.AddStaff(ctx, subject.UserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to add seller staff: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"seller_id": subject.UserID, "user_id": userID}).Info("Seller staff added")
+	return toDomainSellerStaff(row), nil
+}
+
+func (s *sellerStaffServiceImpl) RemoveStaff(ctx context.Context, subject policies.Subject, userID uuid.UUID) error {
+	if subject.Role != policies.RoleSeller {
+		return apperrors.ErrActionNotPermitted
+	}
+
+	removed, err := s.staffRepo.RemoveStaff(ctx, subject.UserID, userID)
+	if err != nil {
+		return fmt.Errorf("service: failed to remove seller staff: %w", err)
+	}
+
+	if !removed {
+		return apperrors.ErrSellerStaffNotFound
+	}
+
+	return nil
+}
+
+func (s *sellerStaffServiceImpl) GetStaff(ctx context.Context, subject policies.Subject) ([]entities.SellerStaff, error) {
+	rows, err := s.staffRepo.GetStaff(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to retrieve seller staff: %w", err)
+	}
+
+	staff := make([]entities.SellerStaff, 0, len(rows))
+	for i := range rows {
+		staff = append(staff, *toDomainSellerStaff(&rows[i]))
+	}
+
+	return staff, nil
+}
+
+func toDomainSellerStaff(row *db.SellerStaff) *entities.SellerStaff {
+	return &entities.SellerStaff{
+		SellerID:  row.SellerID,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/entities"
+	apperrors "github.com/RehanAthallahAzhar/shopeezy-catalog/internal/pkg/errors"
+	"github.com/RehanAthallahAzhar/shopeezy-catalog/internal/repositories"
+)
+
+const (
+	DefaultTrendingWindow = "24h"
+	DefaultTrendingLimit  = 20
+	MaxTrendingLimit      = 100
+)
+
+// Bobot sinyal: pembelian lebih bernilai daripada add-to-cart, dan add-to-cart lebih bernilai daripada view
+var trendingSignalWeights = map[entities.TrendingSignal]float64{
+	entities.TrendingSignalView:      1,
+	entities.TrendingSignalAddToCart: 3,
+	entities.TrendingSignalPurchase:  5,
+}
+
+// trendingWindow menentukan rentang bucket yang dihitung dan seberapa cepat sinyal lama meluruh
+type trendingWindow struct {
+	span     time.Duration
+	halfLife time.Duration
+}
+
+var trendingWindows = map[string]trendingWindow{
+	"24h": {span: 24 * time.Hour, halfLife: 6 * time.Hour},
+	"7d":  {span: 7 * 24 * time.Hour, halfLife: 24 * time.Hour},
+	"30d": {span: 30 * 24 * time.Hour, halfLife: 7 * 24 * time.Hour},
+}
+
+// TrendingRetention adalah umur bucket sinyal mentah, sepanjang window terpanjang
+const TrendingRetention = 30 * 24 * time.Hour
+
+type TrendingService interface {
+	// RecordSignal tidak mengembalikan error: gagal mencatat sinyal tidak boleh menggagalkan request utama
+	RecordSignal(ctx context.Context, productID uuid.UUID, productType string, signal entities.TrendingSignal, quantity int)
+	GetTrending(ctx context.Context, window, productType string, limit int) ([]entities.TrendingProduct, error)
+	CompactAll(ctx context.Context) error
+	// RunCompaction menjalankan CompactAll setiap interval sampai ctx selesai
+	RunCompaction(ctx context.Context, interval time.Duration)
+}
+
+type trendingServiceImpl struct {
+	repo       repositories.TrendingRepository
+	productSvc ProductService
+	log        *logrus.Logger
+}
+
+func NewTrendingService(repo repositories.TrendingRepository, productSvc ProductService, log *logrus.Logger) TrendingService {
+	return &trendingServiceImpl{
+		repo:       repo,
+		productSvc: productSvc,
+		log:        log,
+	}
+}
+
+func (s *trendingServiceImpl) RecordSignal(ctx context.Context, productID uuid.UUID, productType string, signal entities.TrendingSignal, quantity int) {
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	weight := trendingSignalWeights[signal] * float64(quantity)
+	if err := s.repo.RecordSignal(ctx, productID, productType, weight, time.Now()); err != nil {
+		s.log.WithError(err).WithFields(logrus.Fields{"product_id": productID, "signal": signal}).Warn("Failed to record trending signal")
+	}
+}
+
+func (s *trendingServiceImpl) GetTrending(ctx context.Context, window, productType string, limit int) ([]entities.TrendingProduct, error) {
+	if window == "" {
+		window = DefaultTrendingWindow
+	}
+	if _, ok := trendingWindows[window]; !ok {
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrUnknownTrendingWindow, window)
+	}
+
+	if limit <= 0 {
+		limit = DefaultTrendingLimit
+	}
+	if limit > MaxTrendingLimit {
+		limit = MaxTrendingLimit
+	}
+
+	scores, err := s.repo.GetTop(ctx, window, productType, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skor belum pernah dirangkum (mis. job belum jalan sejak start), hitung langsung untuk scope ini.
+	// Type dari query hanya dihitung jika memang pernah menerima sinyal, supaya type sembarang
+	// tidak memicu ZUNIONSTORE atas ratusan bucket di setiap request publik; sisanya ditangani RunCompaction.
+	if len(scores) == 0 {
+		known, err := s.isKnownType(ctx, productType)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			return []entities.TrendingProduct{}, nil
+		}
+
+		if err := s.compact(ctx, window, productType, time.Now()); err != nil {
+			return nil, err
+		}
+		if scores, err = s.repo.GetTop(ctx, window, productType, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(scores) == 0 {
+		return []entities.TrendingProduct{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(scores))
+	for i, score := range scores {
+		ids[i] = score.ProductID
+	}
+
+	products, err := s.productSvc.GetProductByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]entities.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	// Produk yang sudah dihapus atau tidak active dilewati, urutan skor dipertahankan
+	trending := make([]entities.TrendingProduct, 0, len(scores))
+	for _, score := range scores {
+		if p, ok := byID[score.ProductID]; ok && p.IsActive() {
+			trending = append(trending, entities.TrendingProduct{Product: p, Score: score.Score})
+		}
+	}
+
+	return trending, nil
+}
+
+func (s *trendingServiceImpl) CompactAll(ctx context.Context) error {
+	types, err := s.repo.GetTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	scopes := append([]string{""}, types...)
+	for window := range trendingWindows {
+		for _, productType := range scopes {
+			if err := s.compact(ctx, window, productType, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *trendingServiceImpl) RunCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CompactAll(ctx); err != nil && ctx.Err() == nil {
+			s.log.WithError(err).Warn("Failed to compact trending scores")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *trendingServiceImpl) isKnownType(ctx context.Context, productType string) (bool, error) {
+	if productType == "" {
+		return true, nil
+	}
+
+	return s.repo.HasType(ctx, productType)
+}
+
+// compact memberi bobot 0.5^(umur/halfLife) untuk setiap bucket dalam window, diukur dari tengah bucket
+func (s *trendingServiceImpl) compact(ctx context.Context, window, productType string, now time.Time) error {
+	w := trendingWindows[window]
+	current := now.Truncate(repositories.TrendingBucketSize)
+	count := int(w.span / repositories.TrendingBucketSize)
+
+	buckets := make([]time.Time, 0, count)
+	weights := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		bucket := current.Add(-time.Duration(i) * repositories.TrendingBucketSize)
+		age := now.Sub(bucket.Add(repositories.TrendingBucketSize / 2))
+		if age < 0 {
+			age = 0
+		}
+
+		buckets = append(buckets, bucket)
+		weights = append(weights, math.Pow(0.5, age.Hours()/w.halfLife.Hours()))
+	}
+
+	return s.repo.Compact(ctx, window, productType, buckets, weights)
+}